@@ -0,0 +1,1391 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
+// source: api/packs/v1/packs.proto
+
+// The gRPC API of the order packs calculator. It manages the available packs and calculates packs for orders
+// with the same service as the REST API.
+
+package packsv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// ShipmentMode chooses whether an order may be overshipped or undershipped
+type ShipmentMode int32
+
+const (
+	// SHIPMENT_MODE_UNSPECIFIED ships in the overship mode
+	ShipmentMode_SHIPMENT_MODE_UNSPECIFIED ShipmentMode = 0
+	// SHIPMENT_MODE_OVERSHIP ships at least the order size
+	ShipmentMode_SHIPMENT_MODE_OVERSHIP ShipmentMode = 1
+	// SHIPMENT_MODE_UNDERSHIP ships at most the order size and backorders the rest
+	ShipmentMode_SHIPMENT_MODE_UNDERSHIP ShipmentMode = 2
+)
+
+// Enum value maps for ShipmentMode.
+var (
+	ShipmentMode_name = map[int32]string{
+		0: "SHIPMENT_MODE_UNSPECIFIED",
+		1: "SHIPMENT_MODE_OVERSHIP",
+		2: "SHIPMENT_MODE_UNDERSHIP",
+	}
+	ShipmentMode_value = map[string]int32{
+		"SHIPMENT_MODE_UNSPECIFIED": 0,
+		"SHIPMENT_MODE_OVERSHIP":    1,
+		"SHIPMENT_MODE_UNDERSHIP":   2,
+	}
+)
+
+func (x ShipmentMode) Enum() *ShipmentMode {
+	p := new(ShipmentMode)
+	*p = x
+	return p
+}
+
+func (x ShipmentMode) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ShipmentMode) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_packs_v1_packs_proto_enumTypes[0].Descriptor()
+}
+
+func (ShipmentMode) Type() protoreflect.EnumType {
+	return &file_api_packs_v1_packs_proto_enumTypes[0]
+}
+
+func (x ShipmentMode) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ShipmentMode.Descriptor instead.
+func (ShipmentMode) EnumDescriptor() ([]byte, []int) {
+	return file_api_packs_v1_packs_proto_rawDescGZIP(), []int{0}
+}
+
+// Pack is a pack size with its optional properties
+type Pack struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// size is the number of items in the pack
+	Size int64 `protobuf:"varint,1,opt,name=size,proto3" json:"size,omitempty"`
+	// cost is the cost of a single pack in minor currency units, e.g. cents
+	Cost int64 `protobuf:"varint,2,opt,name=cost,proto3" json:"cost,omitempty"`
+	// min_count is the number of packs of the size every combination must hold
+	MinCount int64 `protobuf:"varint,3,opt,name=min_count,json=minCount,proto3" json:"min_count,omitempty"`
+	// max_count is the largest number of packs of the size a combination can hold, zero means no limit
+	MaxCount int64 `protobuf:"varint,4,opt,name=max_count,json=maxCount,proto3" json:"max_count,omitempty"`
+	// weight is the weight of a single pack, e.g. in grams
+	Weight int64 `protobuf:"varint,5,opt,name=weight,proto3" json:"weight,omitempty"`
+	// volume is the volume of a single pack, e.g. in cubic centimetres
+	Volume int64 `protobuf:"varint,6,opt,name=volume,proto3" json:"volume,omitempty"`
+	// content is the decimal amount of a product measured in kilograms or litres in a single pack, e.g. "0.5"
+	Content       string `protobuf:"bytes,7,opt,name=content,proto3" json:"content,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Pack) Reset() {
+	*x = Pack{}
+	mi := &file_api_packs_v1_packs_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Pack) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Pack) ProtoMessage() {}
+
+func (x *Pack) ProtoReflect() protoreflect.Message {
+	mi := &file_api_packs_v1_packs_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Pack.ProtoReflect.Descriptor instead.
+func (*Pack) Descriptor() ([]byte, []int) {
+	return file_api_packs_v1_packs_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Pack) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *Pack) GetCost() int64 {
+	if x != nil {
+		return x.Cost
+	}
+	return 0
+}
+
+func (x *Pack) GetMinCount() int64 {
+	if x != nil {
+		return x.MinCount
+	}
+	return 0
+}
+
+func (x *Pack) GetMaxCount() int64 {
+	if x != nil {
+		return x.MaxCount
+	}
+	return 0
+}
+
+func (x *Pack) GetWeight() int64 {
+	if x != nil {
+		return x.Weight
+	}
+	return 0
+}
+
+func (x *Pack) GetVolume() int64 {
+	if x != nil {
+		return x.Volume
+	}
+	return 0
+}
+
+func (x *Pack) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+// PackCount is the number of packs of a size
+type PackCount struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// size is the pack size
+	Size int64 `protobuf:"varint,1,opt,name=size,proto3" json:"size,omitempty"`
+	// count is the number of packs
+	Count         int64 `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PackCount) Reset() {
+	*x = PackCount{}
+	mi := &file_api_packs_v1_packs_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PackCount) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PackCount) ProtoMessage() {}
+
+func (x *PackCount) ProtoReflect() protoreflect.Message {
+	mi := &file_api_packs_v1_packs_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PackCount.ProtoReflect.Descriptor instead.
+func (*PackCount) Descriptor() ([]byte, []int) {
+	return file_api_packs_v1_packs_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *PackCount) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *PackCount) GetCount() int64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+// OrderLine is a line of a multi-line order
+type OrderLine struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// catalog is the ID of the catalog of the ordered product, the available packs are used if it is empty
+	Catalog string `protobuf:"bytes,1,opt,name=catalog,proto3" json:"catalog,omitempty"`
+	// quantity is the number of ordered items of a product counted in items
+	Quantity int64 `protobuf:"varint,2,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	// amount is the decimal ordered amount of a product measured in kilograms or litres, e.g. "12.5"
+	Amount        string `protobuf:"bytes,3,opt,name=amount,proto3" json:"amount,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrderLine) Reset() {
+	*x = OrderLine{}
+	mi := &file_api_packs_v1_packs_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderLine) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderLine) ProtoMessage() {}
+
+func (x *OrderLine) ProtoReflect() protoreflect.Message {
+	mi := &file_api_packs_v1_packs_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderLine.ProtoReflect.Descriptor instead.
+func (*OrderLine) Descriptor() ([]byte, []int) {
+	return file_api_packs_v1_packs_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *OrderLine) GetCatalog() string {
+	if x != nil {
+		return x.Catalog
+	}
+	return ""
+}
+
+func (x *OrderLine) GetQuantity() int64 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+func (x *OrderLine) GetAmount() string {
+	if x != nil {
+		return x.Amount
+	}
+	return ""
+}
+
+type GetPacksRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPacksRequest) Reset() {
+	*x = GetPacksRequest{}
+	mi := &file_api_packs_v1_packs_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPacksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPacksRequest) ProtoMessage() {}
+
+func (x *GetPacksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_packs_v1_packs_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPacksRequest.ProtoReflect.Descriptor instead.
+func (*GetPacksRequest) Descriptor() ([]byte, []int) {
+	return file_api_packs_v1_packs_proto_rawDescGZIP(), []int{3}
+}
+
+type GetPacksResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// packs are the available packs sorted by size
+	Packs         []*Pack `protobuf:"bytes,1,rep,name=packs,proto3" json:"packs,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPacksResponse) Reset() {
+	*x = GetPacksResponse{}
+	mi := &file_api_packs_v1_packs_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPacksResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPacksResponse) ProtoMessage() {}
+
+func (x *GetPacksResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_packs_v1_packs_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPacksResponse.ProtoReflect.Descriptor instead.
+func (*GetPacksResponse) Descriptor() ([]byte, []int) {
+	return file_api_packs_v1_packs_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GetPacksResponse) GetPacks() []*Pack {
+	if x != nil {
+		return x.Packs
+	}
+	return nil
+}
+
+type AddPackRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// pack is the pack to add
+	Pack          *Pack `protobuf:"bytes,1,opt,name=pack,proto3" json:"pack,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddPackRequest) Reset() {
+	*x = AddPackRequest{}
+	mi := &file_api_packs_v1_packs_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddPackRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddPackRequest) ProtoMessage() {}
+
+func (x *AddPackRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_packs_v1_packs_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddPackRequest.ProtoReflect.Descriptor instead.
+func (*AddPackRequest) Descriptor() ([]byte, []int) {
+	return file_api_packs_v1_packs_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *AddPackRequest) GetPack() *Pack {
+	if x != nil {
+		return x.Pack
+	}
+	return nil
+}
+
+type AddPackResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddPackResponse) Reset() {
+	*x = AddPackResponse{}
+	mi := &file_api_packs_v1_packs_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddPackResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddPackResponse) ProtoMessage() {}
+
+func (x *AddPackResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_packs_v1_packs_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddPackResponse.ProtoReflect.Descriptor instead.
+func (*AddPackResponse) Descriptor() ([]byte, []int) {
+	return file_api_packs_v1_packs_proto_rawDescGZIP(), []int{6}
+}
+
+type RemovePackRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// size is the size of the pack to remove
+	Size          int64 `protobuf:"varint,1,opt,name=size,proto3" json:"size,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemovePackRequest) Reset() {
+	*x = RemovePackRequest{}
+	mi := &file_api_packs_v1_packs_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemovePackRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemovePackRequest) ProtoMessage() {}
+
+func (x *RemovePackRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_packs_v1_packs_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemovePackRequest.ProtoReflect.Descriptor instead.
+func (*RemovePackRequest) Descriptor() ([]byte, []int) {
+	return file_api_packs_v1_packs_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *RemovePackRequest) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+type RemovePackResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemovePackResponse) Reset() {
+	*x = RemovePackResponse{}
+	mi := &file_api_packs_v1_packs_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemovePackResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemovePackResponse) ProtoMessage() {}
+
+func (x *RemovePackResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_packs_v1_packs_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemovePackResponse.ProtoReflect.Descriptor instead.
+func (*RemovePackResponse) Descriptor() ([]byte, []int) {
+	return file_api_packs_v1_packs_proto_rawDescGZIP(), []int{8}
+}
+
+type ReplacePacksRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// packs are the new available packs
+	Packs         []*Pack `protobuf:"bytes,1,rep,name=packs,proto3" json:"packs,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReplacePacksRequest) Reset() {
+	*x = ReplacePacksRequest{}
+	mi := &file_api_packs_v1_packs_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReplacePacksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReplacePacksRequest) ProtoMessage() {}
+
+func (x *ReplacePacksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_packs_v1_packs_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReplacePacksRequest.ProtoReflect.Descriptor instead.
+func (*ReplacePacksRequest) Descriptor() ([]byte, []int) {
+	return file_api_packs_v1_packs_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ReplacePacksRequest) GetPacks() []*Pack {
+	if x != nil {
+		return x.Packs
+	}
+	return nil
+}
+
+type ReplacePacksResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReplacePacksResponse) Reset() {
+	*x = ReplacePacksResponse{}
+	mi := &file_api_packs_v1_packs_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReplacePacksResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReplacePacksResponse) ProtoMessage() {}
+
+func (x *ReplacePacksResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_packs_v1_packs_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReplacePacksResponse.ProtoReflect.Descriptor instead.
+func (*ReplacePacksResponse) Descriptor() ([]byte, []int) {
+	return file_api_packs_v1_packs_proto_rawDescGZIP(), []int{10}
+}
+
+type CalculateRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// order_size is the size of a single-line order of the available packs, used if there are no lines
+	OrderSize int64 `protobuf:"varint,1,opt,name=order_size,json=orderSize,proto3" json:"order_size,omitempty"`
+	// lines are the lines of a multi-line order
+	Lines []*OrderLine `protobuf:"bytes,2,rep,name=lines,proto3" json:"lines,omitempty"`
+	// mode chooses whether the order may be overshipped or undershipped
+	Mode          ShipmentMode `protobuf:"varint,3,opt,name=mode,proto3,enum=packs.v1.ShipmentMode" json:"mode,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CalculateRequest) Reset() {
+	*x = CalculateRequest{}
+	mi := &file_api_packs_v1_packs_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CalculateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CalculateRequest) ProtoMessage() {}
+
+func (x *CalculateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_packs_v1_packs_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CalculateRequest.ProtoReflect.Descriptor instead.
+func (*CalculateRequest) Descriptor() ([]byte, []int) {
+	return file_api_packs_v1_packs_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *CalculateRequest) GetOrderSize() int64 {
+	if x != nil {
+		return x.OrderSize
+	}
+	return 0
+}
+
+func (x *CalculateRequest) GetLines() []*OrderLine {
+	if x != nil {
+		return x.Lines
+	}
+	return nil
+}
+
+func (x *CalculateRequest) GetMode() ShipmentMode {
+	if x != nil {
+		return x.Mode
+	}
+	return ShipmentMode_SHIPMENT_MODE_UNSPECIFIED
+}
+
+// LineResult is the packing plan of an order line
+type LineResult struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// catalog is the ID of the catalog of the ordered product
+	Catalog string `protobuf:"bytes,1,opt,name=catalog,proto3" json:"catalog,omitempty"`
+	// quantity is the number of ordered items
+	Quantity int64 `protobuf:"varint,2,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	// unit is the unit of measure of the catalog, "item", "kg" or "l"
+	Unit string `protobuf:"bytes,3,opt,name=unit,proto3" json:"unit,omitempty"`
+	// amount is the decimal ordered amount of a product measured in kilograms or litres
+	Amount string `protobuf:"bytes,4,opt,name=amount,proto3" json:"amount,omitempty"`
+	// shipped_amount is the decimal total amount in the packs of a product measured in kilograms or litres
+	ShippedAmount string `protobuf:"bytes,5,opt,name=shipped_amount,json=shippedAmount,proto3" json:"shipped_amount,omitempty"`
+	// packs are the packs shipped for the line sorted by size
+	Packs []*PackCount `protobuf:"bytes,6,rep,name=packs,proto3" json:"packs,omitempty"`
+	// shipped_items is the total number of items in the packs
+	ShippedItems int64 `protobuf:"varint,7,opt,name=shipped_items,json=shippedItems,proto3" json:"shipped_items,omitempty"`
+	// overshipment is the number of items shipped above the order size
+	Overshipment int64 `protobuf:"varint,8,opt,name=overshipment,proto3" json:"overshipment,omitempty"`
+	// backorder is the number of items shipped below the order size
+	Backorder int64 `protobuf:"varint,9,opt,name=backorder,proto3" json:"backorder,omitempty"`
+	// pack_count is the total number of packs
+	PackCount int64 `protobuf:"varint,10,opt,name=pack_count,json=packCount,proto3" json:"pack_count,omitempty"`
+	// cost is the total cost of the packs in minor currency units
+	Cost int64 `protobuf:"varint,11,opt,name=cost,proto3" json:"cost,omitempty"`
+	// tolerance_exceeded reports whether the overshipment of the line exceeds the tolerance of its catalog
+	ToleranceExceeded bool `protobuf:"varint,12,opt,name=tolerance_exceeded,json=toleranceExceeded,proto3" json:"tolerance_exceeded,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *LineResult) Reset() {
+	*x = LineResult{}
+	mi := &file_api_packs_v1_packs_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LineResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LineResult) ProtoMessage() {}
+
+func (x *LineResult) ProtoReflect() protoreflect.Message {
+	mi := &file_api_packs_v1_packs_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LineResult.ProtoReflect.Descriptor instead.
+func (*LineResult) Descriptor() ([]byte, []int) {
+	return file_api_packs_v1_packs_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *LineResult) GetCatalog() string {
+	if x != nil {
+		return x.Catalog
+	}
+	return ""
+}
+
+func (x *LineResult) GetQuantity() int64 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+func (x *LineResult) GetUnit() string {
+	if x != nil {
+		return x.Unit
+	}
+	return ""
+}
+
+func (x *LineResult) GetAmount() string {
+	if x != nil {
+		return x.Amount
+	}
+	return ""
+}
+
+func (x *LineResult) GetShippedAmount() string {
+	if x != nil {
+		return x.ShippedAmount
+	}
+	return ""
+}
+
+func (x *LineResult) GetPacks() []*PackCount {
+	if x != nil {
+		return x.Packs
+	}
+	return nil
+}
+
+func (x *LineResult) GetShippedItems() int64 {
+	if x != nil {
+		return x.ShippedItems
+	}
+	return 0
+}
+
+func (x *LineResult) GetOvershipment() int64 {
+	if x != nil {
+		return x.Overshipment
+	}
+	return 0
+}
+
+func (x *LineResult) GetBackorder() int64 {
+	if x != nil {
+		return x.Backorder
+	}
+	return 0
+}
+
+func (x *LineResult) GetPackCount() int64 {
+	if x != nil {
+		return x.PackCount
+	}
+	return 0
+}
+
+func (x *LineResult) GetCost() int64 {
+	if x != nil {
+		return x.Cost
+	}
+	return 0
+}
+
+func (x *LineResult) GetToleranceExceeded() bool {
+	if x != nil {
+		return x.ToleranceExceeded
+	}
+	return false
+}
+
+// ShipmentTotals sums up the packing plans of the lines of a multi-line order
+type ShipmentTotals struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// shipped_items is the total number of items shipped
+	ShippedItems int64 `protobuf:"varint,1,opt,name=shipped_items,json=shippedItems,proto3" json:"shipped_items,omitempty"`
+	// overshipment is the total number of items shipped above the order sizes
+	Overshipment int64 `protobuf:"varint,2,opt,name=overshipment,proto3" json:"overshipment,omitempty"`
+	// backorder is the total number of items shipped below the order sizes
+	Backorder int64 `protobuf:"varint,3,opt,name=backorder,proto3" json:"backorder,omitempty"`
+	// pack_count is the total number of packs shipped
+	PackCount int64 `protobuf:"varint,4,opt,name=pack_count,json=packCount,proto3" json:"pack_count,omitempty"`
+	// cost is the total cost of the packs shipped in minor currency units
+	Cost          int64 `protobuf:"varint,5,opt,name=cost,proto3" json:"cost,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ShipmentTotals) Reset() {
+	*x = ShipmentTotals{}
+	mi := &file_api_packs_v1_packs_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ShipmentTotals) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ShipmentTotals) ProtoMessage() {}
+
+func (x *ShipmentTotals) ProtoReflect() protoreflect.Message {
+	mi := &file_api_packs_v1_packs_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ShipmentTotals.ProtoReflect.Descriptor instead.
+func (*ShipmentTotals) Descriptor() ([]byte, []int) {
+	return file_api_packs_v1_packs_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ShipmentTotals) GetShippedItems() int64 {
+	if x != nil {
+		return x.ShippedItems
+	}
+	return 0
+}
+
+func (x *ShipmentTotals) GetOvershipment() int64 {
+	if x != nil {
+		return x.Overshipment
+	}
+	return 0
+}
+
+func (x *ShipmentTotals) GetBackorder() int64 {
+	if x != nil {
+		return x.Backorder
+	}
+	return 0
+}
+
+func (x *ShipmentTotals) GetPackCount() int64 {
+	if x != nil {
+		return x.PackCount
+	}
+	return 0
+}
+
+func (x *ShipmentTotals) GetCost() int64 {
+	if x != nil {
+		return x.Cost
+	}
+	return 0
+}
+
+type CalculateResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// order_size is the original size of the order, the total quantity of a multi-line order
+	OrderSize int64 `protobuf:"varint,1,opt,name=order_size,json=orderSize,proto3" json:"order_size,omitempty"`
+	// packs are the packs needed for the order sorted by size
+	Packs []*PackCount `protobuf:"bytes,2,rep,name=packs,proto3" json:"packs,omitempty"`
+	// backorder is the number of items of a single-line order that are not shipped in the undership mode
+	Backorder int64 `protobuf:"varint,3,opt,name=backorder,proto3" json:"backorder,omitempty"`
+	// tolerance_exceeded reports whether the overshipment of the order or of any of its lines exceeds the tolerance
+	ToleranceExceeded bool `protobuf:"varint,4,opt,name=tolerance_exceeded,json=toleranceExceeded,proto3" json:"tolerance_exceeded,omitempty"`
+	// lines are the packing plans of the lines of a multi-line order
+	Lines []*LineResult `protobuf:"bytes,5,rep,name=lines,proto3" json:"lines,omitempty"`
+	// totals sums up the packing plans of the lines of a multi-line order
+	Totals        *ShipmentTotals `protobuf:"bytes,6,opt,name=totals,proto3" json:"totals,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CalculateResponse) Reset() {
+	*x = CalculateResponse{}
+	mi := &file_api_packs_v1_packs_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CalculateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CalculateResponse) ProtoMessage() {}
+
+func (x *CalculateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_packs_v1_packs_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CalculateResponse.ProtoReflect.Descriptor instead.
+func (*CalculateResponse) Descriptor() ([]byte, []int) {
+	return file_api_packs_v1_packs_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *CalculateResponse) GetOrderSize() int64 {
+	if x != nil {
+		return x.OrderSize
+	}
+	return 0
+}
+
+func (x *CalculateResponse) GetPacks() []*PackCount {
+	if x != nil {
+		return x.Packs
+	}
+	return nil
+}
+
+func (x *CalculateResponse) GetBackorder() int64 {
+	if x != nil {
+		return x.Backorder
+	}
+	return 0
+}
+
+func (x *CalculateResponse) GetToleranceExceeded() bool {
+	if x != nil {
+		return x.ToleranceExceeded
+	}
+	return false
+}
+
+func (x *CalculateResponse) GetLines() []*LineResult {
+	if x != nil {
+		return x.Lines
+	}
+	return nil
+}
+
+func (x *CalculateResponse) GetTotals() *ShipmentTotals {
+	if x != nil {
+		return x.Totals
+	}
+	return nil
+}
+
+type CalculateStreamRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// id identifies the order in the stream, it is sent back with its response
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// order is the order to calculate
+	Order         *CalculateRequest `protobuf:"bytes,2,opt,name=order,proto3" json:"order,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CalculateStreamRequest) Reset() {
+	*x = CalculateStreamRequest{}
+	mi := &file_api_packs_v1_packs_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CalculateStreamRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CalculateStreamRequest) ProtoMessage() {}
+
+func (x *CalculateStreamRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_packs_v1_packs_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CalculateStreamRequest.ProtoReflect.Descriptor instead.
+func (*CalculateStreamRequest) Descriptor() ([]byte, []int) {
+	return file_api_packs_v1_packs_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *CalculateStreamRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *CalculateStreamRequest) GetOrder() *CalculateRequest {
+	if x != nil {
+		return x.Order
+	}
+	return nil
+}
+
+type CalculateStreamResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// id is the id of the order the response is for
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Types that are valid to be assigned to Result:
+	//
+	//	*CalculateStreamResponse_Response
+	//	*CalculateStreamResponse_Error
+	Result        isCalculateStreamResponse_Result `protobuf_oneof:"result"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CalculateStreamResponse) Reset() {
+	*x = CalculateStreamResponse{}
+	mi := &file_api_packs_v1_packs_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CalculateStreamResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CalculateStreamResponse) ProtoMessage() {}
+
+func (x *CalculateStreamResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_packs_v1_packs_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CalculateStreamResponse.ProtoReflect.Descriptor instead.
+func (*CalculateStreamResponse) Descriptor() ([]byte, []int) {
+	return file_api_packs_v1_packs_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *CalculateStreamResponse) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *CalculateStreamResponse) GetResult() isCalculateStreamResponse_Result {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+func (x *CalculateStreamResponse) GetResponse() *CalculateResponse {
+	if x != nil {
+		if x, ok := x.Result.(*CalculateStreamResponse_Response); ok {
+			return x.Response
+		}
+	}
+	return nil
+}
+
+func (x *CalculateStreamResponse) GetError() *Error {
+	if x != nil {
+		if x, ok := x.Result.(*CalculateStreamResponse_Error); ok {
+			return x.Error
+		}
+	}
+	return nil
+}
+
+type isCalculateStreamResponse_Result interface {
+	isCalculateStreamResponse_Result()
+}
+
+type CalculateStreamResponse_Response struct {
+	// response is the result of a successful calculation
+	Response *CalculateResponse `protobuf:"bytes,2,opt,name=response,proto3,oneof"`
+}
+
+type CalculateStreamResponse_Error struct {
+	// error is the error of a failed calculation
+	Error *Error `protobuf:"bytes,3,opt,name=error,proto3,oneof"`
+}
+
+func (*CalculateStreamResponse_Response) isCalculateStreamResponse_Result() {}
+
+func (*CalculateStreamResponse_Error) isCalculateStreamResponse_Result() {}
+
+// Error is an error of the service
+type Error struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// code is a machine-readable identifier of the error, e.g. "ORDER_SIZE_TOO_LARGE"
+	Code string `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	// message is a human-readable description of the error
+	Message       string `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Error) Reset() {
+	*x = Error{}
+	mi := &file_api_packs_v1_packs_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Error) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Error) ProtoMessage() {}
+
+func (x *Error) ProtoReflect() protoreflect.Message {
+	mi := &file_api_packs_v1_packs_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Error.ProtoReflect.Descriptor instead.
+func (*Error) Descriptor() ([]byte, []int) {
+	return file_api_packs_v1_packs_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *Error) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *Error) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+var File_api_packs_v1_packs_proto protoreflect.FileDescriptor
+
+const file_api_packs_v1_packs_proto_rawDesc = "" +
+	"\n" +
+	"\x18api/packs/v1/packs.proto\x12\bpacks.v1\"\xb2\x01\n" +
+	"\x04Pack\x12\x12\n" +
+	"\x04size\x18\x01 \x01(\x03R\x04size\x12\x12\n" +
+	"\x04cost\x18\x02 \x01(\x03R\x04cost\x12\x1b\n" +
+	"\tmin_count\x18\x03 \x01(\x03R\bminCount\x12\x1b\n" +
+	"\tmax_count\x18\x04 \x01(\x03R\bmaxCount\x12\x16\n" +
+	"\x06weight\x18\x05 \x01(\x03R\x06weight\x12\x16\n" +
+	"\x06volume\x18\x06 \x01(\x03R\x06volume\x12\x18\n" +
+	"\acontent\x18\a \x01(\tR\acontent\"5\n" +
+	"\tPackCount\x12\x12\n" +
+	"\x04size\x18\x01 \x01(\x03R\x04size\x12\x14\n" +
+	"\x05count\x18\x02 \x01(\x03R\x05count\"Y\n" +
+	"\tOrderLine\x12\x18\n" +
+	"\acatalog\x18\x01 \x01(\tR\acatalog\x12\x1a\n" +
+	"\bquantity\x18\x02 \x01(\x03R\bquantity\x12\x16\n" +
+	"\x06amount\x18\x03 \x01(\tR\x06amount\"\x11\n" +
+	"\x0fGetPacksRequest\"8\n" +
+	"\x10GetPacksResponse\x12$\n" +
+	"\x05packs\x18\x01 \x03(\v2\x0e.packs.v1.PackR\x05packs\"4\n" +
+	"\x0eAddPackRequest\x12\"\n" +
+	"\x04pack\x18\x01 \x01(\v2\x0e.packs.v1.PackR\x04pack\"\x11\n" +
+	"\x0fAddPackResponse\"'\n" +
+	"\x11RemovePackRequest\x12\x12\n" +
+	"\x04size\x18\x01 \x01(\x03R\x04size\"\x14\n" +
+	"\x12RemovePackResponse\";\n" +
+	"\x13ReplacePacksRequest\x12$\n" +
+	"\x05packs\x18\x01 \x03(\v2\x0e.packs.v1.PackR\x05packs\"\x16\n" +
+	"\x14ReplacePacksResponse\"\x88\x01\n" +
+	"\x10CalculateRequest\x12\x1d\n" +
+	"\n" +
+	"order_size\x18\x01 \x01(\x03R\torderSize\x12)\n" +
+	"\x05lines\x18\x02 \x03(\v2\x13.packs.v1.OrderLineR\x05lines\x12*\n" +
+	"\x04mode\x18\x03 \x01(\x0e2\x16.packs.v1.ShipmentModeR\x04mode\"\x89\x03\n" +
+	"\n" +
+	"LineResult\x12\x18\n" +
+	"\acatalog\x18\x01 \x01(\tR\acatalog\x12\x1a\n" +
+	"\bquantity\x18\x02 \x01(\x03R\bquantity\x12\x12\n" +
+	"\x04unit\x18\x03 \x01(\tR\x04unit\x12\x16\n" +
+	"\x06amount\x18\x04 \x01(\tR\x06amount\x12%\n" +
+	"\x0eshipped_amount\x18\x05 \x01(\tR\rshippedAmount\x12)\n" +
+	"\x05packs\x18\x06 \x03(\v2\x13.packs.v1.PackCountR\x05packs\x12#\n" +
+	"\rshipped_items\x18\a \x01(\x03R\fshippedItems\x12\"\n" +
+	"\fovershipment\x18\b \x01(\x03R\fovershipment\x12\x1c\n" +
+	"\tbackorder\x18\t \x01(\x03R\tbackorder\x12\x1d\n" +
+	"\n" +
+	"pack_count\x18\n" +
+	" \x01(\x03R\tpackCount\x12\x12\n" +
+	"\x04cost\x18\v \x01(\x03R\x04cost\x12-\n" +
+	"\x12tolerance_exceeded\x18\f \x01(\bR\x11toleranceExceeded\"\xaa\x01\n" +
+	"\x0eShipmentTotals\x12#\n" +
+	"\rshipped_items\x18\x01 \x01(\x03R\fshippedItems\x12\"\n" +
+	"\fovershipment\x18\x02 \x01(\x03R\fovershipment\x12\x1c\n" +
+	"\tbackorder\x18\x03 \x01(\x03R\tbackorder\x12\x1d\n" +
+	"\n" +
+	"pack_count\x18\x04 \x01(\x03R\tpackCount\x12\x12\n" +
+	"\x04cost\x18\x05 \x01(\x03R\x04cost\"\x88\x02\n" +
+	"\x11CalculateResponse\x12\x1d\n" +
+	"\n" +
+	"order_size\x18\x01 \x01(\x03R\torderSize\x12)\n" +
+	"\x05packs\x18\x02 \x03(\v2\x13.packs.v1.PackCountR\x05packs\x12\x1c\n" +
+	"\tbackorder\x18\x03 \x01(\x03R\tbackorder\x12-\n" +
+	"\x12tolerance_exceeded\x18\x04 \x01(\bR\x11toleranceExceeded\x12*\n" +
+	"\x05lines\x18\x05 \x03(\v2\x14.packs.v1.LineResultR\x05lines\x120\n" +
+	"\x06totals\x18\x06 \x01(\v2\x18.packs.v1.ShipmentTotalsR\x06totals\"Z\n" +
+	"\x16CalculateStreamRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x120\n" +
+	"\x05order\x18\x02 \x01(\v2\x1a.packs.v1.CalculateRequestR\x05order\"\x97\x01\n" +
+	"\x17CalculateStreamResponse\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x129\n" +
+	"\bresponse\x18\x02 \x01(\v2\x1b.packs.v1.CalculateResponseH\x00R\bresponse\x12'\n" +
+	"\x05error\x18\x03 \x01(\v2\x0f.packs.v1.ErrorH\x00R\x05errorB\b\n" +
+	"\x06result\"5\n" +
+	"\x05Error\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\tR\x04code\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage*f\n" +
+	"\fShipmentMode\x12\x1d\n" +
+	"\x19SHIPMENT_MODE_UNSPECIFIED\x10\x00\x12\x1a\n" +
+	"\x16SHIPMENT_MODE_OVERSHIP\x10\x01\x12\x1b\n" +
+	"\x17SHIPMENT_MODE_UNDERSHIP\x10\x022\xcb\x03\n" +
+	"\fPacksService\x12A\n" +
+	"\bGetPacks\x12\x19.packs.v1.GetPacksRequest\x1a\x1a.packs.v1.GetPacksResponse\x12>\n" +
+	"\aAddPack\x12\x18.packs.v1.AddPackRequest\x1a\x19.packs.v1.AddPackResponse\x12G\n" +
+	"\n" +
+	"RemovePack\x12\x1b.packs.v1.RemovePackRequest\x1a\x1c.packs.v1.RemovePackResponse\x12M\n" +
+	"\fReplacePacks\x12\x1d.packs.v1.ReplacePacksRequest\x1a\x1e.packs.v1.ReplacePacksResponse\x12D\n" +
+	"\tCalculate\x12\x1a.packs.v1.CalculateRequest\x1a\x1b.packs.v1.CalculateResponse\x12Z\n" +
+	"\x0fCalculateStream\x12 .packs.v1.CalculateStreamRequest\x1a!.packs.v1.CalculateStreamResponse(\x010\x01BIZGgithub.com/alishercodecrafter/orderpackscalculator/api/packs/v1;packsv1b\x06proto3"
+
+var (
+	file_api_packs_v1_packs_proto_rawDescOnce sync.Once
+	file_api_packs_v1_packs_proto_rawDescData []byte
+)
+
+func file_api_packs_v1_packs_proto_rawDescGZIP() []byte {
+	file_api_packs_v1_packs_proto_rawDescOnce.Do(func() {
+		file_api_packs_v1_packs_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_api_packs_v1_packs_proto_rawDesc), len(file_api_packs_v1_packs_proto_rawDesc)))
+	})
+	return file_api_packs_v1_packs_proto_rawDescData
+}
+
+var file_api_packs_v1_packs_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_api_packs_v1_packs_proto_msgTypes = make([]protoimpl.MessageInfo, 18)
+var file_api_packs_v1_packs_proto_goTypes = []any{
+	(ShipmentMode)(0),               // 0: packs.v1.ShipmentMode
+	(*Pack)(nil),                    // 1: packs.v1.Pack
+	(*PackCount)(nil),               // 2: packs.v1.PackCount
+	(*OrderLine)(nil),               // 3: packs.v1.OrderLine
+	(*GetPacksRequest)(nil),         // 4: packs.v1.GetPacksRequest
+	(*GetPacksResponse)(nil),        // 5: packs.v1.GetPacksResponse
+	(*AddPackRequest)(nil),          // 6: packs.v1.AddPackRequest
+	(*AddPackResponse)(nil),         // 7: packs.v1.AddPackResponse
+	(*RemovePackRequest)(nil),       // 8: packs.v1.RemovePackRequest
+	(*RemovePackResponse)(nil),      // 9: packs.v1.RemovePackResponse
+	(*ReplacePacksRequest)(nil),     // 10: packs.v1.ReplacePacksRequest
+	(*ReplacePacksResponse)(nil),    // 11: packs.v1.ReplacePacksResponse
+	(*CalculateRequest)(nil),        // 12: packs.v1.CalculateRequest
+	(*LineResult)(nil),              // 13: packs.v1.LineResult
+	(*ShipmentTotals)(nil),          // 14: packs.v1.ShipmentTotals
+	(*CalculateResponse)(nil),       // 15: packs.v1.CalculateResponse
+	(*CalculateStreamRequest)(nil),  // 16: packs.v1.CalculateStreamRequest
+	(*CalculateStreamResponse)(nil), // 17: packs.v1.CalculateStreamResponse
+	(*Error)(nil),                   // 18: packs.v1.Error
+}
+var file_api_packs_v1_packs_proto_depIdxs = []int32{
+	1,  // 0: packs.v1.GetPacksResponse.packs:type_name -> packs.v1.Pack
+	1,  // 1: packs.v1.AddPackRequest.pack:type_name -> packs.v1.Pack
+	1,  // 2: packs.v1.ReplacePacksRequest.packs:type_name -> packs.v1.Pack
+	3,  // 3: packs.v1.CalculateRequest.lines:type_name -> packs.v1.OrderLine
+	0,  // 4: packs.v1.CalculateRequest.mode:type_name -> packs.v1.ShipmentMode
+	2,  // 5: packs.v1.LineResult.packs:type_name -> packs.v1.PackCount
+	2,  // 6: packs.v1.CalculateResponse.packs:type_name -> packs.v1.PackCount
+	13, // 7: packs.v1.CalculateResponse.lines:type_name -> packs.v1.LineResult
+	14, // 8: packs.v1.CalculateResponse.totals:type_name -> packs.v1.ShipmentTotals
+	12, // 9: packs.v1.CalculateStreamRequest.order:type_name -> packs.v1.CalculateRequest
+	15, // 10: packs.v1.CalculateStreamResponse.response:type_name -> packs.v1.CalculateResponse
+	18, // 11: packs.v1.CalculateStreamResponse.error:type_name -> packs.v1.Error
+	4,  // 12: packs.v1.PacksService.GetPacks:input_type -> packs.v1.GetPacksRequest
+	6,  // 13: packs.v1.PacksService.AddPack:input_type -> packs.v1.AddPackRequest
+	8,  // 14: packs.v1.PacksService.RemovePack:input_type -> packs.v1.RemovePackRequest
+	10, // 15: packs.v1.PacksService.ReplacePacks:input_type -> packs.v1.ReplacePacksRequest
+	12, // 16: packs.v1.PacksService.Calculate:input_type -> packs.v1.CalculateRequest
+	16, // 17: packs.v1.PacksService.CalculateStream:input_type -> packs.v1.CalculateStreamRequest
+	5,  // 18: packs.v1.PacksService.GetPacks:output_type -> packs.v1.GetPacksResponse
+	7,  // 19: packs.v1.PacksService.AddPack:output_type -> packs.v1.AddPackResponse
+	9,  // 20: packs.v1.PacksService.RemovePack:output_type -> packs.v1.RemovePackResponse
+	11, // 21: packs.v1.PacksService.ReplacePacks:output_type -> packs.v1.ReplacePacksResponse
+	15, // 22: packs.v1.PacksService.Calculate:output_type -> packs.v1.CalculateResponse
+	17, // 23: packs.v1.PacksService.CalculateStream:output_type -> packs.v1.CalculateStreamResponse
+	18, // [18:24] is the sub-list for method output_type
+	12, // [12:18] is the sub-list for method input_type
+	12, // [12:12] is the sub-list for extension type_name
+	12, // [12:12] is the sub-list for extension extendee
+	0,  // [0:12] is the sub-list for field type_name
+}
+
+func init() { file_api_packs_v1_packs_proto_init() }
+func file_api_packs_v1_packs_proto_init() {
+	if File_api_packs_v1_packs_proto != nil {
+		return
+	}
+	file_api_packs_v1_packs_proto_msgTypes[16].OneofWrappers = []any{
+		(*CalculateStreamResponse_Response)(nil),
+		(*CalculateStreamResponse_Error)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_api_packs_v1_packs_proto_rawDesc), len(file_api_packs_v1_packs_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   18,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_api_packs_v1_packs_proto_goTypes,
+		DependencyIndexes: file_api_packs_v1_packs_proto_depIdxs,
+		EnumInfos:         file_api_packs_v1_packs_proto_enumTypes,
+		MessageInfos:      file_api_packs_v1_packs_proto_msgTypes,
+	}.Build()
+	File_api_packs_v1_packs_proto = out.File
+	file_api_packs_v1_packs_proto_goTypes = nil
+	file_api_packs_v1_packs_proto_depIdxs = nil
+}
@@ -0,0 +1,333 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: api/packs/v1/packs.proto
+
+// The gRPC API of the order packs calculator. It manages the available packs and calculates packs for orders
+// with the same service as the REST API.
+
+package packsv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	PacksService_GetPacks_FullMethodName        = "/packs.v1.PacksService/GetPacks"
+	PacksService_AddPack_FullMethodName         = "/packs.v1.PacksService/AddPack"
+	PacksService_RemovePack_FullMethodName      = "/packs.v1.PacksService/RemovePack"
+	PacksService_ReplacePacks_FullMethodName    = "/packs.v1.PacksService/ReplacePacks"
+	PacksService_Calculate_FullMethodName       = "/packs.v1.PacksService/Calculate"
+	PacksService_CalculateStream_FullMethodName = "/packs.v1.PacksService/CalculateStream"
+)
+
+// PacksServiceClient is the client API for PacksService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// PacksService manages the available packs and calculates the packs needed for orders.
+//
+// Failed calls return a status with the error message of the service and a packs.v1.Error detail
+// carrying its machine-readable code, the same code the REST API responds with.
+type PacksServiceClient interface {
+	// GetPacks returns all available packs
+	GetPacks(ctx context.Context, in *GetPacksRequest, opts ...grpc.CallOption) (*GetPacksResponse, error)
+	// AddPack adds a new pack
+	AddPack(ctx context.Context, in *AddPackRequest, opts ...grpc.CallOption) (*AddPackResponse, error)
+	// RemovePack removes a pack by its size
+	RemovePack(ctx context.Context, in *RemovePackRequest, opts ...grpc.CallOption) (*RemovePackResponse, error)
+	// ReplacePacks replaces all packs
+	ReplacePacks(ctx context.Context, in *ReplacePacksRequest, opts ...grpc.CallOption) (*ReplacePacksResponse, error)
+	// Calculate calculates the optimal number of packs needed for a single-line or a multi-line order
+	Calculate(ctx context.Context, in *CalculateRequest, opts ...grpc.CallOption) (*CalculateResponse, error)
+	// CalculateStream calculates a stream of orders, responding to each of them in the order they are sent.
+	// A failed calculation is answered with its error and does not end the stream.
+	CalculateStream(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[CalculateStreamRequest, CalculateStreamResponse], error)
+}
+
+type packsServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewPacksServiceClient(cc grpc.ClientConnInterface) PacksServiceClient {
+	return &packsServiceClient{cc}
+}
+
+func (c *packsServiceClient) GetPacks(ctx context.Context, in *GetPacksRequest, opts ...grpc.CallOption) (*GetPacksResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetPacksResponse)
+	err := c.cc.Invoke(ctx, PacksService_GetPacks_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *packsServiceClient) AddPack(ctx context.Context, in *AddPackRequest, opts ...grpc.CallOption) (*AddPackResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AddPackResponse)
+	err := c.cc.Invoke(ctx, PacksService_AddPack_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *packsServiceClient) RemovePack(ctx context.Context, in *RemovePackRequest, opts ...grpc.CallOption) (*RemovePackResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RemovePackResponse)
+	err := c.cc.Invoke(ctx, PacksService_RemovePack_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *packsServiceClient) ReplacePacks(ctx context.Context, in *ReplacePacksRequest, opts ...grpc.CallOption) (*ReplacePacksResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReplacePacksResponse)
+	err := c.cc.Invoke(ctx, PacksService_ReplacePacks_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *packsServiceClient) Calculate(ctx context.Context, in *CalculateRequest, opts ...grpc.CallOption) (*CalculateResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CalculateResponse)
+	err := c.cc.Invoke(ctx, PacksService_Calculate_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *packsServiceClient) CalculateStream(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[CalculateStreamRequest, CalculateStreamResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &PacksService_ServiceDesc.Streams[0], PacksService_CalculateStream_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[CalculateStreamRequest, CalculateStreamResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PacksService_CalculateStreamClient = grpc.BidiStreamingClient[CalculateStreamRequest, CalculateStreamResponse]
+
+// PacksServiceServer is the server API for PacksService service.
+// All implementations must embed UnimplementedPacksServiceServer
+// for forward compatibility.
+//
+// PacksService manages the available packs and calculates the packs needed for orders.
+//
+// Failed calls return a status with the error message of the service and a packs.v1.Error detail
+// carrying its machine-readable code, the same code the REST API responds with.
+type PacksServiceServer interface {
+	// GetPacks returns all available packs
+	GetPacks(context.Context, *GetPacksRequest) (*GetPacksResponse, error)
+	// AddPack adds a new pack
+	AddPack(context.Context, *AddPackRequest) (*AddPackResponse, error)
+	// RemovePack removes a pack by its size
+	RemovePack(context.Context, *RemovePackRequest) (*RemovePackResponse, error)
+	// ReplacePacks replaces all packs
+	ReplacePacks(context.Context, *ReplacePacksRequest) (*ReplacePacksResponse, error)
+	// Calculate calculates the optimal number of packs needed for a single-line or a multi-line order
+	Calculate(context.Context, *CalculateRequest) (*CalculateResponse, error)
+	// CalculateStream calculates a stream of orders, responding to each of them in the order they are sent.
+	// A failed calculation is answered with its error and does not end the stream.
+	CalculateStream(grpc.BidiStreamingServer[CalculateStreamRequest, CalculateStreamResponse]) error
+	mustEmbedUnimplementedPacksServiceServer()
+}
+
+// UnimplementedPacksServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedPacksServiceServer struct{}
+
+func (UnimplementedPacksServiceServer) GetPacks(context.Context, *GetPacksRequest) (*GetPacksResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPacks not implemented")
+}
+func (UnimplementedPacksServiceServer) AddPack(context.Context, *AddPackRequest) (*AddPackResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddPack not implemented")
+}
+func (UnimplementedPacksServiceServer) RemovePack(context.Context, *RemovePackRequest) (*RemovePackResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemovePack not implemented")
+}
+func (UnimplementedPacksServiceServer) ReplacePacks(context.Context, *ReplacePacksRequest) (*ReplacePacksResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReplacePacks not implemented")
+}
+func (UnimplementedPacksServiceServer) Calculate(context.Context, *CalculateRequest) (*CalculateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Calculate not implemented")
+}
+func (UnimplementedPacksServiceServer) CalculateStream(grpc.BidiStreamingServer[CalculateStreamRequest, CalculateStreamResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method CalculateStream not implemented")
+}
+func (UnimplementedPacksServiceServer) mustEmbedUnimplementedPacksServiceServer() {}
+func (UnimplementedPacksServiceServer) testEmbeddedByValue()                      {}
+
+// UnsafePacksServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to PacksServiceServer will
+// result in compilation errors.
+type UnsafePacksServiceServer interface {
+	mustEmbedUnimplementedPacksServiceServer()
+}
+
+func RegisterPacksServiceServer(s grpc.ServiceRegistrar, srv PacksServiceServer) {
+	// If the following call pancis, it indicates UnimplementedPacksServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&PacksService_ServiceDesc, srv)
+}
+
+func _PacksService_GetPacks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPacksRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PacksServiceServer).GetPacks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PacksService_GetPacks_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PacksServiceServer).GetPacks(ctx, req.(*GetPacksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PacksService_AddPack_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddPackRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PacksServiceServer).AddPack(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PacksService_AddPack_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PacksServiceServer).AddPack(ctx, req.(*AddPackRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PacksService_RemovePack_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemovePackRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PacksServiceServer).RemovePack(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PacksService_RemovePack_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PacksServiceServer).RemovePack(ctx, req.(*RemovePackRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PacksService_ReplacePacks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReplacePacksRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PacksServiceServer).ReplacePacks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PacksService_ReplacePacks_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PacksServiceServer).ReplacePacks(ctx, req.(*ReplacePacksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PacksService_Calculate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CalculateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PacksServiceServer).Calculate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PacksService_Calculate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PacksServiceServer).Calculate(ctx, req.(*CalculateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PacksService_CalculateStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(PacksServiceServer).CalculateStream(&grpc.GenericServerStream[CalculateStreamRequest, CalculateStreamResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PacksService_CalculateStreamServer = grpc.BidiStreamingServer[CalculateStreamRequest, CalculateStreamResponse]
+
+// PacksService_ServiceDesc is the grpc.ServiceDesc for PacksService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var PacksService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "packs.v1.PacksService",
+	HandlerType: (*PacksServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetPacks",
+			Handler:    _PacksService_GetPacks_Handler,
+		},
+		{
+			MethodName: "AddPack",
+			Handler:    _PacksService_AddPack_Handler,
+		},
+		{
+			MethodName: "RemovePack",
+			Handler:    _PacksService_RemovePack_Handler,
+		},
+		{
+			MethodName: "ReplacePacks",
+			Handler:    _PacksService_ReplacePacks_Handler,
+		},
+		{
+			MethodName: "Calculate",
+			Handler:    _PacksService_Calculate_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "CalculateStream",
+			Handler:       _PacksService_CalculateStream_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "api/packs/v1/packs.proto",
+}
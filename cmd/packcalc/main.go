@@ -0,0 +1,225 @@
+// Command packcalc calculates the packs of order sizes offline, without starting the server.
+//
+// Usage:
+//
+//	packcalc [flags] [order size...]
+//
+// The pack sizes are taken from -packs, from the file given by -packs-file, or from stdin if -packs-file is "-",
+// and default to the pack sizes of the server. The order sizes are taken from the arguments or, if there are none,
+// from stdin. Sizes are separated by commas or whitespace.
+//
+// The exit code is 0 if every order is calculated, 1 if the calculation of an order fails
+// and 2 if the flags or the input are invalid.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/alishercodecrafter/orderpackscalculator/internal/model"
+	"github.com/alishercodecrafter/orderpackscalculator/internal/repository"
+	"github.com/alishercodecrafter/orderpackscalculator/internal/service"
+)
+
+const (
+	// exitOK means every order is calculated
+	exitOK = 0
+	// exitCalculationFailed means the calculation of at least one order fails
+	exitCalculationFailed = 1
+	// exitUsage means the flags or the input are invalid
+	exitUsage = 2
+)
+
+func main() {
+	os.Exit(run(context.Background(), os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run calculates the orders given by the arguments and stdin, writes the results to stdout
+// and returns the exit code
+func run(ctx context.Context, args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("packcalc", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: packcalc [flags] [order size...]")
+		flags.PrintDefaults()
+	}
+
+	packsFlag := flags.String("packs", "", "pack sizes separated by commas, e.g. 250,500,1000")
+	packsFile := flags.String("packs-file", "", `file of pack sizes separated by commas or whitespace, "-" for stdin`)
+	format := flags.String("format", formatTable, "output format: table, json or csv")
+	mode := flags.String("mode", string(model.ShipmentModeOvership), "shipment mode: overship or undership")
+
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+
+		return exitUsage
+	}
+
+	if *format != formatTable && *format != formatJSON && *format != formatCSV {
+		return usageError(stderr, fmt.Errorf("format must be %s, %s or %s", formatTable, formatJSON, formatCSV))
+	}
+
+	shipmentMode := model.ShipmentMode(*mode)
+	if shipmentMode != model.ShipmentModeOvership && shipmentMode != model.ShipmentModeUndership {
+		return usageError(
+			stderr,
+			fmt.Errorf("mode must be %s or %s", model.ShipmentModeOvership, model.ShipmentModeUndership),
+		)
+	}
+
+	packSizes, err := readPackSizes(*packsFlag, *packsFile, stdin)
+	if err != nil {
+		return usageError(stderr, err)
+	}
+
+	if (*packsFlag != "" || *packsFile != "") && len(packSizes) == 0 {
+		return usageError(stderr, errors.New("there are no pack sizes to calculate with"))
+	}
+
+	orderSizes, err := readOrderSizes(flags.Args(), *packsFile == "-", stdin)
+	if err != nil {
+		return usageError(stderr, err)
+	}
+
+	svc, err := newService(packSizes)
+	if err != nil {
+		return usageError(stderr, err)
+	}
+
+	results := make([]result, 0, len(orderSizes))
+	failed := false
+	for _, orderSize := range orderSizes {
+		response, err := svc.Calculate(ctx, model.CalculationRequest{OrderSize: orderSize, Mode: shipmentMode})
+		if err != nil {
+			response = model.CalculationResponse{OrderSize: orderSize}
+		}
+
+		r := newResult(response, err)
+		if r.Error != nil {
+			failed = true
+		}
+		results = append(results, r)
+	}
+
+	if err := writeResults(stdout, *format, results); err != nil {
+		fmt.Fprintf(stderr, "packcalc: %v\n", err)
+
+		return exitCalculationFailed
+	}
+
+	if failed {
+		return exitCalculationFailed
+	}
+
+	return exitOK
+}
+
+// usageError reports an invalid flag or input and returns the usage exit code
+func usageError(stderr io.Writer, err error) int {
+	fmt.Fprintf(stderr, "packcalc: %v\n", err)
+
+	return exitUsage
+}
+
+// readPackSizes returns the pack sizes of the -packs flag or of the -packs-file file,
+// or no sizes if neither is set
+func readPackSizes(packsFlag, packsFile string, stdin io.Reader) ([]int64, error) {
+	switch {
+	case packsFlag != "" && packsFile != "":
+		return nil, errors.New("-packs and -packs-file cannot be used together")
+	case packsFlag != "":
+		return parseSizes(packsFlag)
+	case packsFile == "-":
+		return readSizes(stdin)
+	case packsFile != "":
+		file, err := os.Open(packsFile)
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+
+		return readSizes(file)
+	default:
+		return nil, nil
+	}
+}
+
+// readOrderSizes returns the order sizes of the arguments or, if there are none and stdin is not read
+// for the pack sizes, of stdin
+func readOrderSizes(args []string, stdinRead bool, stdin io.Reader) ([]int64, error) {
+	var orderSizes []int64
+	if len(args) > 0 {
+		var err error
+		if orderSizes, err = parseSizes(strings.Join(args, " ")); err != nil {
+			return nil, err
+		}
+	} else if !stdinRead {
+		var err error
+		if orderSizes, err = readSizes(stdin); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(orderSizes) == 0 {
+		return nil, errors.New("there are no order sizes to calculate")
+	}
+
+	return orderSizes, nil
+}
+
+// readSizes reads sizes separated by commas or whitespace
+func readSizes(r io.Reader) ([]int64, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseSizes(string(data))
+}
+
+// parseSizes parses sizes separated by commas or whitespace
+func parseSizes(text string) ([]int64, error) {
+	fields := strings.FieldsFunc(text, func(r rune) bool {
+		return r == ',' || unicode.IsSpace(r)
+	})
+
+	sizes := make([]int64, 0, len(fields))
+	for _, field := range fields {
+		size, err := strconv.ParseInt(field, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a whole number", field)
+		}
+		sizes = append(sizes, size)
+	}
+
+	return sizes, nil
+}
+
+// newService creates a service with an in-memory repository holding the pack sizes,
+// or the default pack sizes of the repository if there are none
+func newService(packSizes []int64) (*service.PacksServiceImpl, error) {
+	svc := service.NewPacksService(repository.NewMemoryRepository())
+	if len(packSizes) == 0 {
+		return svc, nil
+	}
+
+	packs := make(model.Packs, 0, len(packSizes))
+	for _, size := range packSizes {
+		packs = append(packs, model.Pack{Size: model.PackSize(size)})
+	}
+
+	if err := svc.ReplacePacks(packs); err != nil {
+		return nil, err
+	}
+
+	return svc, nil
+}
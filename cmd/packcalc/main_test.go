@@ -0,0 +1,198 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/alishercodecrafter/orderpackscalculator/internal/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRun(t *testing.T) {
+	packsFile := filepath.Join(t.TempDir(), "packs.txt")
+	require.NoError(t, os.WriteFile(packsFile, []byte("23\n31, 53\n"), 0o644))
+
+	manySizes := make([]string, 0, 101)
+	for size := 1; size <= 101; size++ {
+		manySizes = append(manySizes, strconv.Itoa(size))
+	}
+
+	testCases := []struct {
+		name       string
+		args       []string
+		stdin      string
+		exitCode   int
+		wantStdout string
+		wantStderr string
+	}{
+		{
+			name:     "Order sizes of the arguments with the default packs",
+			args:     []string{"1", "501,12001"},
+			exitCode: exitOK,
+			wantStdout: "ORDER SIZE  SHIPPED  OVERSHIPMENT  BACKORDER  PACK COUNT  PACKS\n" +
+				"1           250      249           0          1           1x250\n" +
+				"501         750      249           0          2           1x500 1x250\n" +
+				"12001       12250    249           0          4           2x5000 1x2000 1x250\n",
+		},
+		{
+			name:     "Order sizes of stdin",
+			args:     []string{"-packs", "250,500", "-format", "csv"},
+			stdin:    "501\n 251,",
+			exitCode: exitOK,
+			wantStdout: "order_size,shipped_items,overshipment,backorder,pack_count,packs,error_code,error\n" +
+				"501,750,249,0,2,1x500;1x250,,\n" +
+				"251,500,249,0,1,1x500,,\n",
+		},
+		{
+			name:     "Pack sizes of a file",
+			args:     []string{"-packs-file", packsFile, "-format", "csv", "500000"},
+			exitCode: exitOK,
+			wantStdout: "order_size,shipped_items,overshipment,backorder,pack_count,packs,error_code,error\n" +
+				"500000,500000,0,0,9438,9429x53;7x31;2x23,,\n",
+		},
+		{
+			name:     "Pack sizes of stdin",
+			args:     []string{"-packs-file", "-", "-format", "csv", "-mode", "undership", "260"},
+			stdin:    "250 500",
+			exitCode: exitOK,
+			wantStdout: "order_size,shipped_items,overshipment,backorder,pack_count,packs,error_code,error\n" +
+				"260,250,0,10,1,1x250,,\n",
+		},
+		{
+			name:     "Failed calculation",
+			args:     []string{"-packs", "250", "-format", "csv", "0", "1"},
+			exitCode: exitCalculationFailed,
+			wantStdout: "order_size,shipped_items,overshipment,backorder,pack_count,packs,error_code,error\n" +
+				"0,,,,,,INVALID_ORDER_SIZE,order size must be greater than zero\n" +
+				"1,250,249,0,1,1x250,,\n",
+		},
+		{
+			name:       "Unknown flag",
+			args:       []string{"-unknown", "1"},
+			exitCode:   exitUsage,
+			wantStderr: "flag provided but not defined: -unknown",
+		},
+		{
+			name:       "Unknown format",
+			args:       []string{"-format", "xml", "1"},
+			exitCode:   exitUsage,
+			wantStderr: "packcalc: format must be table, json or csv\n",
+		},
+		{
+			name:       "Unknown mode",
+			args:       []string{"-mode", "exact", "1"},
+			exitCode:   exitUsage,
+			wantStderr: "packcalc: mode must be overship or undership\n",
+		},
+		{
+			name:       "Both pack flags",
+			args:       []string{"-packs", "250", "-packs-file", packsFile, "1"},
+			exitCode:   exitUsage,
+			wantStderr: "packcalc: -packs and -packs-file cannot be used together\n",
+		},
+		{
+			name:       "Missing packs file",
+			args:       []string{"-packs-file", filepath.Join(t.TempDir(), "missing.txt"), "1"},
+			exitCode:   exitUsage,
+			wantStderr: "no such file or directory",
+		},
+		{
+			name:       "No pack sizes",
+			args:       []string{"-packs", ",", "1"},
+			exitCode:   exitUsage,
+			wantStderr: "packcalc: there are no pack sizes to calculate with\n",
+		},
+		{
+			name:       "Invalid pack size",
+			args:       []string{"-packs", "250,abc", "1"},
+			exitCode:   exitUsage,
+			wantStderr: "packcalc: \"abc\" is not a whole number\n",
+		},
+		{
+			name:       "Duplicate pack size",
+			args:       []string{"-packs", "250,250", "1"},
+			exitCode:   exitUsage,
+			wantStderr: "already exists",
+		},
+		{
+			name:       "Too many pack sizes",
+			args:       []string{"-packs", strings.Join(manySizes, ","), "1"},
+			exitCode:   exitUsage,
+			wantStderr: "packcalc: number of packs cannot exceed 100\n",
+		},
+		{
+			name:       "Invalid order size",
+			args:       []string{"1.5"},
+			exitCode:   exitUsage,
+			wantStderr: "packcalc: \"1.5\" is not a whole number\n",
+		},
+		{
+			name:       "No order sizes",
+			args:       []string{"-packs-file", "-"},
+			stdin:      "250",
+			exitCode:   exitUsage,
+			wantStderr: "packcalc: there are no order sizes to calculate\n",
+		},
+		{
+			name:       "Help",
+			args:       []string{"-h"},
+			exitCode:   exitOK,
+			wantStderr: "Usage: packcalc [flags] [order size...]\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			exitCode := run(context.Background(), tc.args, strings.NewReader(tc.stdin), &stdout, &stderr)
+
+			require.Equal(t, tc.exitCode, exitCode, stderr.String())
+			require.Equal(t, tc.wantStdout, stdout.String())
+			if tc.wantStderr == "" {
+				require.Empty(t, stderr.String())
+			} else {
+				require.Contains(t, stderr.String(), tc.wantStderr)
+			}
+		})
+	}
+}
+
+func TestRun_JSON(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	args := []string{"-packs", "250,500", "-format", "json", "501", "0"}
+	exitCode := run(context.Background(), args, strings.NewReader(""), &stdout, &stderr)
+
+	require.Equal(t, exitCalculationFailed, exitCode)
+	require.Empty(t, stderr.String())
+
+	var results []result
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &results))
+	require.Len(t, results, 2)
+
+	require.Equal(t, int64(501), results[0].OrderSize)
+	require.Equal(t, int64(750), results[0].ShippedItems)
+	require.Equal(t, int64(249), results[0].Overshipment)
+	require.Equal(t, int64(2), results[0].PackCount)
+	require.Nil(t, results[0].Error)
+
+	require.Equal(t, int64(0), results[1].OrderSize)
+	require.NotNil(t, results[1].Error)
+	require.Equal(t, "INVALID_ORDER_SIZE", string(results[1].Error.Code))
+}
+
+func TestNewResult_Overflow(t *testing.T) {
+	response := model.CalculationResponse{OrderSize: 1, Packs: map[model.PackSize]int64{math.MaxInt64 / 2: 3}}
+
+	r := newResult(response, nil)
+	require.NotNil(t, r.Error)
+	require.Equal(t, model.ErrorCodeArithmeticOverflow, r.Error.Code)
+	require.Nil(t, r.Packs)
+	require.Zero(t, r.ShippedItems)
+}
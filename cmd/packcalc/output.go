@@ -0,0 +1,143 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/alishercodecrafter/orderpackscalculator/internal/model"
+	"github.com/alishercodecrafter/orderpackscalculator/pkg/packing"
+)
+
+const (
+	// formatTable writes the results as an aligned text table
+	formatTable = "table"
+	// formatJSON writes the results as a JSON array
+	formatJSON = "json"
+	// formatCSV writes the results as CSV with a header row
+	formatCSV = "csv"
+)
+
+// result is the outcome of the calculation of an order size
+type result struct {
+	model.CalculationResponse
+	// ShippedItems is the total number of items in the packs
+	ShippedItems int64 `json:"shippedItems"`
+	// Overshipment is the number of items shipped above the order size
+	Overshipment int64 `json:"overshipment"`
+	// PackCount is the total number of packs
+	PackCount int64 `json:"packCount"`
+	// Error is the error of a failed calculation
+	Error *model.ErrorResponse `json:"error,omitempty"`
+}
+
+// newResult sums up the response of a calculation, or describes its error or the overflow of its sums
+func newResult(response model.CalculationResponse, err error) result {
+	r := result{CalculationResponse: response}
+	if err == nil {
+		r.ShippedItems, r.PackCount, err = packing.Combination(response.Packs).Totals()
+		if err != nil {
+			err = model.ErrArithmeticOverflow
+		}
+	}
+
+	if err != nil {
+		return result{
+			CalculationResponse: model.CalculationResponse{OrderSize: response.OrderSize},
+			Error:               &model.ErrorResponse{Error: err.Error(), Code: model.ErrorCodeOf(err)},
+		}
+	}
+	r.Overshipment = max(r.ShippedItems-response.OrderSize, 0)
+
+	return r
+}
+
+// writeResults writes the results in the format
+func writeResults(w io.Writer, format string, results []result) error {
+	switch format {
+	case formatJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+
+		return encoder.Encode(results)
+	case formatCSV:
+		return writeCSV(w, results)
+	default:
+		return writeTable(w, results)
+	}
+}
+
+// writeTable writes the results as an aligned text table
+func writeTable(w io.Writer, results []result) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ORDER SIZE\tSHIPPED\tOVERSHIPMENT\tBACKORDER\tPACK COUNT\tPACKS")
+	for _, r := range results {
+		if r.Error != nil {
+			fmt.Fprintf(tw, "%d\t\t\t\t\terror: %s\n", r.OrderSize, r.Error.Error)
+
+			continue
+		}
+
+		fmt.Fprintf(
+			tw,
+			"%d\t%d\t%d\t%d\t%d\t%s\n",
+			r.OrderSize,
+			r.ShippedItems,
+			r.Overshipment,
+			r.Backorder,
+			r.PackCount,
+			formatPacks(r.Packs, " "),
+		)
+	}
+
+	return tw.Flush()
+}
+
+// writeCSV writes the results as CSV with a header row
+func writeCSV(w io.Writer, results []result) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{
+		"order_size", "shipped_items", "overshipment", "backorder", "pack_count", "packs", "error_code", "error",
+	}); err != nil {
+		return err
+	}
+
+	for _, r := range results {
+		record := []string{strconv.FormatInt(r.OrderSize, 10), "", "", "", "", "", "", ""}
+		if r.Error != nil {
+			record[6], record[7] = string(r.Error.Code), r.Error.Error
+		} else {
+			record[1] = strconv.FormatInt(r.ShippedItems, 10)
+			record[2] = strconv.FormatInt(r.Overshipment, 10)
+			record[3] = strconv.FormatInt(r.Backorder, 10)
+			record[4] = strconv.FormatInt(r.PackCount, 10)
+			record[5] = formatPacks(r.Packs, ";")
+		}
+
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+
+	return cw.Error()
+}
+
+// formatPacks formats the counts of packs largest first, e.g. "2x500 1x250" with a space as the separator
+func formatPacks(packs map[model.PackSize]int64, separator string) string {
+	sizes := slices.Sorted(maps.Keys(packs))
+	slices.Reverse(sizes)
+
+	parts := make([]string, 0, len(sizes))
+	for _, size := range sizes {
+		parts = append(parts, fmt.Sprintf("%dx%d", packs[size], size))
+	}
+
+	return strings.Join(parts, separator)
+}
@@ -0,0 +1,340 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/alishercodecrafter/orderpackscalculator/pkg/client"
+)
+
+// defaultHistoryLimit is the number of most recent calculations the history command shows by default
+const defaultHistoryLimit = 20
+
+// changed is the JSON output of a change of the packs, the response of the server to it
+var changed = map[string]bool{"success": true}
+
+// listPacks prints the available packs
+func (e *env) listPacks(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("packs list", flag.ContinueOnError)
+	if err := e.parseFlags(flags, args); err != nil {
+		return err
+	}
+
+	if flags.NArg() > 0 {
+		return newUsageError("packs list takes no arguments")
+	}
+
+	packs, err := e.client.GetPacks(ctx)
+	if err != nil {
+		return err
+	}
+
+	return e.print(packs, func(w io.Writer) {
+		writePacks(w, packs)
+	})
+}
+
+// addPack adds a pack with its properties
+func (e *env) addPack(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("packs add", flag.ContinueOnError)
+	cost := flags.Int64("cost", 0, "cost of a pack in minor currency units")
+	minCount := flags.Int64("min-count", 0, "number of packs every combination must hold")
+	maxCount := flags.Int64("max-count", 0, "largest number of packs a combination can hold, 0 means no limit")
+	weight := flags.Int64("weight", 0, "weight of a pack")
+	volume := flags.Int64("volume", 0, "volume of a pack")
+	if err := e.parseFlags(flags, args); err != nil {
+		return err
+	}
+
+	sizes, err := parseSizes(flags.Args())
+	if err != nil {
+		return err
+	}
+
+	if len(sizes) != 1 {
+		return newUsageError("packs add requires exactly one pack size")
+	}
+
+	pack := client.Pack{
+		Size:     client.PackSize(sizes[0]),
+		Cost:     *cost,
+		MinCount: *minCount,
+		MaxCount: *maxCount,
+		Weight:   *weight,
+		Volume:   *volume,
+	}
+	if err := e.client.AddPack(ctx, pack); err != nil {
+		return err
+	}
+
+	return e.print(changed, func(w io.Writer) {
+		fmt.Fprintf(w, "Added pack size %d\n", pack.Size)
+	})
+}
+
+// removePacks removes packs by their sizes
+func (e *env) removePacks(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("packs remove", flag.ContinueOnError)
+	if err := e.parseFlags(flags, args); err != nil {
+		return err
+	}
+
+	sizes, err := parseSizes(flags.Args())
+	if err != nil {
+		return err
+	}
+
+	if len(sizes) == 0 {
+		return newUsageError("packs remove requires at least one pack size")
+	}
+
+	responses := make([]map[string]bool, 0, len(sizes))
+	for _, size := range sizes {
+		if err := e.client.RemovePack(ctx, client.PackSize(size)); err != nil {
+			return fmt.Errorf("pack size %d: %w", size, err)
+		}
+		responses = append(responses, changed)
+	}
+
+	return e.print(responses, func(w io.Writer) {
+		for _, size := range sizes {
+			fmt.Fprintf(w, "Removed pack size %d\n", size)
+		}
+	})
+}
+
+// replacePacks replaces all packs with packs of the sizes
+func (e *env) replacePacks(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("packs replace", flag.ContinueOnError)
+	if err := e.parseFlags(flags, args); err != nil {
+		return err
+	}
+
+	sizes, err := parseSizes(flags.Args())
+	if err != nil {
+		return err
+	}
+
+	if len(sizes) == 0 {
+		return newUsageError("packs replace requires at least one pack size")
+	}
+
+	packs := make(client.Packs, 0, len(sizes))
+	for _, size := range sizes {
+		packs = append(packs, client.Pack{Size: client.PackSize(size)})
+	}
+
+	if err := e.client.ReplacePacks(ctx, packs); err != nil {
+		return err
+	}
+
+	return e.print(changed, func(w io.Writer) {
+		fmt.Fprintf(w, "Replaced the packs with sizes %s\n", joinSizes(sizes))
+	})
+}
+
+// calculate calculates the packs of order sizes, one request per order size
+func (e *env) calculate(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("calculate", flag.ContinueOnError)
+	mode := flags.String("mode", "", "shipment mode: overship or undership, the server default if it is empty")
+	if err := e.parseFlags(flags, args); err != nil {
+		return err
+	}
+
+	orderSizes, err := parseSizes(flags.Args())
+	if err != nil {
+		return err
+	}
+
+	if len(orderSizes) == 0 {
+		return newUsageError("calculate requires at least one order size")
+	}
+
+	results := make([]client.CalculationResponse, 0, len(orderSizes))
+	for _, orderSize := range orderSizes {
+		req := client.CalculationRequest{OrderSize: orderSize, Mode: client.ShipmentMode(*mode)}
+		result, err := e.client.Calculate(ctx, req)
+		if err != nil {
+			return fmt.Errorf("order size %d: %w", orderSize, err)
+		}
+		results = append(results, result)
+	}
+
+	return e.print(results, func(w io.Writer) {
+		writeCalculations(w, results)
+	})
+}
+
+// history prints the most recent calculations, oldest first
+func (e *env) history(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("history", flag.ContinueOnError)
+	limit := flags.Int("limit", defaultHistoryLimit, "number of most recent calculations to show, 0 shows all")
+	if err := e.parseFlags(flags, args); err != nil {
+		return err
+	}
+
+	if *limit < 0 {
+		return newUsageError("limit must not be negative")
+	}
+
+	records, err := e.client.GetCalculations(ctx)
+	if err != nil {
+		return err
+	}
+
+	if *limit > 0 && len(records) > *limit {
+		records = records[len(records)-*limit:]
+	}
+
+	return e.print(records, func(w io.Writer) {
+		fmt.Fprintln(w, "CALCULATED AT\tORDER SIZE\tSHIPPED\tPACK COUNT\tPACKS")
+		for _, record := range records {
+			shipped, packCount := sumPacks(record.Packs)
+			if len(record.Lines) > 0 && record.Totals != nil {
+				shipped, packCount = record.Totals.ShippedItems, record.Totals.PackCount
+			}
+
+			packs := formatPacks(record.Packs)
+			if len(record.Lines) > 0 {
+				packs = fmt.Sprintf("%d lines", len(record.Lines))
+			}
+
+			fmt.Fprintf(
+				w,
+				"%s\t%d\t%d\t%d\t%s\n",
+				record.CalculatedAt.Local().Format(time.DateTime),
+				record.OrderSize,
+				shipped,
+				packCount,
+				packs,
+			)
+		}
+	})
+}
+
+// print prints the response of the server as indented JSON, or a table written by human
+func (e *env) print(response any, human func(w io.Writer)) error {
+	if e.output == outputJSON {
+		data, err := json.MarshalIndent(response, "", "  ")
+		if err != nil {
+			return err
+		}
+
+		_, err = fmt.Fprintln(e.stdout, string(data))
+
+		return err
+	}
+
+	return e.table(human)
+}
+
+// table writes an aligned text table
+func (e *env) table(write func(w io.Writer)) error {
+	tw := tabwriter.NewWriter(e.stdout, 0, 0, 2, ' ', 0)
+	write(tw)
+
+	return tw.Flush()
+}
+
+// writePacks writes the properties of packs as table rows
+func writePacks(w io.Writer, packs client.Packs) {
+	fmt.Fprintln(w, "SIZE\tCOST\tMIN COUNT\tMAX COUNT\tWEIGHT\tVOLUME")
+	for _, pack := range packs {
+		maxCount := "-"
+		if pack.MaxCount > 0 {
+			maxCount = strconv.FormatInt(pack.MaxCount, 10)
+		}
+
+		fmt.Fprintf(
+			w,
+			"%d\t%d\t%d\t%s\t%d\t%d\n",
+			pack.Size,
+			pack.Cost,
+			pack.MinCount,
+			maxCount,
+			pack.Weight,
+			pack.Volume,
+		)
+	}
+}
+
+// writeCalculations writes the results of single-line calculations as table rows
+func writeCalculations(w io.Writer, results []client.CalculationResponse) {
+	fmt.Fprintln(w, "ORDER SIZE\tSHIPPED\tOVERSHIPMENT\tBACKORDER\tPACK COUNT\tPACKS")
+	for _, result := range results {
+		shipped, packCount := sumPacks(result.Packs)
+		fmt.Fprintf(
+			w,
+			"%d\t%d\t%d\t%d\t%d\t%s\n",
+			result.OrderSize,
+			shipped,
+			max(shipped-result.OrderSize, 0),
+			result.Backorder,
+			packCount,
+			formatPacks(result.Packs),
+		)
+	}
+}
+
+// parseSizes parses sizes given as arguments, each of them possibly a comma-separated list
+func parseSizes(args []string) ([]int64, error) {
+	var sizes []int64
+	for _, arg := range args {
+		for _, field := range strings.Split(arg, ",") {
+			if field == "" {
+				continue
+			}
+
+			size, err := strconv.ParseInt(field, 10, 64)
+			if err != nil {
+				return nil, newUsageError("%q is not a whole number", field)
+			}
+			sizes = append(sizes, size)
+		}
+	}
+
+	return sizes, nil
+}
+
+// sumPacks returns the number of items and the number of packs
+func sumPacks(packs map[client.PackSize]int64) (int64, int64) {
+	var items, count int64
+	for size, packCount := range packs {
+		items += int64(size) * packCount
+		count += packCount
+	}
+
+	return items, count
+}
+
+// formatPacks formats the counts of packs largest first, e.g. "2x500 1x250"
+func formatPacks(packs map[client.PackSize]int64) string {
+	sizes := slices.Sorted(maps.Keys(packs))
+	slices.Reverse(sizes)
+
+	parts := make([]string, 0, len(sizes))
+	for _, size := range sizes {
+		parts = append(parts, fmt.Sprintf("%dx%d", packs[size], size))
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// joinSizes formats sizes as a comma-separated list
+func joinSizes(sizes []int64) string {
+	parts := make([]string, 0, len(sizes))
+	for _, size := range sizes {
+		parts = append(parts, strconv.FormatInt(size, 10))
+	}
+
+	return strings.Join(parts, ", ")
+}
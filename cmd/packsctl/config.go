@@ -0,0 +1,91 @@
+package main
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	// defaultURL is the server URL used without a profile
+	defaultURL = "http://localhost:8080"
+	// defaultTimeout is the timeout of a request used if the profile has none
+	defaultTimeout = 30 * time.Second
+)
+
+// config holds the profiles of the servers the CLI talks to
+type config struct {
+	// Default is the name of the profile used if none is chosen
+	Default string `json:"default"`
+	// Profiles maps a profile name to its settings
+	Profiles map[string]profile `json:"profiles"`
+}
+
+// profile holds the settings of a server environment
+type profile struct {
+	// URL is the base URL of the server, e.g. http://localhost:8080
+	URL string `json:"url"`
+	// Timeout is the timeout of a request, e.g. "10s", defaultTimeout if it is empty
+	Timeout string `json:"timeout,omitempty"`
+}
+
+// configPath returns the path of the config file, PACKSCTL_CONFIG or packsctl/config.json
+// in the user config directory
+func configPath() (string, error) {
+	if path := os.Getenv("PACKSCTL_CONFIG"); path != "" {
+		return path, nil
+	}
+
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, "packsctl", "config.json"), nil
+}
+
+// loadConfig reads the config file, an empty config if the file does not exist and is not required
+func loadConfig(path string, required bool) (config, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) && !required {
+		return config{}, nil
+	}
+	if err != nil {
+		return config{}, err
+	}
+
+	var cfg config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return config{}, fmt.Errorf("config %s cannot be parsed: %w", path, err)
+	}
+
+	return cfg, nil
+}
+
+// resolveProfile returns the settings of the named profile, of the default profile if the name is empty,
+// with the URL replaced by url if it is set
+func resolveProfile(cfg config, name, url string) (profile, time.Duration, error) {
+	var result profile
+	if name = cmp.Or(name, cfg.Default); name != "" {
+		var ok bool
+		if result, ok = cfg.Profiles[name]; !ok {
+			return profile{}, 0, fmt.Errorf("profile %q not found", name)
+		}
+	}
+	result.URL = cmp.Or(url, result.URL, defaultURL)
+
+	timeout := defaultTimeout
+	if result.Timeout != "" {
+		var err error
+		if timeout, err = time.ParseDuration(result.Timeout); err != nil {
+			return profile{}, 0, fmt.Errorf("timeout of profile %q: %w", name, err)
+		}
+	}
+
+	return result, timeout, nil
+}
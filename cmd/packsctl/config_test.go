@@ -0,0 +1,126 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveProfile(t *testing.T) {
+	cfg := config{
+		Default: "staging",
+		Profiles: map[string]profile{
+			"staging": {URL: "https://staging.example.com"},
+			"prod":    {URL: "https://prod.example.com", Timeout: "5s"},
+			"broken":  {URL: "https://broken.example.com", Timeout: "soon"},
+		},
+	}
+
+	testCases := []struct {
+		name        string
+		cfg         config
+		profile     string
+		url         string
+		wantURL     string
+		wantTimeout time.Duration
+		wantErr     string
+	}{
+		{
+			name:        "No config",
+			wantURL:     defaultURL,
+			wantTimeout: defaultTimeout,
+		},
+		{
+			name:        "Default profile",
+			cfg:         cfg,
+			wantURL:     "https://staging.example.com",
+			wantTimeout: defaultTimeout,
+		},
+		{
+			name:        "Chosen profile with a timeout",
+			cfg:         cfg,
+			profile:     "prod",
+			wantURL:     "https://prod.example.com",
+			wantTimeout: 5 * time.Second,
+		},
+		{
+			name:        "URL overrides the profile",
+			cfg:         cfg,
+			profile:     "prod",
+			url:         "http://localhost:9000",
+			wantURL:     "http://localhost:9000",
+			wantTimeout: 5 * time.Second,
+		},
+		{
+			name:    "Unknown profile",
+			cfg:     cfg,
+			profile: "dev",
+			wantErr: `profile "dev" not found`,
+		},
+		{
+			name:    "Invalid timeout",
+			cfg:     cfg,
+			profile: "broken",
+			wantErr: `timeout of profile "broken"`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server, timeout, err := resolveProfile(tc.cfg, tc.profile, tc.url)
+			if tc.wantErr != "" {
+				require.ErrorContains(t, err, tc.wantErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.wantURL, server.URL)
+			require.Equal(t, tc.wantTimeout, timeout)
+		})
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	dir := t.TempDir()
+
+	// Test that a missing config is empty unless it is required
+	missing := filepath.Join(dir, "missing.json")
+	cfg, err := loadConfig(missing, false)
+	require.NoError(t, err)
+	require.Equal(t, config{}, cfg)
+
+	_, err = loadConfig(missing, true)
+	require.ErrorIs(t, err, os.ErrNotExist)
+
+	// Test reading the profiles
+	path := filepath.Join(dir, "config.json")
+	require.NoError(t, os.WriteFile(
+		path,
+		[]byte(`{"default":"prod","profiles":{"prod":{"url":"https://prod.example.com","timeout":"5s"}}}`),
+		0o644,
+	))
+
+	cfg, err = loadConfig(path, true)
+	require.NoError(t, err)
+	require.Equal(t, config{
+		Default:  "prod",
+		Profiles: map[string]profile{"prod": {URL: "https://prod.example.com", Timeout: "5s"}},
+	}, cfg)
+
+	// Test an invalid config
+	require.NoError(t, os.WriteFile(path, []byte(`{"profiles":[]}`), 0o644))
+	_, err = loadConfig(path, false)
+	require.ErrorContains(t, err, "cannot be parsed")
+}
+
+func TestConfigPath(t *testing.T) {
+	t.Setenv("PACKSCTL_CONFIG", "/etc/packsctl.json")
+
+	path, err := configPath()
+	require.NoError(t, err)
+	require.Equal(t, "/etc/packsctl.json", path)
+}
@@ -0,0 +1,220 @@
+// Command packsctl manages a running server over its HTTP API.
+//
+// Usage:
+//
+//	packsctl [flags] packs list
+//	packsctl [flags] packs add [-cost n] [-min-count n] [-max-count n] [-weight n] <size>
+//	packsctl [flags] packs remove <size>...
+//	packsctl [flags] packs replace <size>...
+//	packsctl [flags] calculate [-mode overship|undership] <order size>...
+//	packsctl [flags] history [-limit n]
+//	packsctl [flags] shell [-offline]
+//
+// The server is chosen by -url or by a profile of the config file, see config.
+// The exit code is 0 on success, 1 if the server rejects a request or cannot be reached
+// and 2 if the command line is invalid.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+
+	"github.com/alishercodecrafter/orderpackscalculator/pkg/client"
+)
+
+const (
+	// exitOK means the command succeeded
+	exitOK = 0
+	// exitFailed means the server rejected a request or could not be reached
+	exitFailed = 1
+	// exitUsage means the command line is invalid
+	exitUsage = 2
+)
+
+const (
+	// outputHuman prints tables and messages
+	outputHuman = "human"
+	// outputJSON prints the JSON responses of the server
+	outputJSON = "json"
+)
+
+// usage describes the commands
+const usage = `Usage: packsctl [flags] <command>
+
+Commands:
+  packs list                           list the available packs
+  packs add [flags] <size>             add a pack
+  packs remove <size>...               remove packs
+  packs replace <size>...              replace all packs
+  calculate [-mode mode] <order>...    calculate the packs of order sizes
+  history [-limit n]                   show the most recent calculations
+  shell [-offline]                     explore pack sets interactively
+
+Flags:
+`
+
+// usageError is an invalid command line
+type usageError struct {
+	err error
+	// reported means the error was already printed, e.g. by the flag package
+	reported bool
+}
+
+// Error returns the message of the underlying error
+func (e usageError) Error() string {
+	return e.err.Error()
+}
+
+// newUsageError creates a usage error with a formatted message
+func newUsageError(format string, args ...any) error {
+	return usageError{err: fmt.Errorf(format, args...)}
+}
+
+// env holds what the commands share
+type env struct {
+	client *client.Client
+	// url is the base URL of the server
+	url    string
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+	output string
+}
+
+func main() {
+	os.Exit(run(context.Background(), os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run executes the command line and returns the exit code
+func run(ctx context.Context, args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("packsctl", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprint(stderr, usage)
+		flags.PrintDefaults()
+	}
+
+	configFlag := flags.String("config", "", "config file of the profiles, PACKSCTL_CONFIG or the user config by default")
+	profileFlag := flags.String("profile", os.Getenv("PACKSCTL_PROFILE"), "profile of the server to use")
+	urlFlag := flags.String("url", "", "base URL of the server, overrides the profile")
+	output := flags.String("output", outputHuman, "output format: human or json")
+
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+
+		return exitUsage
+	}
+
+	if *output != outputHuman && *output != outputJSON {
+		return fail(stderr, newUsageError("output must be %s or %s", outputHuman, outputJSON))
+	}
+
+	path := *configFlag
+	if path == "" {
+		var err error
+		if path, err = configPath(); err != nil {
+			return fail(stderr, err)
+		}
+	}
+
+	cfg, err := loadConfig(path, *configFlag != "")
+	if err != nil {
+		return fail(stderr, usageError{err: err})
+	}
+
+	server, timeout, err := resolveProfile(cfg, *profileFlag, *urlFlag)
+	if err != nil {
+		return fail(stderr, usageError{err: err})
+	}
+
+	e := &env{
+		client: client.New(server.URL, client.WithHTTPClient(&http.Client{Timeout: timeout})),
+		url:    server.URL,
+		stdin:  stdin,
+		stdout: stdout,
+		stderr: stderr,
+		output: *output,
+	}
+
+	if err := e.dispatch(ctx, flags.Args()); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+
+		return fail(stderr, err)
+	}
+
+	return exitOK
+}
+
+// fail reports an error and returns its exit code
+func fail(stderr io.Writer, err error) int {
+	var usageErr usageError
+	if errors.As(err, &usageErr) {
+		if !usageErr.reported {
+			fmt.Fprintf(stderr, "packsctl: %v\n", err)
+		}
+
+		return exitUsage
+	}
+
+	fmt.Fprintf(stderr, "packsctl: %v\n", err)
+
+	return exitFailed
+}
+
+// dispatch runs the command named by the first arguments
+func (e *env) dispatch(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return newUsageError("a command is required, see packsctl -h")
+	}
+
+	switch args[0] {
+	case "packs":
+		if len(args) < 2 {
+			return newUsageError("packs requires a subcommand: list, add, remove or replace")
+		}
+
+		switch args[1] {
+		case "list":
+			return e.listPacks(ctx, args[2:])
+		case "add":
+			return e.addPack(ctx, args[2:])
+		case "remove":
+			return e.removePacks(ctx, args[2:])
+		case "replace":
+			return e.replacePacks(ctx, args[2:])
+		default:
+			return newUsageError("unknown packs subcommand %q", args[1])
+		}
+	case "calculate":
+		return e.calculate(ctx, args[1:])
+	case "history":
+		return e.history(ctx, args[1:])
+	case "shell":
+		return e.shell(ctx, args[1:])
+	default:
+		return newUsageError("unknown command %q", args[0])
+	}
+}
+
+// parseFlags parses the flags of a command, reporting invalid flags as usage errors
+func (e *env) parseFlags(flags *flag.FlagSet, args []string) error {
+	flags.SetOutput(e.stderr)
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+
+		return usageError{err: err, reported: true}
+	}
+
+	return nil
+}
@@ -0,0 +1,252 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/alishercodecrafter/orderpackscalculator/internal/controller"
+	"github.com/alishercodecrafter/orderpackscalculator/internal/model"
+	"github.com/alishercodecrafter/orderpackscalculator/internal/repository"
+	"github.com/alishercodecrafter/orderpackscalculator/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestServer starts a server of the API with the default packs and returns its URL and repository
+func newTestServer(t *testing.T) (string, *repository.MemoryRepository) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	repo := repository.NewMemoryRepository()
+	router := gin.New()
+	controller.NewPacksController(service.NewPacksService(repo)).RegisterRoutes(router.Group("/api"))
+
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+
+	return server.URL, repo
+}
+
+// runCommand runs packsctl with the arguments and stdin and returns its exit code, stdout and stderr
+func runCommand(args []string, stdin string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+	exitCode := run(context.Background(), args, strings.NewReader(stdin), &stdout, &stderr)
+
+	return exitCode, stdout.String(), stderr.String()
+}
+
+func TestRun(t *testing.T) {
+	url, repo := newTestServer(t)
+	t.Setenv("PACKSCTL_CONFIG", filepath.Join(t.TempDir(), "missing.json"))
+
+	testCases := []struct {
+		name       string
+		args       []string
+		exitCode   int
+		wantStdout string
+		wantStderr string
+	}{
+		{
+			name:     "List packs",
+			args:     []string{"-url", url, "packs", "list"},
+			exitCode: exitOK,
+			wantStdout: "SIZE  COST  MIN COUNT  MAX COUNT  WEIGHT  VOLUME\n" +
+				"250   0     0          -          0       0\n" +
+				"500   0     0          -          0       0\n" +
+				"1000  0     0          -          0       0\n" +
+				"2000  0     0          -          0       0\n" +
+				"5000  0     0          -          0       0\n",
+		},
+		{
+			name:       "Add a pack",
+			args:       []string{"-url", url, "packs", "add", "-cost", "12", "-max-count", "2", "750"},
+			exitCode:   exitOK,
+			wantStdout: "Added pack size 750\n",
+		},
+		{
+			name:       "Add an existing pack",
+			args:       []string{"-url", url, "packs", "add", "750"},
+			exitCode:   exitFailed,
+			wantStderr: "packsctl: pack size 750 already exists (PACK_EXISTS)\n",
+		},
+		{
+			name:       "Remove packs",
+			args:       []string{"-url", url, "packs", "remove", "750,2000"},
+			exitCode:   exitOK,
+			wantStdout: "Removed pack size 750\nRemoved pack size 2000\n",
+		},
+		{
+			name:       "Remove a missing pack",
+			args:       []string{"-url", url, "packs", "remove", "42"},
+			exitCode:   exitFailed,
+			wantStderr: "packsctl: pack size 42: pack size 42 not found (PACK_NOT_FOUND)\n",
+		},
+		{
+			name:     "Calculate",
+			args:     []string{"-url", url, "calculate", "-mode", "undership", "501", "1"},
+			exitCode: exitOK,
+			wantStdout: "ORDER SIZE  SHIPPED  OVERSHIPMENT  BACKORDER  PACK COUNT  PACKS\n" +
+				"501         500      0             1          1           1x500\n" +
+				"1           0        0             1          0           \n",
+		},
+		{
+			name:       "Replace packs",
+			args:       []string{"-url", url, "packs", "replace", "23", "31,53"},
+			exitCode:   exitOK,
+			wantStdout: "Replaced the packs with sizes 23, 31, 53\n",
+		},
+		{
+			name:       "Unreachable server",
+			args:       []string{"-url", "http://127.0.0.1:1", "packs", "list"},
+			exitCode:   exitFailed,
+			wantStderr: "connection refused",
+		},
+		{
+			name:       "No command",
+			args:       []string{"-url", url},
+			exitCode:   exitUsage,
+			wantStderr: "packsctl: a command is required, see packsctl -h\n",
+		},
+		{
+			name:       "Unknown command",
+			args:       []string{"-url", url, "orders"},
+			exitCode:   exitUsage,
+			wantStderr: "packsctl: unknown command \"orders\"\n",
+		},
+		{
+			name:       "Missing packs subcommand",
+			args:       []string{"-url", url, "packs"},
+			exitCode:   exitUsage,
+			wantStderr: "packsctl: packs requires a subcommand: list, add, remove or replace\n",
+		},
+		{
+			name:       "Unknown packs subcommand",
+			args:       []string{"-url", url, "packs", "move"},
+			exitCode:   exitUsage,
+			wantStderr: "packsctl: unknown packs subcommand \"move\"\n",
+		},
+		{
+			name:       "Arguments of packs list",
+			args:       []string{"-url", url, "packs", "list", "250"},
+			exitCode:   exitUsage,
+			wantStderr: "packsctl: packs list takes no arguments\n",
+		},
+		{
+			name:       "Several sizes to add",
+			args:       []string{"-url", url, "packs", "add", "250", "500"},
+			exitCode:   exitUsage,
+			wantStderr: "packsctl: packs add requires exactly one pack size\n",
+		},
+		{
+			name:       "Invalid size",
+			args:       []string{"-url", url, "packs", "remove", "abc"},
+			exitCode:   exitUsage,
+			wantStderr: "packsctl: \"abc\" is not a whole number\n",
+		},
+		{
+			name:       "Unknown command flag",
+			args:       []string{"-url", url, "calculate", "-fast", "1"},
+			exitCode:   exitUsage,
+			wantStderr: "flag provided but not defined: -fast",
+		},
+		{
+			name:       "Negative history limit",
+			args:       []string{"-url", url, "history", "-limit", "-1"},
+			exitCode:   exitUsage,
+			wantStderr: "packsctl: limit must not be negative\n",
+		},
+		{
+			name:       "Unknown output",
+			args:       []string{"-url", url, "-output", "yaml", "packs", "list"},
+			exitCode:   exitUsage,
+			wantStderr: "packsctl: output must be human or json\n",
+		},
+		{
+			name:       "Unknown profile",
+			args:       []string{"-profile", "prod", "packs", "list"},
+			exitCode:   exitUsage,
+			wantStderr: "packsctl: profile \"prod\" not found\n",
+		},
+		{
+			name:       "Missing config",
+			args:       []string{"-config", filepath.Join(t.TempDir(), "missing.json"), "packs", "list"},
+			exitCode:   exitUsage,
+			wantStderr: "no such file or directory",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			exitCode, stdout, stderr := runCommand(tc.args, "")
+
+			require.Equal(t, tc.exitCode, exitCode, stderr)
+			require.Equal(t, tc.wantStdout, stdout)
+			if tc.wantStderr == "" {
+				require.Empty(t, stderr)
+			} else {
+				require.Contains(t, stderr, tc.wantStderr)
+			}
+		})
+	}
+
+	require.Equal(t, model.Packs{{Size: 23}, {Size: 31}, {Size: 53}}, repo.GetPacks())
+}
+
+func TestRun_Profile(t *testing.T) {
+	url, _ := newTestServer(t)
+
+	path := filepath.Join(t.TempDir(), "config.json")
+	cfg := fmt.Sprintf(`{"default":"local","profiles":{"local":{"url":%q,"timeout":"5s"}}}`, url)
+	require.NoError(t, os.WriteFile(path, []byte(cfg), 0o644))
+
+	// Test that the default profile of the config file is used
+	exitCode, stdout, stderr := runCommand([]string{"-config", path, "packs", "add", "750"}, "")
+	require.Equal(t, exitOK, exitCode, stderr)
+	require.Equal(t, "Added pack size 750\n", stdout)
+
+	// Test that the config file is found by PACKSCTL_CONFIG and the profile by PACKSCTL_PROFILE
+	t.Setenv("PACKSCTL_CONFIG", path)
+	t.Setenv("PACKSCTL_PROFILE", "local")
+
+	exitCode, stdout, stderr = runCommand([]string{"packs", "remove", "750"}, "")
+	require.Equal(t, exitOK, exitCode, stderr)
+	require.Equal(t, "Removed pack size 750\n", stdout)
+}
+
+func TestRun_JSON(t *testing.T) {
+	url, _ := newTestServer(t)
+
+	exitCode, stdout, stderr := runCommand([]string{"-url", url, "-output", "json", "packs", "list"}, "")
+	require.Equal(t, exitOK, exitCode, stderr)
+
+	var packs model.Packs
+	require.NoError(t, json.Unmarshal([]byte(stdout), &packs))
+	require.Equal(t, model.Packs{{Size: 250}, {Size: 500}, {Size: 1000}, {Size: 2000}, {Size: 5000}}, packs)
+
+	exitCode, stdout, stderr = runCommand([]string{"-url", url, "-output", "json", "packs", "remove", "250"}, "")
+	require.Equal(t, exitOK, exitCode, stderr)
+	require.JSONEq(t, `[{"success":true}]`, stdout)
+
+	for _, orderSize := range []string{"501", "12001", "251"} {
+		exitCode, _, stderr = runCommand([]string{"-url", url, "calculate", orderSize}, "")
+		require.Equal(t, exitOK, exitCode, stderr)
+	}
+
+	// Test that the history is limited to the most recent calculations
+	exitCode, stdout, stderr = runCommand([]string{"-url", url, "-output", "json", "history", "-limit", "2"}, "")
+	require.Equal(t, exitOK, exitCode, stderr)
+
+	var records []model.CalculationRecord
+	require.NoError(t, json.Unmarshal([]byte(stdout), &records))
+	require.Len(t, records, 2)
+	require.Equal(t, int64(12001), records[0].OrderSize)
+	require.Equal(t, int64(251), records[1].OrderSize)
+	require.Equal(t, map[model.PackSize]int64{500: 1}, records[1].Packs)
+}
@@ -0,0 +1,371 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/alishercodecrafter/orderpackscalculator/internal/model"
+	"github.com/alishercodecrafter/orderpackscalculator/internal/repository"
+	"github.com/alishercodecrafter/orderpackscalculator/internal/service"
+	"github.com/alishercodecrafter/orderpackscalculator/pkg/client"
+)
+
+// shellPrompt is printed before every command of the shell
+const shellPrompt = "packs> "
+
+// shellHelp describes the commands of the shell
+const shellHelp = `Commands:
+  list                     list the explored packs
+  add <size>...            add packs
+  remove <size>...         remove packs
+  replace <size>...        replace all packs
+  undo                     undo the last change of the packs
+  calc <order>...          calculate the packs of order sizes
+  compare <order>...       compare the explored packs with the baseline for order sizes
+  baseline                 make the explored packs the baseline
+  load <file>              replace the packs with the sizes in a file
+  export <file>            write the pack sizes to a file, one per line
+  pull                     replace the packs with the packs of the server
+  push                     replace the packs of the server with the explored packs
+  help                     show this help
+  quit                     leave the shell
+`
+
+// session is the state of the shell: the explored pack set, the baseline it is compared with
+// and the pack sets before each change
+type session struct {
+	env *env
+	// current holds the explored packs
+	current *repository.MemoryRepository
+	// explorer calculates with the explored packs
+	explorer *service.PacksServiceImpl
+	// baseline holds the packs the explored packs are compared with
+	baseline *repository.MemoryRepository
+	// comparer compares candidate packs with the baseline
+	comparer *service.PacksServiceImpl
+	// undo holds the explored packs before each change, the most recent last
+	undo []model.Packs
+}
+
+// shell explores pack sets interactively in memory, starting from the packs of the server
+func (e *env) shell(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("shell", flag.ContinueOnError)
+	offline := flags.Bool("offline", false, "start from the default pack sizes instead of the packs of the server")
+	if err := e.parseFlags(flags, args); err != nil {
+		return err
+	}
+
+	if flags.NArg() > 0 {
+		return newUsageError("shell takes no arguments")
+	}
+
+	s := &session{
+		env:      e,
+		current:  repository.NewMemoryRepository(),
+		baseline: repository.NewMemoryRepository(),
+	}
+	s.explorer = service.NewPacksService(s.current)
+	s.comparer = service.NewPacksService(s.baseline)
+
+	if !*offline {
+		if err := s.pull(ctx); err != nil {
+			fmt.Fprintf(e.stderr, "packs of the server cannot be pulled, starting from the default pack sizes: %v\n", err)
+		}
+		s.undo = nil
+	}
+	fmt.Fprintln(e.stdout, `Type "help" for the commands.`)
+
+	scanner := bufio.NewScanner(e.stdin)
+	for {
+		fmt.Fprint(e.stdout, shellPrompt)
+		if !scanner.Scan() {
+			fmt.Fprintln(e.stdout)
+
+			return scanner.Err()
+		}
+
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+
+		if fields[0] == "quit" || fields[0] == "exit" {
+			return nil
+		}
+
+		if err := s.execute(ctx, fields[0], fields[1:]); err != nil {
+			fmt.Fprintf(e.stderr, "error: %v\n", err)
+		}
+	}
+}
+
+// execute runs a command of the shell
+func (s *session) execute(ctx context.Context, command string, args []string) error {
+	switch command {
+	case "help":
+		fmt.Fprint(s.env.stdout, shellHelp)
+
+		return nil
+	case "list":
+		packs, err := convert[client.Packs](s.current.GetPacks())
+		if err != nil {
+			return err
+		}
+
+		return s.env.table(func(w io.Writer) {
+			writePacks(w, packs)
+		})
+	case "add", "remove", "replace":
+		sizes, err := parseSizes(args)
+		if err != nil {
+			return err
+		}
+
+		if len(sizes) == 0 {
+			return fmt.Errorf("%s requires at least one pack size", command)
+		}
+
+		return s.change(command, sizes)
+	case "undo":
+		return s.undoChange()
+	case "calc":
+		return s.calculate(ctx, args)
+	case "compare":
+		return s.compare(ctx, args)
+	case "baseline":
+		return s.baseline.ReplacePacks(s.current.GetPacks())
+	case "load":
+		if len(args) != 1 {
+			return errors.New("load requires a file")
+		}
+
+		return s.load(args[0])
+	case "export":
+		if len(args) != 1 {
+			return errors.New("export requires a file")
+		}
+
+		return s.export(args[0])
+	case "pull":
+		return s.pull(ctx)
+	case "push":
+		return s.push(ctx)
+	default:
+		return fmt.Errorf("unknown command %q, type \"help\" for the commands", command)
+	}
+}
+
+// change adds, removes or replaces packs of the sizes, all of them or none
+func (s *session) change(command string, sizes []int64) error {
+	previous := s.current.GetPacks()
+
+	var err error
+	switch command {
+	case "add":
+		for _, size := range sizes {
+			if err = s.explorer.AddPack(model.Pack{Size: model.PackSize(size)}); err != nil {
+				break
+			}
+		}
+	case "remove":
+		for _, size := range sizes {
+			if err = s.explorer.RemovePack(model.PackSize(size)); err != nil {
+				break
+			}
+		}
+	default:
+		packs := make(model.Packs, 0, len(sizes))
+		for _, size := range sizes {
+			packs = append(packs, model.Pack{Size: model.PackSize(size)})
+		}
+		err = s.explorer.ReplacePacks(packs)
+	}
+
+	if err != nil {
+		// the previous packs were valid, restoring them cannot fail
+		_ = s.current.ReplacePacks(previous)
+
+		return err
+	}
+	s.undo = append(s.undo, previous)
+
+	return nil
+}
+
+// undoChange restores the packs before the last change
+func (s *session) undoChange() error {
+	if len(s.undo) == 0 {
+		return errors.New("there is nothing to undo")
+	}
+
+	previous := s.undo[len(s.undo)-1]
+	s.undo = s.undo[:len(s.undo)-1]
+
+	return s.current.ReplacePacks(previous)
+}
+
+// replaceAll replaces the explored packs so that the change can be undone
+func (s *session) replaceAll(packs model.Packs) error {
+	previous := s.current.GetPacks()
+	if err := s.explorer.ReplacePacks(packs); err != nil {
+		return err
+	}
+	s.undo = append(s.undo, previous)
+
+	return nil
+}
+
+// calculate prints the packs of order sizes with the explored packs
+func (s *session) calculate(ctx context.Context, args []string) error {
+	orderSizes, err := parseSizes(args)
+	if err != nil {
+		return err
+	}
+
+	if len(orderSizes) == 0 {
+		return errors.New("calc requires at least one order size")
+	}
+
+	results := make([]model.CalculationResponse, 0, len(orderSizes))
+	for _, orderSize := range orderSizes {
+		result, err := s.explorer.Calculate(ctx, model.CalculationRequest{OrderSize: orderSize})
+		if err != nil {
+			return fmt.Errorf("order size %d: %w", orderSize, err)
+		}
+		results = append(results, result)
+	}
+
+	responses, err := convert[[]client.CalculationResponse](results)
+	if err != nil {
+		return err
+	}
+
+	return s.env.table(func(w io.Writer) {
+		writeCalculations(w, responses)
+	})
+}
+
+// compare prints the shipments of order sizes with the baseline and with the explored packs
+func (s *session) compare(ctx context.Context, args []string) error {
+	orderSizes, err := parseSizes(args)
+	if err != nil {
+		return err
+	}
+
+	comparison, err := s.comparer.ComparePacks(ctx, model.ComparisonRequest{
+		Packs:      s.current.GetPacks(),
+		OrderSizes: orderSizes,
+	})
+	if err != nil {
+		return err
+	}
+
+	result, err := convert[client.ComparisonResponse](comparison)
+	if err != nil {
+		return err
+	}
+
+	return s.env.table(func(w io.Writer) {
+		fmt.Fprintln(w, "ORDER SIZE\tBASELINE\tEXPLORED\tSHIPPED DELTA\tPACK COUNT DELTA")
+		for _, order := range result.Orders {
+			fmt.Fprintf(
+				w,
+				"%d\t%s\t%s\t%+d\t%+d\n",
+				order.OrderSize,
+				formatPacks(order.Current.Packs),
+				formatPacks(order.Candidate.Packs),
+				order.Candidate.ShippedItems-order.Current.ShippedItems,
+				order.Candidate.PackCount-order.Current.PackCount,
+			)
+		}
+		fmt.Fprintf(w, "TOTAL\t\t\t%+d\t%+d\n", result.ShippedItemsDelta, result.PackCountDelta)
+	})
+}
+
+// load replaces the explored packs with the sizes in a file
+func (s *session) load(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	sizes, err := parseSizes(strings.Fields(string(data)))
+	if err != nil {
+		return err
+	}
+
+	if len(sizes) == 0 {
+		return fmt.Errorf("%s has no pack sizes", path)
+	}
+
+	return s.change("replace", sizes)
+}
+
+// export writes the explored pack sizes to a file, one per line, as read by packcalc -packs-file
+func (s *session) export(path string) error {
+	var b strings.Builder
+	for _, pack := range s.current.GetPacks() {
+		fmt.Fprintln(&b, pack.Size)
+	}
+
+	return os.WriteFile(path, []byte(b.String()), 0o644)
+}
+
+// pull replaces the explored packs and the baseline with the packs of the server
+func (s *session) pull(ctx context.Context) error {
+	serverPacks, err := s.env.client.GetPacks(ctx)
+	if err != nil {
+		return err
+	}
+
+	packs, err := convert[model.Packs](serverPacks)
+	if err != nil {
+		return err
+	}
+
+	if err := s.replaceAll(packs); err != nil {
+		return err
+	}
+
+	return s.baseline.ReplacePacks(packs)
+}
+
+// push replaces the packs of the server with the explored packs and makes them the baseline
+func (s *session) push(ctx context.Context) error {
+	packs := s.current.GetPacks()
+	serverPacks, err := convert[client.Packs](packs)
+	if err != nil {
+		return err
+	}
+
+	if err := s.env.client.ReplacePacks(ctx, serverPacks); err != nil {
+		return err
+	}
+	fmt.Fprintf(s.env.stdout, "Pushed %d pack sizes to %s\n", len(packs), s.env.url)
+
+	return s.baseline.ReplacePacks(packs)
+}
+
+// convert converts a value between the types of the service the shell calculates with and the types of the API
+// through their JSON encoding, which is the same for both
+func convert[T any](v any) (T, error) {
+	var result T
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return result, err
+	}
+
+	if err := json.Unmarshal(data, &result); err != nil {
+		return result, err
+	}
+
+	return result, nil
+}
@@ -0,0 +1,222 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/alishercodecrafter/orderpackscalculator/internal/model"
+	"github.com/stretchr/testify/require"
+)
+
+// offline starts the shell from the default pack sizes without a server
+var offline = []string{"shell", "-offline"}
+
+// runShell runs packsctl with the arguments and the shell commands as its input,
+// and returns the output of the commands without the prompts and the errors
+func runShell(t *testing.T, args []string, commands ...string) (string, string) {
+	t.Helper()
+
+	exitCode, stdout, stderr := runCommand(args, strings.Join(commands, "\n")+"\n")
+	require.Equal(t, exitOK, exitCode, stderr)
+
+	stdout = strings.TrimPrefix(stdout, "Type \"help\" for the commands.\n")
+
+	return strings.ReplaceAll(stdout, shellPrompt, ""), stderr
+}
+
+// readExport returns the pack sizes of a file written by the export command, separated by commas
+func readExport(t *testing.T, path string) string {
+	t.Helper()
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	return strings.Join(strings.Fields(string(data)), ",")
+}
+
+func TestShell_Undo(t *testing.T) {
+	dir := t.TempDir()
+	export := func(name string) string {
+		return "export " + filepath.Join(dir, name)
+	}
+
+	stdout, stderr := runShell(
+		t,
+		offline,
+		"undo",
+		"replace 250,500",
+		"add 750",
+		"remove 250",
+		export("changed.txt"),
+		// a failed change leaves the packs as they were and is not recorded for undo
+		"add 1000 500",
+		"remove 42",
+		export("failed.txt"),
+		"undo",
+		export("undo1.txt"),
+		"undo",
+		export("undo2.txt"),
+		"undo",
+		export("undo3.txt"),
+		"undo",
+		"calc 501",
+	)
+
+	require.Equal(t, "500,750", readExport(t, filepath.Join(dir, "changed.txt")))
+	require.Equal(t, "500,750", readExport(t, filepath.Join(dir, "failed.txt")))
+	require.Equal(t, "250,500,750", readExport(t, filepath.Join(dir, "undo1.txt")))
+	require.Equal(t, "250,500", readExport(t, filepath.Join(dir, "undo2.txt")))
+	require.Equal(t, "250,500,1000,2000,5000", readExport(t, filepath.Join(dir, "undo3.txt")))
+
+	require.Equal(
+		t,
+		"error: there is nothing to undo\n"+
+			"error: pack size 500 already exists\n"+
+			"error: pack size 42 not found\n"+
+			"error: there is nothing to undo\n",
+		stderr,
+	)
+
+	// Test that the calculations use the restored packs
+	require.Equal(
+		t,
+		"ORDER SIZE  SHIPPED  OVERSHIPMENT  BACKORDER  PACK COUNT  PACKS\n"+
+			"501         750      249           0          2           1x500 1x250\n\n",
+		stdout,
+	)
+}
+
+func TestShell_BaselineAndCompare(t *testing.T) {
+	stdout, stderr := runShell(
+		t,
+		offline,
+		"replace 250 500",
+		"baseline",
+		"add 300",
+		"compare 550 251",
+		"calc 550",
+	)
+	require.Empty(t, stderr)
+
+	require.Equal(
+		t,
+		"ORDER SIZE  BASELINE     EXPLORED     SHIPPED DELTA  PACK COUNT DELTA\n"+
+			"550         1x500 1x250  1x300 1x250  -200           +0\n"+
+			"251         1x500        1x300        -200           +0\n"+
+			"TOTAL                                 -400           +0\n"+
+			"ORDER SIZE  SHIPPED  OVERSHIPMENT  BACKORDER  PACK COUNT  PACKS\n"+
+			"550         550      0             0          2           1x300 1x250\n\n",
+		stdout,
+	)
+}
+
+func TestShell_LoadAndExport(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "packs.txt")
+	require.NoError(t, os.WriteFile(path, []byte("23\n31, 53\n"), 0o644))
+	empty := filepath.Join(dir, "empty.txt")
+	require.NoError(t, os.WriteFile(empty, []byte("\n"), 0o644))
+
+	_, stderr := runShell(
+		t,
+		offline,
+		"load "+path,
+		"export "+filepath.Join(dir, "loaded.txt"),
+		// a load is undone like any other change
+		"undo",
+		"export "+filepath.Join(dir, "undone.txt"),
+		"load "+path,
+		"load "+empty,
+		"load "+filepath.Join(dir, "missing.txt"),
+		"load",
+		"export",
+		"export "+filepath.Join(dir, "exported.txt"),
+	)
+
+	require.Equal(t, "23,31,53", readExport(t, filepath.Join(dir, "loaded.txt")))
+	require.Equal(t, "250,500,1000,2000,5000", readExport(t, filepath.Join(dir, "undone.txt")))
+
+	data, err := os.ReadFile(filepath.Join(dir, "exported.txt"))
+	require.NoError(t, err)
+	require.Equal(t, "23\n31\n53\n", string(data))
+
+	require.Contains(t, stderr, "error: "+empty+" has no pack sizes\n")
+	require.Contains(t, stderr, "missing.txt: no such file or directory\n")
+	require.Contains(t, stderr, "error: load requires a file\n")
+	require.Contains(t, stderr, "error: export requires a file\n")
+}
+
+func TestShell_PullAndPush(t *testing.T) {
+	url, repo := newTestServer(t)
+	require.NoError(t, repo.ReplacePacks(model.Packs{{Size: 23}, {Size: 31}, {Size: 53}}))
+	dir := t.TempDir()
+
+	stdout, stderr := runShell(
+		t,
+		[]string{"-url", url, "shell"},
+		// the packs pulled at the start are the baseline and cannot be undone
+		"undo",
+		"export "+filepath.Join(dir, "started.txt"),
+		"add 60",
+		"compare 60",
+		"push",
+	)
+
+	require.Equal(t, "error: there is nothing to undo\n", stderr)
+	require.Equal(t, "23,31,53", readExport(t, filepath.Join(dir, "started.txt")))
+	require.Equal(
+		t,
+		"ORDER SIZE  BASELINE  EXPLORED  SHIPPED DELTA  PACK COUNT DELTA\n"+
+			"60          2x31      1x60      -2             -1\n"+
+			"TOTAL                           -2             -1\n"+
+			"Pushed 4 pack sizes to "+url+"\n\n",
+		stdout,
+	)
+	require.Equal(t, model.Packs{{Size: 23}, {Size: 31}, {Size: 53}, {Size: 60}}, repo.GetPacks())
+
+	// Test that a pull replaces the explored packs and can be undone
+	require.NoError(t, repo.ReplacePacks(model.Packs{{Size: 250}}))
+
+	_, stderr = runShell(
+		t,
+		[]string{"-url", url, "shell", "-offline"},
+		"pull",
+		"export "+filepath.Join(dir, "pulled.txt"),
+		"undo",
+		"export "+filepath.Join(dir, "undone.txt"),
+	)
+	require.Empty(t, stderr)
+	require.Equal(t, "250", readExport(t, filepath.Join(dir, "pulled.txt")))
+	require.Equal(t, "250,500,1000,2000,5000", readExport(t, filepath.Join(dir, "undone.txt")))
+
+	// Test that the shell starts from the default packs if the server cannot be reached
+	_, stderr = runShell(
+		t,
+		[]string{"-url", "http://127.0.0.1:1", "shell"},
+		"export "+filepath.Join(dir, "unreachable.txt"),
+		"push",
+	)
+	require.Contains(t, stderr, "packs of the server cannot be pulled, starting from the default pack sizes")
+	require.Equal(t, 2, strings.Count(stderr, "connection refused"), stderr)
+	require.Equal(t, "250,500,1000,2000,5000", readExport(t, filepath.Join(dir, "unreachable.txt")))
+}
+
+func TestShell_Commands(t *testing.T) {
+	stdout, stderr := runShell(t, offline, "help", "", "add", "calc", "remove x", "orders", "quit", "list")
+
+	require.Equal(t, shellHelp, stdout)
+	require.Equal(
+		t,
+		"error: add requires at least one pack size\n"+
+			"error: calc requires at least one order size\n"+
+			"error: \"x\" is not a whole number\n"+
+			"error: unknown command \"orders\", type \"help\" for the commands\n",
+		stderr,
+	)
+
+	exitCode, _, stderr := runCommand([]string{"shell", "now"}, "")
+	require.Equal(t, exitUsage, exitCode)
+	require.Equal(t, "packsctl: shell takes no arguments\n", stderr)
+}
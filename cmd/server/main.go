@@ -1,22 +1,61 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"log"
+	"net"
+	"net/http"
+	"net/netip"
 	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
 
+	packsv1 "github.com/alishercodecrafter/orderpackscalculator/api/packs/v1"
 	_ "github.com/alishercodecrafter/orderpackscalculator/docs" // Import generated docs
 	"github.com/alishercodecrafter/orderpackscalculator/internal/controller"
+	"github.com/alishercodecrafter/orderpackscalculator/internal/graphqlapi"
+	"github.com/alishercodecrafter/orderpackscalculator/internal/grpcserver"
+	"github.com/alishercodecrafter/orderpackscalculator/internal/model"
+	"github.com/alishercodecrafter/orderpackscalculator/internal/netguard"
 	"github.com/alishercodecrafter/orderpackscalculator/internal/repository"
 	"github.com/alishercodecrafter/orderpackscalculator/internal/service"
+	"github.com/alishercodecrafter/orderpackscalculator/internal/webhook"
 	"github.com/gin-gonic/gin"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/reflection"
 )
 
+// shutdownTimeout is the time the servers and the webhook deliveries have to finish once a shutdown is requested
+const shutdownTimeout = 15 * time.Second
+
 func main() {
+	// Stop on an interrupt or a termination request
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
 	// Create repository, service, and controller
-	repo := repository.NewMemoryRepository()
-	svc := service.NewPacksService(repo)
+	repo := newRepository()
+	addresses := webhookAddressPolicy()
+	dispatcher := webhook.NewDispatcher(
+		repo,
+		webhook.WithRetryPolicy(webhookRetryPolicy()),
+		webhook.WithAddressPolicy(addresses),
+	)
+	dispatcher.Start(context.Background())
+	svc := service.NewPacksService(
+		repo,
+		append(
+			serviceOptions(),
+			service.WithWebhookNotifier(dispatcher),
+			service.WithWebhookAddressPolicy(addresses),
+		)...,
+	)
 	ctrl := controller.NewPacksController(svc)
 
 	// Create Gin router
@@ -30,17 +69,21 @@ func main() {
 	router.GET("/", ctrl.GetIndex)
 
 	// API routes
-	api := router.Group("/api")
-	{
-		api.GET("/packs", ctrl.GetPacks)
-		api.POST("/packs", ctrl.AddPack)
-		api.DELETE("/packs/:size", ctrl.RemovePack)
-		api.POST("/calculate", ctrl.CalculatePacks)
-	}
+	ctrl.RegisterRoutes(router.Group("/api"))
+
+	// GraphQL endpoint
+	router.POST("/graphql", gin.WrapH(graphqlapi.NewHandler(svc)))
 
 	// Swagger documentation endpoint
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
+	// Serve the gRPC API on its own port
+	grpcPort := os.Getenv("GRPC_PORT")
+	if grpcPort == "" {
+		grpcPort = "9090"
+	}
+	grpcServer := serveGRPC(svc, grpcPort)
+
 	// Get port from environment or use default
 	port := os.Getenv("PORT")
 	if port == "" {
@@ -49,7 +92,184 @@ func main() {
 
 	log.Printf("Server starting on port %s...", port)
 	log.Printf("Swagger documentation available at http://localhost:%s/swagger/index.html", port)
-	if err := router.Run(":" + port); err != nil {
-		log.Fatalf("Failed to start server: %v", err)
+	server := &http.Server{Addr: ":" + port, Handler: router}
+	go func() {
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatalf("Failed to start server: %v", err)
+		}
+	}()
+
+	<-ctx.Done()
+	stop()
+	log.Printf("Shutting down...")
+	shutdown(server, grpcServer, dispatcher)
+}
+
+// shutdown stops the servers and then the webhook deliveries, waiting at most shutdownTimeout for the requests
+// being served and the queued deliveries. The deliveries it could not make are dead-lettered.
+func shutdown(server *http.Server, grpcServer *grpc.Server, dispatcher *webhook.Dispatcher) {
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	if err := server.Shutdown(ctx); err != nil {
+		log.Printf("Failed to shut down the server gracefully: %v", err)
+		_ = server.Close()
+	}
+
+	stopped := make(chan struct{})
+	go func() {
+		grpcServer.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		log.Printf("Failed to shut down the gRPC server gracefully: %v", ctx.Err())
+		grpcServer.Stop()
+	}
+
+	if err := dispatcher.Shutdown(ctx); err != nil {
+		log.Printf("Failed to deliver the queued webhook events, they are dead-lettered: %v", err)
 	}
 }
+
+// newRepository creates the repository, persisting the pack changes in PACKS_LOG_DIR if it is set
+func newRepository() *repository.MemoryRepository {
+	dir := os.Getenv("PACKS_LOG_DIR")
+	if dir == "" {
+		return repository.NewMemoryRepository()
+	}
+
+	repo, err := repository.OpenMemoryRepository(dir)
+	if err != nil {
+		log.Fatalf("Invalid PACKS_LOG_DIR %q: %v", dir, err)
+	}
+	log.Printf("Pack changes are persisted in %s", dir)
+
+	return repo
+}
+
+// serveGRPC starts serving the gRPC API of the service on the port
+func serveGRPC(svc *service.PacksServiceImpl, port string) *grpc.Server {
+	listener, err := net.Listen("tcp", ":"+port)
+	if err != nil {
+		log.Fatalf("Failed to listen on gRPC port %s: %v", port, err)
+	}
+
+	server := grpc.NewServer()
+	packsv1.RegisterPacksServiceServer(server, grpcserver.NewServer(svc))
+	reflection.Register(server)
+
+	log.Printf("gRPC server starting on port %s...", port)
+	go func() {
+		if err := server.Serve(listener); err != nil {
+			log.Fatalf("Failed to start gRPC server: %v", err)
+		}
+	}()
+
+	return server
+}
+
+// serviceOptions builds the service options from the environment
+func serviceOptions() []service.Option {
+	var opts []service.Option
+
+	if value := os.Getenv("CALCULATION_TIMEOUT"); value != "" {
+		timeout, err := time.ParseDuration(value)
+		if err != nil {
+			log.Fatalf("Invalid CALCULATION_TIMEOUT %q: %v", value, err)
+		}
+		opts = append(opts, service.WithCalculationTimeout(timeout))
+	}
+
+	if value := os.Getenv("CALCULATION_MAX_STEPS"); value != "" {
+		maxSteps, err := strconv.Atoi(value)
+		if err != nil {
+			log.Fatalf("Invalid CALCULATION_MAX_STEPS %q: %v", value, err)
+		}
+		opts = append(opts, service.WithCalculationMaxSteps(maxSteps))
+	}
+
+	limits := service.DefaultLimits()
+	limits.MaxOrderSize = envInt64("MAX_ORDER_SIZE", limits.MaxOrderSize)
+	limits.MaxPacks = int(envInt64("MAX_PACKS", int64(limits.MaxPacks)))
+	limits.MaxOrderToPackRatio = envInt64("MAX_ORDER_TO_PACK_RATIO", limits.MaxOrderToPackRatio)
+	limits.MaxAnalysisRange = envInt64("MAX_ANALYSIS_RANGE", limits.MaxAnalysisRange)
+	opts = append(opts, service.WithLimits(limits))
+
+	var tolerance model.OvershipmentTolerance
+	tolerance.MaxItems = envInt64("OVERSHIPMENT_MAX_ITEMS", 0)
+	if value := os.Getenv("OVERSHIPMENT_MAX_PERCENT"); value != "" {
+		maxPercent, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			log.Fatalf("Invalid OVERSHIPMENT_MAX_PERCENT %q: %v", value, err)
+		}
+		tolerance.MaxPercent = maxPercent
+	}
+	if value := os.Getenv("OVERSHIPMENT_REJECT"); value != "" {
+		reject, err := strconv.ParseBool(value)
+		if err != nil {
+			log.Fatalf("Invalid OVERSHIPMENT_REJECT %q: %v", value, err)
+		}
+		tolerance.Reject = reject
+	}
+	opts = append(opts, service.WithOvershipmentTolerance(tolerance))
+
+	if path := os.Getenv("CARRIER_RATES_FILE"); path != "" {
+		tables, err := service.ReadRateTables(path)
+		if err != nil {
+			log.Fatalf("Invalid CARRIER_RATES_FILE %q: %v", path, err)
+		}
+		opts = append(opts, service.WithRateTables(tables))
+	}
+
+	return opts
+}
+
+// webhookRetryPolicy builds the retry policy of the webhook deliveries from the environment
+func webhookRetryPolicy() webhook.RetryPolicy {
+	policy := webhook.DefaultRetryPolicy()
+	policy.MaxAttempts = int(envInt64("WEBHOOK_MAX_ATTEMPTS", int64(policy.MaxAttempts)))
+
+	if value := os.Getenv("WEBHOOK_INITIAL_BACKOFF"); value != "" {
+		backoff, err := time.ParseDuration(value)
+		if err != nil {
+			log.Fatalf("Invalid WEBHOOK_INITIAL_BACKOFF %q: %v", value, err)
+		}
+		policy.InitialBackoff = backoff
+	}
+
+	return policy
+}
+
+// webhookAddressPolicy builds the policy of the addresses webhooks may be delivered to from the environment
+func webhookAddressPolicy() netguard.Policy {
+	var policy netguard.Policy
+
+	if value := os.Getenv("WEBHOOK_ALLOWED_NETWORKS"); value != "" {
+		for _, network := range strings.Split(value, ",") {
+			prefix, err := netip.ParsePrefix(strings.TrimSpace(network))
+			if err != nil {
+				log.Fatalf("Invalid WEBHOOK_ALLOWED_NETWORKS %q: %v", value, err)
+			}
+			policy.Allowed = append(policy.Allowed, prefix)
+		}
+	}
+
+	return policy
+}
+
+// envInt64 returns the integer value of the environment variable or the fallback if it is not set
+func envInt64(name string, fallback int64) int64 {
+	value := os.Getenv(name)
+	if value == "" {
+		return fallback
+	}
+
+	result, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		log.Fatalf("Invalid %s %q: %v", name, value, err)
+	}
+
+	return result
+}
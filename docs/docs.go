@@ -32,9 +32,58 @@ const docTemplate = `{
                 }
             }
         },
+        "/api/analysis": {
+            "post": {
+                "description": "Report the overshipment, the largest order that cannot be matched exactly, the pack count distribution and the worst orders of a pack set for a range of order sizes, counting apart the orders the packs cannot hold within their maximum counts",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Analyze pack set coverage",
+                "parameters": [
+                    {
+                        "description": "Pack set and order size range",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.AnalysisRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Analysis result",
+                        "schema": {
+                            "$ref": "#/definitions/model.AnalysisResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Error response",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "408": {
+                        "description": "Analysis timed out or was cancelled",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Analysis exceeded its work budget",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
         "/api/calculate": {
             "post": {
-                "description": "Calculate the optimal number of packs needed for an order",
+                "description": "Calculate the optimal number of packs needed for an order, or for each line of a multi-line order with the packs of its catalog, overshipping or, in the undership mode, backordering the rest, optionally with the shipping and landed cost",
                 "consumes": [
                     "application/json"
                 ],
@@ -44,7 +93,7 @@ const docTemplate = `{
                 "summary": "Calculate packs",
                 "parameters": [
                     {
-                        "description": "Order size",
+                        "description": "Order size or order lines",
                         "name": "request",
                         "in": "body",
                         "required": true,
@@ -63,51 +112,69 @@ const docTemplate = `{
                     "400": {
                         "description": "Error response",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "408": {
+                        "description": "Calculation timed out or was cancelled",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Calculation exceeded its work budget or the overshipment tolerance",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/api/packs": {
+        "/api/catalogs": {
             "get": {
-                "description": "Get a list of all available packs",
+                "description": "Get a list of all product catalogs with their packs, starting with the default catalog of the available packs",
                 "produces": [
                     "application/json"
                 ],
-                "summary": "Get all packs",
+                "summary": "Get all catalogs",
                 "responses": {
                     "200": {
-                        "description": "List of packs",
+                        "description": "List of catalogs",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/model.Pack"
+                                "$ref": "#/definitions/model.Catalog"
                             }
                         }
                     }
                 }
-            },
-            "post": {
-                "description": "Add a new pack with its properties",
+            }
+        },
+        "/api/catalogs/{id}": {
+            "put": {
+                "description": "Add a product catalog or replace the catalog with the same ID",
                 "consumes": [
                     "application/json"
                 ],
                 "produces": [
                     "application/json"
                 ],
-                "summary": "Add pack",
+                "summary": "Save catalog",
                 "parameters": [
                     {
-                        "description": "Pack to add",
+                        "type": "string",
+                        "description": "Catalog ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Catalog to save",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/model.AddPackRequest"
+                            "$ref": "#/definitions/model.Catalog"
                         }
                     }
                 ],
@@ -122,27 +189,22 @@ const docTemplate = `{
                     "400": {
                         "description": "Error response",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/model.ErrorResponse"
                         }
                     }
                 }
-            }
-        },
-        "/api/packs/{size}": {
+            },
             "delete": {
-                "description": "Remove a pack by its size value",
+                "description": "Remove a product catalog by its ID",
                 "produces": [
                     "application/json"
                 ],
-                "summary": "Remove pack",
+                "summary": "Remove catalog",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Pack size to remove",
-                        "name": "size",
+                        "type": "string",
+                        "description": "Catalog ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     }
@@ -158,62 +220,1951 @@ const docTemplate = `{
                     "400": {
                         "description": "Error response",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/model.ErrorResponse"
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "model.AddPackRequest": {
-            "type": "object",
-            "required": [
-                "pack"
-            ],
-            "properties": {
-                "pack": {
-                    "$ref": "#/definitions/model.Pack"
+        },
+        "/api/comparison": {
+            "post": {
+                "description": "Calculate a list of orders with the available packs and a candidate pack set and report the per-order changes and the changes of the shipped items, packs and cost",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Compare pack sets",
+                "parameters": [
+                    {
+                        "description": "Candidate pack set and order sizes",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.ComparisonRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Comparison result",
+                        "schema": {
+                            "$ref": "#/definitions/model.ComparisonResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Error response",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "408": {
+                        "description": "Comparison timed out or was cancelled",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Comparison exceeded its work budget",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
                 }
             }
         },
-        "model.CalculationRequest": {
-            "type": "object",
-            "properties": {
-                "orderSize": {
-                    "type": "integer"
+        "/api/history": {
+            "get": {
+                "description": "Get the most recent calculations, oldest first",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Get calculation history",
+                "responses": {
+                    "200": {
+                        "description": "Calculation history",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.CalculationRecord"
+                            }
+                        }
+                    }
                 }
             }
         },
-        "model.CalculationResponse": {
-            "type": "object",
-            "properties": {
-                "orderSize": {
-                    "description": "OrderSize is the original size of the order",
-                    "type": "integer"
-                },
-                "packs": {
-                    "description": "Packs represents the calculated packs needed for the order",
-                    "type": "object",
-                    "additionalProperties": {
-                        "type": "integer"
+        "/api/limits": {
+            "get": {
+                "description": "Get the bounds order sizes and pack sets must stay within",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Get limits",
+                "responses": {
+                    "200": {
+                        "description": "Limits",
+                        "schema": {
+                            "$ref": "#/definitions/model.Limits"
+                        }
                     }
                 }
             }
         },
-        "model.Pack": {
-            "type": "object",
-            "required": [
-                "size"
-            ],
-            "properties": {
-                "size": {
-                    "type": "integer"
+        "/api/packaging": {
+            "get": {
+                "description": "Get the levels of outer containers packs are shipped in, innermost first",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Get packaging hierarchy",
+                "responses": {
+                    "200": {
+                        "description": "Packaging hierarchy",
+                        "schema": {
+                            "$ref": "#/definitions/model.Packaging"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Replace the levels of outer containers packs are shipped in, e.g. cartons holding items and pallets holding cartons",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Save packaging hierarchy",
+                "parameters": [
+                    {
+                        "description": "Packaging hierarchy to save",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.Packaging"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success response",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Error response",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
                 }
             }
+        },
+        "/api/packs": {
+            "get": {
+                "description": "Get a list of all available packs",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Get all packs",
+                "responses": {
+                    "200": {
+                        "description": "List of packs",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.Pack"
+                            }
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Replace all available packs at once",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Replace packs",
+                "parameters": [
+                    {
+                        "description": "Packs to replace the available packs with",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.ReplacePacksRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success response",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Error response",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Add a new pack with its properties",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Add pack",
+                "parameters": [
+                    {
+                        "description": "Pack to add",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.AddPackRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success response",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Error response",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/packs/changes": {
+            "get": {
+                "description": "Get the changes of the available packs after a version, oldest first. The version of the packs after a change is the position of the change in the log counted from 1",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Get pack changes",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Version after which the changes are returned, all changes if it is not set",
+                        "name": "since",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Pack changes",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.PackChange"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Error response",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/packs/events": {
+            "get": {
+                "description": "Stream the changes of the available packs as server-sent events named \"packs\", each carrying the kind of the change and all available packs after it, until the client disconnects",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "summary": "Stream pack changes",
+                "responses": {
+                    "200": {
+                        "description": "Stream of pack change events",
+                        "schema": {
+                            "$ref": "#/definitions/model.PacksEvent"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/packs/versions/{version}": {
+            "get": {
+                "description": "Get the available packs as they were at a version, rebuilt from their changes",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Get packs of a version",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Version of the packs",
+                        "name": "version",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of packs",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.Pack"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Error response",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/packs/{size}": {
+            "delete": {
+                "description": "Remove a pack by its size value",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Remove pack",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Pack size to remove",
+                        "name": "size",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success response",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Error response",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/rates": {
+            "get": {
+                "description": "Get the carrier rate tables the shipping cost of orders is priced with, loaded from the rates file",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Get carrier rate tables",
+                "responses": {
+                    "200": {
+                        "description": "Carrier rate tables",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.RateTable"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/recommendation": {
+            "post": {
+                "description": "Propose pack sizes that ship the given or the historical orders with the least overshipment and packs, compared with the available packs",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Recommend pack sizes",
+                "parameters": [
+                    {
+                        "description": "Order sizes and number of pack sizes",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.RecommendationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Recommendation",
+                        "schema": {
+                            "$ref": "#/definitions/model.RecommendationResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Error response",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "408": {
+                        "description": "Recommendation timed out or was cancelled",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Recommendation exceeded its work budget",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/warehouses": {
+            "get": {
+                "description": "Get a list of all warehouses with their pack stock",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Get all warehouses",
+                "responses": {
+                    "200": {
+                        "description": "List of warehouses",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.Warehouse"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/warehouses/{id}": {
+            "put": {
+                "description": "Add a warehouse or replace the warehouse with the same ID, together with its pack stock",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Save warehouse",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Warehouse ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Warehouse to save",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.Warehouse"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success response",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Error response",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Remove a warehouse by its ID",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Remove warehouse",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Warehouse ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success response",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Error response",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/webhooks": {
+            "get": {
+                "description": "Get a list of all webhooks with the events they are subscribed to, without their secrets",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Get all webhooks",
+                "responses": {
+                    "200": {
+                        "description": "List of webhooks",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.Webhook"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/webhooks/dead-letters": {
+            "get": {
+                "description": "Get the most recent events that could not be delivered to their webhooks within the retry attempts, oldest first",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Get undelivered webhook events",
+                "responses": {
+                    "200": {
+                        "description": "List of undelivered events",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.DeadLetter"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/webhooks/{id}": {
+            "put": {
+                "description": "Add a webhook or replace the webhook with the same ID. The events it is subscribed to are posted to its URL as JSON, signed with its secret in the X-Webhook-Signature header",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Save webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Webhook ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Webhook to save",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.Webhook"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success response",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Error response",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Remove a webhook by its ID",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Remove webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Webhook ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success response",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Error response",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "model.AddPackRequest": {
+            "type": "object",
+            "required": [
+                "pack"
+            ],
+            "properties": {
+                "pack": {
+                    "$ref": "#/definitions/model.Pack"
+                }
+            }
+        },
+        "model.AnalysisRequest": {
+            "type": "object",
+            "properties": {
+                "maxOrderSize": {
+                    "description": "MaxOrderSize is the largest order size of the range",
+                    "type": "integer"
+                },
+                "minOrderSize": {
+                    "description": "MinOrderSize is the smallest order size of the range",
+                    "type": "integer"
+                },
+                "packs": {
+                    "description": "Packs is the pack set to analyze, the available packs are analyzed if it is empty",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.Pack"
+                    }
+                },
+                "worstOrdersLimit": {
+                    "description": "WorstOrdersLimit is the number of orders with the worst overshipment to report, 10 if not set",
+                    "type": "integer"
+                }
+            }
+        },
+        "model.AnalysisResponse": {
+            "type": "object",
+            "properties": {
+                "averageOvershipment": {
+                    "description": "AverageOvershipment is the average number of items shipped above the order size",
+                    "type": "number"
+                },
+                "averageOvershipmentPercent": {
+                    "description": "AverageOvershipmentPercent is the average overshipment in percent of the order size",
+                    "type": "number"
+                },
+                "exactOrders": {
+                    "description": "ExactOrders is the number of orders of the range that are matched exactly",
+                    "type": "integer"
+                },
+                "greatestCommonDivisor": {
+                    "description": "GreatestCommonDivisor is the greatest common divisor of the pack sizes",
+                    "type": "integer"
+                },
+                "infeasibleOrders": {
+                    "description": "InfeasibleOrders is the number of orders of the range that are larger than the packs can hold within\ntheir maximum counts, they are left out of the other figures",
+                    "type": "integer"
+                },
+                "largestUnmatchableOrder": {
+                    "description": "LargestUnmatchableOrder is the largest order size that cannot be matched exactly (the Frobenius number),\nzero if every order size can be matched and null if infinitely many cannot as the pack sizes share a divisor\nor if it is not known as the counts of packs are limited",
+                    "type": "integer"
+                },
+                "maxOrderSize": {
+                    "description": "MaxOrderSize is the largest order size of the range",
+                    "type": "integer"
+                },
+                "maxOvershipment": {
+                    "description": "MaxOvershipment is the largest number of items shipped above the order size",
+                    "type": "integer"
+                },
+                "maxOvershipmentPercent": {
+                    "description": "MaxOvershipmentPercent is the largest overshipment in percent of the order size",
+                    "type": "number"
+                },
+                "minOrderSize": {
+                    "description": "MinOrderSize is the smallest order size of the range",
+                    "type": "integer"
+                },
+                "packCountDistribution": {
+                    "description": "PackCountDistribution maps a number of packs to the number of orders of the range shipped in that many packs",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "packs": {
+                    "description": "Packs is the analyzed pack set",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.Pack"
+                    }
+                },
+                "smallestInfeasibleOrder": {
+                    "description": "SmallestInfeasibleOrder is the smallest order size of the range the packs cannot hold, null if there is none",
+                    "type": "integer"
+                },
+                "worstOrders": {
+                    "description": "WorstOrders are the orders of the range with the largest overshipment",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.OrderOvershipment"
+                    }
+                }
+            }
+        },
+        "model.CalculationRecord": {
+            "type": "object",
+            "properties": {
+                "allocations": {
+                    "description": "Allocations are the packs each warehouse ships, if requested",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.WarehouseAllocation"
+                    }
+                },
+                "backorder": {
+                    "description": "Backorder is the number of items of a single-line order that are not shipped in the undership mode",
+                    "type": "integer"
+                },
+                "calculatedAt": {
+                    "description": "CalculatedAt is the time the calculation was made",
+                    "type": "string"
+                },
+                "lines": {
+                    "description": "Lines are the packing plans of the lines of a multi-line order",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.LineResult"
+                    }
+                },
+                "orderSize": {
+                    "description": "OrderSize is the original size of the order, the total quantity of a multi-line order",
+                    "type": "integer"
+                },
+                "packaging": {
+                    "description": "Packaging is the plan of the outer containers the packs are shipped in, if requested",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.PackagingPlan"
+                        }
+                    ]
+                },
+                "packs": {
+                    "description": "Packs represents the calculated packs needed for the order",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "shipping": {
+                    "description": "Shipping is the shipping cost and the landed cost of the order, if requested",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.ShippingQuote"
+                        }
+                    ]
+                },
+                "split": {
+                    "description": "Split is the plan of the shipments the order is split into, if requested",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.SplitPlan"
+                        }
+                    ]
+                },
+                "toleranceExceeded": {
+                    "description": "ToleranceExceeded reports whether the overshipment of the order or of any of its lines exceeds the tolerance",
+                    "type": "boolean"
+                },
+                "totals": {
+                    "description": "Totals sums up the packing plans of the lines of a multi-line order",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.ShipmentTotals"
+                        }
+                    ]
+                }
+            }
+        },
+        "model.CalculationRequest": {
+            "type": "object",
+            "properties": {
+                "lines": {
+                    "description": "Lines are the lines of a multi-line order",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.OrderLine"
+                    }
+                },
+                "mode": {
+                    "description": "Mode chooses whether an order may be overshipped or undershipped, ShipmentModeOvership if it is empty",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.ShipmentMode"
+                        }
+                    ]
+                },
+                "orderSize": {
+                    "description": "OrderSize is the size of a single-line order of the available packs, used if there are no lines",
+                    "type": "integer"
+                },
+                "packaging": {
+                    "description": "Packaging requests a plan of the outer containers the packs are shipped in",
+                    "type": "boolean"
+                },
+                "shippingCost": {
+                    "description": "ShippingCost requests the shipping cost of a single-line order with the cheapest carrier,\nchoosing the combination of packs with the lowest landed cost",
+                    "type": "boolean"
+                },
+                "split": {
+                    "description": "Split requests to split a single-line order into shipments within the caps",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.ShipmentSplit"
+                        }
+                    ]
+                },
+                "warehouses": {
+                    "description": "Warehouses requests to fulfil a single-line order from the pack stock of the warehouses",
+                    "type": "boolean"
+                }
+            }
+        },
+        "model.CalculationResponse": {
+            "type": "object",
+            "properties": {
+                "allocations": {
+                    "description": "Allocations are the packs each warehouse ships, if requested",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.WarehouseAllocation"
+                    }
+                },
+                "backorder": {
+                    "description": "Backorder is the number of items of a single-line order that are not shipped in the undership mode",
+                    "type": "integer"
+                },
+                "lines": {
+                    "description": "Lines are the packing plans of the lines of a multi-line order",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.LineResult"
+                    }
+                },
+                "orderSize": {
+                    "description": "OrderSize is the original size of the order, the total quantity of a multi-line order",
+                    "type": "integer"
+                },
+                "packaging": {
+                    "description": "Packaging is the plan of the outer containers the packs are shipped in, if requested",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.PackagingPlan"
+                        }
+                    ]
+                },
+                "packs": {
+                    "description": "Packs represents the calculated packs needed for the order",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "shipping": {
+                    "description": "Shipping is the shipping cost and the landed cost of the order, if requested",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.ShippingQuote"
+                        }
+                    ]
+                },
+                "split": {
+                    "description": "Split is the plan of the shipments the order is split into, if requested",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.SplitPlan"
+                        }
+                    ]
+                },
+                "toleranceExceeded": {
+                    "description": "ToleranceExceeded reports whether the overshipment of the order or of any of its lines exceeds the tolerance",
+                    "type": "boolean"
+                },
+                "totals": {
+                    "description": "Totals sums up the packing plans of the lines of a multi-line order",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.ShipmentTotals"
+                        }
+                    ]
+                }
+            }
+        },
+        "model.Catalog": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "description": "ID identifies the catalog in order lines",
+                    "type": "string"
+                },
+                "name": {
+                    "description": "Name is a human-readable name of the product",
+                    "type": "string"
+                },
+                "packs": {
+                    "description": "Packs are the packs the product is shipped in",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.Pack"
+                    }
+                },
+                "tolerance": {
+                    "description": "Tolerance bounds the overshipment of the product, the global tolerance is used if it is not set",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.OvershipmentTolerance"
+                        }
+                    ]
+                },
+                "unit": {
+                    "description": "Unit is the unit of measure of the packs and the order lines of the product, UnitItem if it is empty",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.Unit"
+                        }
+                    ]
+                }
+            }
+        },
+        "model.ComparisonRequest": {
+            "type": "object",
+            "required": [
+                "orderSizes",
+                "packs"
+            ],
+            "properties": {
+                "orderSizes": {
+                    "description": "OrderSizes are the order sizes to compare the pack sets for",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "packs": {
+                    "description": "Packs is the candidate pack set",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.Pack"
+                    }
+                }
+            }
+        },
+        "model.ComparisonResponse": {
+            "type": "object",
+            "properties": {
+                "candidate": {
+                    "description": "Candidate sums up the shipments with the candidate pack set",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.ShipmentTotals"
+                        }
+                    ]
+                },
+                "candidatePacks": {
+                    "description": "CandidatePacks is the candidate pack set",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.Pack"
+                    }
+                },
+                "costDelta": {
+                    "description": "CostDelta is the change of the cost of the shipped packs with the candidate pack set",
+                    "type": "integer"
+                },
+                "current": {
+                    "description": "Current sums up the shipments with the available packs",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.ShipmentTotals"
+                        }
+                    ]
+                },
+                "currentPacks": {
+                    "description": "CurrentPacks are the available packs",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.Pack"
+                    }
+                },
+                "orders": {
+                    "description": "Orders compares the shipments of each order",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.OrderComparison"
+                    }
+                },
+                "packCountDelta": {
+                    "description": "PackCountDelta is the change of the shipped packs with the candidate pack set",
+                    "type": "integer"
+                },
+                "shippedItemsDelta": {
+                    "description": "ShippedItemsDelta is the change of the shipped items with the candidate pack set",
+                    "type": "integer"
+                }
+            }
+        },
+        "model.ContainerGroup": {
+            "type": "object",
+            "properties": {
+                "contents": {
+                    "description": "Contents is the number of inner containers in each container of an outer level",
+                    "type": "integer"
+                },
+                "count": {
+                    "description": "Count is the number of containers in the group",
+                    "type": "integer"
+                },
+                "packs": {
+                    "description": "Packs maps a pack size to the count of packs in each container of the first level",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "model.ContainerLevel": {
+            "type": "object",
+            "properties": {
+                "capacity": {
+                    "description": "Capacity is the number of items a container of the first level holds,\nor the number of inner containers a container of an outer level holds",
+                    "type": "integer"
+                },
+                "name": {
+                    "description": "Name is the name of the containers of the level",
+                    "type": "string"
+                }
+            }
+        },
+        "model.ContainerPlan": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "description": "Count is the number of containers of the level",
+                    "type": "integer"
+                },
+                "groups": {
+                    "description": "Groups are the groups of identically filled containers of the level",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.ContainerGroup"
+                    }
+                },
+                "name": {
+                    "description": "Name is the name of the containers of the level",
+                    "type": "string"
+                }
+            }
+        },
+        "model.DeadLetter": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "description": "Attempts is the number of deliveries attempted",
+                    "type": "integer"
+                },
+                "error": {
+                    "description": "Error describes why the last attempt failed",
+                    "type": "string"
+                },
+                "event": {
+                    "description": "Event is the undelivered event",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.WebhookEvent"
+                        }
+                    ]
+                },
+                "failedAt": {
+                    "description": "FailedAt is the time the last attempt failed",
+                    "type": "string"
+                },
+                "url": {
+                    "description": "URL is the URL the event was posted to",
+                    "type": "string"
+                },
+                "webhook": {
+                    "description": "Webhook is the ID of the webhook",
+                    "type": "string"
+                }
+            }
+        },
+        "model.ErrorCode": {
+            "type": "string",
+            "enum": [
+                "INVALID_REQUEST",
+                "INVALID_PACK_SIZE",
+                "INVALID_PACK_COUNT_LIMITS",
+                "INVALID_PACK_WEIGHT",
+                "PACK_EXISTS",
+                "PACK_NOT_FOUND",
+                "PACKS_VERSION_NOT_FOUND",
+                "INVALID_CATALOG",
+                "CATALOG_NOT_FOUND",
+                "INVALID_UNIT",
+                "NO_PACKS",
+                "TOO_MANY_PACKS",
+                "INVALID_ORDER_SIZE",
+                "ORDER_SIZE_TOO_LARGE",
+                "ORDER_TO_PACK_RATIO_TOO_LARGE",
+                "INVALID_ORDER_RANGE",
+                "ORDER_RANGE_TOO_LARGE",
+                "NO_ORDERS",
+                "INVALID_PACK_COUNT",
+                "NO_FEASIBLE_PACKS",
+                "INVALID_SHIPMENT_MODE",
+                "INVALID_TOLERANCE",
+                "TOLERANCE_EXCEEDED",
+                "INVALID_PACKAGING",
+                "PACK_DOES_NOT_FIT_CONTAINER",
+                "INVALID_SPLIT",
+                "PACK_DOES_NOT_FIT_SHIPMENT",
+                "INVALID_WAREHOUSE",
+                "WAREHOUSE_NOT_FOUND",
+                "INSUFFICIENT_STOCK",
+                "INVALID_RATE_TABLE",
+                "NO_RATE_TABLES",
+                "NO_RATE_BAND",
+                "INVALID_WEBHOOK",
+                "WEBHOOK_NOT_FOUND",
+                "ARITHMETIC_OVERFLOW",
+                "CALCULATION_TIMEOUT",
+                "CALCULATION_CANCELLED",
+                "CALCULATION_BUDGET_EXCEEDED"
+            ],
+            "x-enum-varnames": [
+                "ErrorCodeInvalidRequest",
+                "ErrorCodeInvalidPackSize",
+                "ErrorCodeInvalidPackCountLimits",
+                "ErrorCodeInvalidPackWeight",
+                "ErrorCodePackExists",
+                "ErrorCodePackNotFound",
+                "ErrorCodePacksVersionNotFound",
+                "ErrorCodeInvalidCatalog",
+                "ErrorCodeCatalogNotFound",
+                "ErrorCodeInvalidUnit",
+                "ErrorCodeNoPacks",
+                "ErrorCodeTooManyPacks",
+                "ErrorCodeInvalidOrderSize",
+                "ErrorCodeOrderSizeTooLarge",
+                "ErrorCodeOrderToPackRatioTooLarge",
+                "ErrorCodeInvalidOrderRange",
+                "ErrorCodeOrderRangeTooLarge",
+                "ErrorCodeNoOrders",
+                "ErrorCodeInvalidPackCount",
+                "ErrorCodeNoFeasiblePacks",
+                "ErrorCodeInvalidShipmentMode",
+                "ErrorCodeInvalidTolerance",
+                "ErrorCodeToleranceExceeded",
+                "ErrorCodeInvalidPackaging",
+                "ErrorCodePackDoesNotFitContainer",
+                "ErrorCodeInvalidSplit",
+                "ErrorCodePackDoesNotFitShipment",
+                "ErrorCodeInvalidWarehouse",
+                "ErrorCodeWarehouseNotFound",
+                "ErrorCodeInsufficientStock",
+                "ErrorCodeInvalidRateTable",
+                "ErrorCodeNoRateTables",
+                "ErrorCodeNoRateBand",
+                "ErrorCodeInvalidWebhook",
+                "ErrorCodeWebhookNotFound",
+                "ErrorCodeArithmeticOverflow",
+                "ErrorCodeCalculationTimeout",
+                "ErrorCodeCalculationCancelled",
+                "ErrorCodeCalculationBudgetExceeded"
+            ]
+        },
+        "model.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "Code is a machine-readable identifier of the error",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.ErrorCode"
+                        }
+                    ]
+                },
+                "error": {
+                    "description": "Error is a human-readable description of the error",
+                    "type": "string"
+                }
+            }
+        },
+        "model.Limits": {
+            "type": "object",
+            "properties": {
+                "maxAnalysisRange": {
+                    "description": "MaxAnalysisRange is the largest number of order sizes analyzed at once, zero means no limit",
+                    "type": "integer"
+                },
+                "maxOrderSize": {
+                    "description": "MaxOrderSize is the largest accepted order size, zero means no limit",
+                    "type": "integer"
+                },
+                "maxOrderToPackRatio": {
+                    "description": "MaxOrderToPackRatio is the largest accepted ratio of the order size to the smallest pack size, zero means no limit",
+                    "type": "integer"
+                },
+                "maxPacks": {
+                    "description": "MaxPacks is the largest number of pack sizes that can be configured, zero means no limit",
+                    "type": "integer"
+                }
+            }
+        },
+        "model.LineResult": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "description": "Amount is the ordered amount of a product measured in kilograms or litres",
+                    "type": "number"
+                },
+                "backorder": {
+                    "description": "Backorder is the number of items shipped below the order size",
+                    "type": "integer"
+                },
+                "catalog": {
+                    "description": "Catalog is the ID of the catalog of the ordered product",
+                    "type": "string"
+                },
+                "contents": {
+                    "description": "Contents maps the content of a pack of a product measured in kilograms or litres to the count of packs shipped",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "cost": {
+                    "description": "Cost is the total cost of the packs in minor currency units",
+                    "type": "integer"
+                },
+                "overshipment": {
+                    "description": "Overshipment is the number of items shipped above the order size",
+                    "type": "integer"
+                },
+                "packCount": {
+                    "description": "PackCount is the total number of packs",
+                    "type": "integer"
+                },
+                "packs": {
+                    "description": "Packs maps a pack size to the count of packs shipped",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "quantity": {
+                    "description": "Quantity is the number of ordered items",
+                    "type": "integer"
+                },
+                "shippedAmount": {
+                    "description": "ShippedAmount is the total amount in the packs of a product measured in kilograms or litres",
+                    "type": "number"
+                },
+                "shippedItems": {
+                    "description": "ShippedItems is the total number of items in the packs",
+                    "type": "integer"
+                },
+                "toleranceExceeded": {
+                    "description": "ToleranceExceeded reports whether the overshipment of the line exceeds the tolerance of its catalog",
+                    "type": "boolean"
+                },
+                "unit": {
+                    "description": "Unit is the unit of measure of the catalog, UnitItem if it is empty",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.Unit"
+                        }
+                    ]
+                }
+            }
+        },
+        "model.OrderComparison": {
+            "type": "object",
+            "properties": {
+                "candidate": {
+                    "description": "Candidate is the shipment with the candidate pack set",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.Shipment"
+                        }
+                    ]
+                },
+                "changed": {
+                    "description": "Changed reports whether the shipments differ",
+                    "type": "boolean"
+                },
+                "current": {
+                    "description": "Current is the shipment with the available packs",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.Shipment"
+                        }
+                    ]
+                },
+                "orderSize": {
+                    "description": "OrderSize is the size of the order",
+                    "type": "integer"
+                }
+            }
+        },
+        "model.OrderLine": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "description": "Amount is the ordered amount of a product measured in kilograms or litres, e.g. 12.5",
+                    "type": "number"
+                },
+                "catalog": {
+                    "description": "Catalog is the ID of the catalog of the ordered product, the available packs are used if it is empty",
+                    "type": "string"
+                },
+                "quantity": {
+                    "description": "Quantity is the number of ordered items of a product counted in items",
+                    "type": "integer"
+                }
+            }
+        },
+        "model.OrderOvershipment": {
+            "type": "object",
+            "properties": {
+                "orderSize": {
+                    "description": "OrderSize is the size of the order",
+                    "type": "integer"
+                },
+                "overshipment": {
+                    "description": "Overshipment is the number of items shipped above the order size",
+                    "type": "integer"
+                },
+                "packs": {
+                    "description": "Packs maps a pack size to the count of packs shipped",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "shippedItems": {
+                    "description": "ShippedItems is the total number of items in the packs",
+                    "type": "integer"
+                }
+            }
+        },
+        "model.OvershipmentTolerance": {
+            "type": "object",
+            "properties": {
+                "maxItems": {
+                    "description": "MaxItems is the largest number of items shipped above the order size, zero means no limit.\nThe items of a product measured in kilograms or litres are thousandths of the unit",
+                    "type": "integer"
+                },
+                "maxPercent": {
+                    "description": "MaxPercent is the largest overshipment in percent of the order size, zero means no limit",
+                    "type": "number"
+                },
+                "reject": {
+                    "description": "Reject rejects a calculation that exceeds the tolerance instead of flagging it",
+                    "type": "boolean"
+                }
+            }
+        },
+        "model.Pack": {
+            "type": "object",
+            "required": [
+                "size"
+            ],
+            "properties": {
+                "content": {
+                    "description": "Content is the amount of a product measured in kilograms or litres in a single pack, e.g. 0.5,\nthe size of the pack is the content in thousandths of the unit",
+                    "type": "number"
+                },
+                "cost": {
+                    "description": "Cost is the cost of a single pack in minor currency units, e.g. cents",
+                    "type": "integer"
+                },
+                "maxCount": {
+                    "description": "MaxCount is the largest number of packs of the size a combination can hold, zero means no limit",
+                    "type": "integer"
+                },
+                "minCount": {
+                    "description": "MinCount is the number of packs of the size every combination must hold",
+                    "type": "integer"
+                },
+                "size": {
+                    "type": "integer"
+                },
+                "volume": {
+                    "description": "Volume is the volume of a single pack, e.g. in cubic centimetres",
+                    "type": "integer"
+                },
+                "weight": {
+                    "description": "Weight is the weight of a single pack, e.g. in grams",
+                    "type": "integer"
+                }
+            }
+        },
+        "model.PackChange": {
+            "type": "object",
+            "properties": {
+                "changedAt": {
+                    "description": "ChangedAt is the time of the change",
+                    "type": "string"
+                },
+                "pack": {
+                    "description": "Pack is the added pack",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.Pack"
+                        }
+                    ]
+                },
+                "packs": {
+                    "description": "Packs are the packs that replaced all packs",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.Pack"
+                    }
+                },
+                "size": {
+                    "description": "Size is the size of the removed pack",
+                    "type": "integer"
+                },
+                "type": {
+                    "description": "Type is the kind of the change",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.PacksEventType"
+                        }
+                    ]
+                },
+                "version": {
+                    "description": "Version is the version of the packs after the change, the position of the change in the log counted from 1",
+                    "type": "integer"
+                }
+            }
+        },
+        "model.PackSetScore": {
+            "type": "object",
+            "properties": {
+                "averageOvershipment": {
+                    "description": "AverageOvershipment is the average number of items shipped above an order size",
+                    "type": "number"
+                },
+                "averagePacks": {
+                    "description": "AveragePacks is the average number of packs shipped for an order",
+                    "type": "number"
+                },
+                "packs": {
+                    "description": "Packs is the scored pack set",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.Pack"
+                    }
+                },
+                "totalOvershipment": {
+                    "description": "TotalOvershipment is the number of items shipped above the order sizes",
+                    "type": "integer"
+                },
+                "totalPacks": {
+                    "description": "TotalPacks is the number of packs shipped",
+                    "type": "integer"
+                }
+            }
+        },
+        "model.Packaging": {
+            "type": "object",
+            "properties": {
+                "levels": {
+                    "description": "Levels are the levels of outer containers, innermost first",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.ContainerLevel"
+                    }
+                }
+            }
+        },
+        "model.PackagingPlan": {
+            "type": "object",
+            "properties": {
+                "levels": {
+                    "description": "Levels are the containers of each level, innermost first",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.ContainerPlan"
+                    }
+                }
+            }
+        },
+        "model.PacksEvent": {
+            "type": "object",
+            "properties": {
+                "packs": {
+                    "description": "Packs are the available packs after the change",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.Pack"
+                    }
+                },
+                "type": {
+                    "description": "Type is the kind of the change",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.PacksEventType"
+                        }
+                    ]
+                }
+            }
+        },
+        "model.PacksEventType": {
+            "type": "string",
+            "enum": [
+                "added",
+                "removed",
+                "replaced"
+            ],
+            "x-enum-varnames": [
+                "PacksEventAdded",
+                "PacksEventRemoved",
+                "PacksEventReplaced"
+            ]
+        },
+        "model.RateBand": {
+            "type": "object",
+            "properties": {
+                "maxVolume": {
+                    "description": "MaxVolume is the largest total volume of the packs in the band, zero means no limit",
+                    "type": "integer"
+                },
+                "maxWeight": {
+                    "description": "MaxWeight is the largest total weight of the packs in the band, zero means no limit",
+                    "type": "integer"
+                },
+                "price": {
+                    "description": "Price is the price of shipping in minor currency units",
+                    "type": "integer"
+                }
+            }
+        },
+        "model.RateTable": {
+            "type": "object",
+            "properties": {
+                "bands": {
+                    "description": "Bands are the bands of the table, the cheapest band a shipment fits into prices it",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.RateBand"
+                    }
+                },
+                "carrier": {
+                    "description": "Carrier is the name of the carrier",
+                    "type": "string"
+                }
+            }
+        },
+        "model.RecommendationRequest": {
+            "type": "object",
+            "properties": {
+                "orderSizes": {
+                    "description": "OrderSizes are the historical order sizes, the calculation history is used if it is empty",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "packCount": {
+                    "description": "PackCount is the number of pack sizes to recommend",
+                    "type": "integer"
+                }
+            }
+        },
+        "model.RecommendationResponse": {
+            "type": "object",
+            "properties": {
+                "current": {
+                    "description": "Current is the score of the available packs for the orders",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.PackSetScore"
+                        }
+                    ]
+                },
+                "orderCount": {
+                    "description": "OrderCount is the number of orders the recommendation is based on",
+                    "type": "integer"
+                },
+                "overshipmentReduction": {
+                    "description": "OvershipmentReduction is the number of items the recommended packs ship less than the available packs",
+                    "type": "integer"
+                },
+                "packCountReduction": {
+                    "description": "PackCountReduction is the number of packs the recommended packs ship less than the available packs",
+                    "type": "integer"
+                },
+                "packs": {
+                    "description": "Packs are the recommended packs",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.Pack"
+                    }
+                },
+                "recommended": {
+                    "description": "Recommended is the score of the recommended packs for the orders",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.PackSetScore"
+                        }
+                    ]
+                }
+            }
+        },
+        "model.ReplacePacksRequest": {
+            "type": "object",
+            "required": [
+                "packs"
+            ],
+            "properties": {
+                "packs": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.Pack"
+                    }
+                }
+            }
+        },
+        "model.Shipment": {
+            "type": "object",
+            "properties": {
+                "backorder": {
+                    "description": "Backorder is the number of items shipped below the order size",
+                    "type": "integer"
+                },
+                "cost": {
+                    "description": "Cost is the total cost of the packs in minor currency units",
+                    "type": "integer"
+                },
+                "overshipment": {
+                    "description": "Overshipment is the number of items shipped above the order size",
+                    "type": "integer"
+                },
+                "packCount": {
+                    "description": "PackCount is the total number of packs",
+                    "type": "integer"
+                },
+                "packs": {
+                    "description": "Packs maps a pack size to the count of packs shipped",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "shippedItems": {
+                    "description": "ShippedItems is the total number of items in the packs",
+                    "type": "integer"
+                }
+            }
+        },
+        "model.ShipmentGroup": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "description": "Count is the number of shipments in the group",
+                    "type": "integer"
+                },
+                "items": {
+                    "description": "Items is the number of items in each shipment",
+                    "type": "integer"
+                },
+                "packs": {
+                    "description": "Packs maps a pack size to the count of packs in each shipment",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "weight": {
+                    "description": "Weight is the total weight of the packs in each shipment",
+                    "type": "integer"
+                }
+            }
+        },
+        "model.ShipmentMode": {
+            "type": "string",
+            "enum": [
+                "overship",
+                "undership"
+            ],
+            "x-enum-varnames": [
+                "Overship",
+                "Undership"
+            ]
+        },
+        "model.ShipmentSplit": {
+            "type": "object",
+            "properties": {
+                "maxItems": {
+                    "description": "MaxItems is the largest number of items in a shipment, zero means no limit",
+                    "type": "integer"
+                },
+                "maxWeight": {
+                    "description": "MaxWeight is the largest total weight of the packs in a shipment, zero means no limit",
+                    "type": "integer"
+                }
+            }
+        },
+        "model.ShipmentTotals": {
+            "type": "object",
+            "properties": {
+                "backorder": {
+                    "description": "Backorder is the total number of items shipped below the order sizes",
+                    "type": "integer"
+                },
+                "cost": {
+                    "description": "Cost is the total cost of the packs shipped in minor currency units",
+                    "type": "integer"
+                },
+                "overshipment": {
+                    "description": "Overshipment is the total number of items shipped above the order sizes",
+                    "type": "integer"
+                },
+                "packCount": {
+                    "description": "PackCount is the total number of packs shipped",
+                    "type": "integer"
+                },
+                "shippedItems": {
+                    "description": "ShippedItems is the total number of items shipped",
+                    "type": "integer"
+                }
+            }
+        },
+        "model.ShippingQuote": {
+            "type": "object",
+            "properties": {
+                "carrier": {
+                    "description": "Carrier is the carrier with the cheapest rate for the packs",
+                    "type": "string"
+                },
+                "landedCost": {
+                    "description": "LandedCost is the sum of the pack cost and the shipping cost",
+                    "type": "integer"
+                },
+                "packCost": {
+                    "description": "PackCost is the total cost of the packs in minor currency units",
+                    "type": "integer"
+                },
+                "shippingCost": {
+                    "description": "ShippingCost is the price of the carrier for shipping the packs in minor currency units",
+                    "type": "integer"
+                },
+                "volume": {
+                    "description": "Volume is the total volume of the packs",
+                    "type": "integer"
+                },
+                "weight": {
+                    "description": "Weight is the total weight of the packs",
+                    "type": "integer"
+                }
+            }
+        },
+        "model.SplitPlan": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "description": "Count is the number of shipments",
+                    "type": "integer"
+                },
+                "groups": {
+                    "description": "Groups are the groups of identical shipments",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.ShipmentGroup"
+                    }
+                }
+            }
+        },
+        "model.StockItem": {
+            "type": "object",
+            "properties": {
+                "quantity": {
+                    "description": "Quantity is the number of packs in stock",
+                    "type": "integer"
+                },
+                "size": {
+                    "description": "Size is the size of the packs",
+                    "type": "integer"
+                }
+            }
+        },
+        "model.Unit": {
+            "type": "string",
+            "enum": [
+                "item",
+                "kg",
+                "l"
+            ],
+            "x-enum-varnames": [
+                "UnitItem",
+                "UnitKilogram",
+                "UnitLitre"
+            ]
+        },
+        "model.Warehouse": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "description": "ID identifies the warehouse in allocations",
+                    "type": "string"
+                },
+                "name": {
+                    "description": "Name is a human-readable name of the warehouse",
+                    "type": "string"
+                },
+                "stock": {
+                    "description": "Stock lists the packs the warehouse has in stock",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.StockItem"
+                    }
+                }
+            }
+        },
+        "model.WarehouseAllocation": {
+            "type": "object",
+            "properties": {
+                "packs": {
+                    "description": "Packs maps a pack size to the count of packs the warehouse ships",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "warehouse": {
+                    "description": "Warehouse is the ID of the warehouse",
+                    "type": "string"
+                }
+            }
+        },
+        "model.Webhook": {
+            "type": "object",
+            "properties": {
+                "events": {
+                    "description": "Events are the kinds of events the webhook is notified of",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.WebhookEventType"
+                    }
+                },
+                "id": {
+                    "description": "ID identifies the webhook",
+                    "type": "string"
+                },
+                "secret": {
+                    "description": "Secret signs the payloads posted to the webhook, it is never returned by the API",
+                    "type": "string"
+                },
+                "url": {
+                    "description": "URL is the absolute http or https URL the events are posted to",
+                    "type": "string"
+                }
+            }
+        },
+        "model.WebhookEvent": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "description": "CreatedAt is the time the event occurred",
+                    "type": "string"
+                },
+                "data": {
+                    "description": "Data is a PacksEvent for the changes of the packs and a CalculationRecord for the calculations"
+                },
+                "id": {
+                    "description": "ID identifies the event, it is the same in every delivery and every retry of the event",
+                    "type": "string"
+                },
+                "type": {
+                    "description": "Type is the kind of the event",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.WebhookEventType"
+                        }
+                    ]
+                }
+            }
+        },
+        "model.WebhookEventType": {
+            "type": "string",
+            "enum": [
+                "packs.added",
+                "packs.removed",
+                "packs.replaced",
+                "calculation.completed"
+            ],
+            "x-enum-varnames": [
+                "WebhookEventPacksAdded",
+                "WebhookEventPacksRemoved",
+                "WebhookEventPacksReplaced",
+                "WebhookEventCalculationCompleted"
+            ]
         }
     }
 }`
@@ -1,8 +1,13 @@
 package controller
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/alishercodecrafter/orderpackscalculator/internal/model"
 	"github.com/gin-gonic/gin"
@@ -16,8 +21,52 @@ type PacksService interface {
 	AddPack(pack model.Pack) error
 	// RemovePack removes a pack by its size
 	RemovePack(packSize model.PackSize) error
-	// CalculatePacks calculates the optimal number of packs needed for an order
-	CalculatePacks(orderSize int) (model.CalculationResponse, error)
+	// ReplacePacks replaces all packs
+	ReplacePacks(packs model.Packs) error
+	// GetPackChanges returns the changes of the available packs after the version, oldest first
+	GetPackChanges(since int64) ([]model.PackChange, error)
+	// GetPacksAt returns the available packs of a version
+	GetPacksAt(version int64) (model.Packs, error)
+	// SubscribePacks subscribes to the changes of the available packs until the returned function is called
+	SubscribePacks() (<-chan model.PacksEvent, func())
+	// Calculate calculates the optimal number of packs needed for a single-line or a multi-line order
+	Calculate(ctx context.Context, req model.CalculationRequest) (model.CalculationResponse, error)
+	// GetCatalogs returns all catalogs
+	GetCatalogs() []model.Catalog
+	// SaveCatalog adds a catalog or replaces the catalog with the same ID
+	SaveCatalog(catalog model.Catalog) error
+	// RemoveCatalog removes a catalog by its ID
+	RemoveCatalog(id string) error
+	// GetPackaging returns the hierarchy of outer containers packs are shipped in
+	GetPackaging() model.Packaging
+	// SavePackaging replaces the hierarchy of outer containers packs are shipped in
+	SavePackaging(packaging model.Packaging) error
+	// GetWarehouses returns all warehouses
+	GetWarehouses() []model.Warehouse
+	// SaveWarehouse adds a warehouse or replaces the warehouse with the same ID
+	SaveWarehouse(warehouse model.Warehouse) error
+	// RemoveWarehouse removes a warehouse by its ID
+	RemoveWarehouse(id string) error
+	// GetWebhooks returns all webhooks without their secrets
+	GetWebhooks() []model.Webhook
+	// SaveWebhook adds a webhook or replaces the webhook with the same ID
+	SaveWebhook(webhook model.Webhook) error
+	// RemoveWebhook removes a webhook by its ID
+	RemoveWebhook(id string) error
+	// GetDeadLetters returns the events that could not be delivered to their webhooks, oldest first
+	GetDeadLetters() []model.DeadLetter
+	// AnalyzePacks reports how well a pack set covers a range of order sizes
+	AnalyzePacks(ctx context.Context, req model.AnalysisRequest) (model.AnalysisResponse, error)
+	// RecommendPacks proposes pack sizes for a distribution of orders
+	RecommendPacks(ctx context.Context, req model.RecommendationRequest) (model.RecommendationResponse, error)
+	// ComparePacks compares a candidate pack set with the available packs for a list of orders
+	ComparePacks(ctx context.Context, req model.ComparisonRequest) (model.ComparisonResponse, error)
+	// GetCalculations returns the calculation history, oldest first
+	GetCalculations() []model.CalculationRecord
+	// Limits returns the bounds of calculation requests
+	Limits() model.Limits
+	// RateTables returns the carrier rate tables the shipping cost of orders is priced with
+	RateTables() []model.RateTable
 }
 
 // PacksController handles HTTP requests
@@ -33,6 +82,36 @@ func NewPacksController(service PacksService) *PacksController {
 	}
 }
 
+// RegisterRoutes registers the handlers of the API under its route group, e.g. /api
+func (c *PacksController) RegisterRoutes(api gin.IRoutes) {
+	api.GET("/packs", c.GetPacks)
+	api.GET("/packs/events", c.StreamPacksEvents)
+	api.GET("/packs/changes", c.GetPackChanges)
+	api.GET("/packs/versions/:version", c.GetPacksAt)
+	api.POST("/packs", c.AddPack)
+	api.PUT("/packs", c.ReplacePacks)
+	api.DELETE("/packs/:size", c.RemovePack)
+	api.POST("/calculate", c.CalculatePacks)
+	api.GET("/catalogs", c.GetCatalogs)
+	api.PUT("/catalogs/:id", c.SaveCatalog)
+	api.DELETE("/catalogs/:id", c.RemoveCatalog)
+	api.GET("/packaging", c.GetPackaging)
+	api.PUT("/packaging", c.SavePackaging)
+	api.GET("/warehouses", c.GetWarehouses)
+	api.PUT("/warehouses/:id", c.SaveWarehouse)
+	api.DELETE("/warehouses/:id", c.RemoveWarehouse)
+	api.GET("/webhooks", c.GetWebhooks)
+	api.GET("/webhooks/dead-letters", c.GetDeadLetters)
+	api.PUT("/webhooks/:id", c.SaveWebhook)
+	api.DELETE("/webhooks/:id", c.RemoveWebhook)
+	api.POST("/analysis", c.AnalyzePacks)
+	api.POST("/recommendation", c.RecommendPacks)
+	api.POST("/comparison", c.ComparePacks)
+	api.GET("/history", c.GetCalculations)
+	api.GET("/limits", c.GetLimits)
+	api.GET("/rates", c.GetRateTables)
+}
+
 // GetPacks returns all packs
 // @Summary Get all packs
 // @Description Get a list of all available packs
@@ -43,6 +122,46 @@ func (c *PacksController) GetPacks(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, c.service.GetPacks())
 }
 
+// StreamPacksEvents streams the changes of the available packs to the client
+// @Summary Stream pack changes
+// @Description Stream the changes of the available packs as server-sent events named "packs", each carrying the kind of the change and all available packs after it, until the client disconnects
+// @Produce text/event-stream
+// @Success 200 {object} model.PacksEvent "Stream of pack change events"
+// @Router /api/packs/events [get]
+func (c *PacksController) StreamPacksEvents(ctx *gin.Context) {
+	events, unsubscribe := c.service.SubscribePacks()
+	defer unsubscribe()
+
+	keepAlive := time.NewTicker(packsEventsKeepAlive)
+	defer keepAlive.Stop()
+
+	ctx.Header("Content-Type", "text/event-stream")
+	ctx.Header("Cache-Control", "no-cache")
+	ctx.Header("X-Accel-Buffering", "no")
+
+	// send the headers right away, so that the client knows it is subscribed before the first change
+	ctx.Status(http.StatusOK)
+	_, _ = io.WriteString(ctx.Writer, ": subscribed\n\n")
+	ctx.Writer.Flush()
+
+	ctx.Stream(func(w io.Writer) bool {
+		select {
+		case <-ctx.Request.Context().Done():
+			return false
+		case event, ok := <-events:
+			if !ok {
+				return false
+			}
+			ctx.SSEvent("packs", event)
+		case <-keepAlive.C:
+			// a comment keeps an idle stream open through proxies that close idle connections
+			_, _ = io.WriteString(w, ": keep-alive\n\n")
+		}
+
+		return true
+	})
+}
+
 // AddPack adds a new pack
 // @Summary Add pack
 // @Description Add a new pack with its properties
@@ -50,24 +169,39 @@ func (c *PacksController) GetPacks(ctx *gin.Context) {
 // @Produce json
 // @Param request body model.AddPackRequest true "Pack to add"
 // @Success 200 {object} map[string]interface{} "Success response"
-// @Failure 400 {object} map[string]string "Error response"
+// @Failure 400 {object} model.ErrorResponse "Error response"
 // @Router /api/packs [post]
 func (c *PacksController) AddPack(ctx *gin.Context) {
 	var req model.AddPackRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		respondError(ctx, http.StatusBadRequest, model.NewError(model.ErrorCodeInvalidRequest, "Invalid request"))
 
 		return
 	}
 
 	if req.Pack.Size <= 0 {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Pack size must be greater than zero"})
+		respondError(
+			ctx,
+			http.StatusBadRequest,
+			model.NewError(model.ErrorCodeInvalidPackSize, "Pack size must be greater than zero"),
+		)
+
+		return
+	}
+
+	limits := c.service.Limits()
+	if limits.MaxPacks > 0 && len(c.service.GetPacks()) >= limits.MaxPacks {
+		respondError(
+			ctx,
+			http.StatusBadRequest,
+			model.NewError(model.ErrorCodeTooManyPacks, fmt.Sprintf("Number of packs cannot exceed %d", limits.MaxPacks)),
+		)
 
 		return
 	}
 
 	if err := c.service.AddPack(req.Pack); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 
 		return
 	}
@@ -81,19 +215,19 @@ func (c *PacksController) AddPack(ctx *gin.Context) {
 // @Produce json
 // @Param size path int true "Pack size to remove"
 // @Success 200 {object} map[string]interface{} "Success response"
-// @Failure 400 {object} map[string]string "Error response"
+// @Failure 400 {object} model.ErrorResponse "Error response"
 // @Router /api/packs/{size} [delete]
 func (c *PacksController) RemovePack(ctx *gin.Context) {
 	sizeStr := ctx.Param("size")
-	size, err := strconv.Atoi(sizeStr)
+	size, err := strconv.ParseInt(sizeStr, 10, 64)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid pack size"})
+		respondError(ctx, http.StatusBadRequest, model.NewError(model.ErrorCodeInvalidPackSize, "Invalid pack size"))
 
 		return
 	}
 
 	if err := c.service.RemovePack(model.PackSize(size)); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 
 		return
 	}
@@ -101,6 +235,58 @@ func (c *PacksController) RemovePack(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, gin.H{"success": true})
 }
 
+// GetPackChanges returns the event log of the packs
+// @Summary Get pack changes
+// @Description Get the changes of the available packs after a version, oldest first. The version of the packs after a change is the position of the change in the log counted from 1
+// @Produce json
+// @Param since query int false "Version after which the changes are returned, all changes if it is not set"
+// @Success 200 {array} model.PackChange "Pack changes"
+// @Failure 400 {object} model.ErrorResponse "Error response"
+// @Router /api/packs/changes [get]
+func (c *PacksController) GetPackChanges(ctx *gin.Context) {
+	since, err := strconv.ParseInt(ctx.DefaultQuery("since", "0"), 10, 64)
+	if err != nil {
+		respondError(ctx, http.StatusBadRequest, model.NewError(model.ErrorCodeInvalidRequest, "Invalid packs version"))
+
+		return
+	}
+
+	changes, err := c.service.GetPackChanges(since)
+	if err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+
+		return
+	}
+
+	ctx.JSON(http.StatusOK, changes)
+}
+
+// GetPacksAt returns the packs of a version
+// @Summary Get packs of a version
+// @Description Get the available packs as they were at a version, rebuilt from their changes
+// @Produce json
+// @Param version path int true "Version of the packs"
+// @Success 200 {array} model.Pack "List of packs"
+// @Failure 400 {object} model.ErrorResponse "Error response"
+// @Router /api/packs/versions/{version} [get]
+func (c *PacksController) GetPacksAt(ctx *gin.Context) {
+	version, err := strconv.ParseInt(ctx.Param("version"), 10, 64)
+	if err != nil {
+		respondError(ctx, http.StatusBadRequest, model.NewError(model.ErrorCodeInvalidRequest, "Invalid packs version"))
+
+		return
+	}
+
+	packs, err := c.service.GetPacksAt(version)
+	if err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+
+		return
+	}
+
+	ctx.JSON(http.StatusOK, packs)
+}
+
 // GetIndex renders the main page
 // @Summary Render main page
 // @Description Get the main page of the Pack Calculator app
@@ -114,35 +300,451 @@ func (c *PacksController) GetIndex(ctx *gin.Context) {
 	})
 }
 
+// ReplacePacks replaces all packs
+// @Summary Replace packs
+// @Description Replace all available packs at once
+// @Accept json
+// @Produce json
+// @Param request body model.ReplacePacksRequest true "Packs to replace the available packs with"
+// @Success 200 {object} map[string]interface{} "Success response"
+// @Failure 400 {object} model.ErrorResponse "Error response"
+// @Router /api/packs [put]
+func (c *PacksController) ReplacePacks(ctx *gin.Context) {
+	var req model.ReplacePacksRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		respondError(ctx, http.StatusBadRequest, model.NewError(model.ErrorCodeInvalidRequest, "Invalid request"))
+
+		return
+	}
+
+	if err := c.service.ReplacePacks(req.Packs); err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"success": true})
+}
+
 // CalculatePacks calculates the number of packs needed
 // @Summary Calculate packs
-// @Description Calculate the optimal number of packs needed for an order
+// @Description Calculate the optimal number of packs needed for an order, or for each line of a multi-line order with the packs of its catalog, overshipping or, in the undership mode, backordering the rest, optionally with the shipping and landed cost
 // @Accept json
 // @Produce json
-// @Param request body model.CalculationRequest true "Order size"
+// @Param request body model.CalculationRequest true "Order size or order lines"
 // @Success 200 {object} model.CalculationResponse "Calculation result"
-// @Failure 400 {object} map[string]string "Error response"
+// @Failure 400 {object} model.ErrorResponse "Error response"
+// @Failure 408 {object} model.ErrorResponse "Calculation timed out or was cancelled"
+// @Failure 422 {object} model.ErrorResponse "Calculation exceeded its work budget or the overshipment tolerance"
 // @Router /api/calculate [post]
 func (c *PacksController) CalculatePacks(ctx *gin.Context) {
 	var req model.CalculationRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		respondError(ctx, http.StatusBadRequest, model.NewError(model.ErrorCodeInvalidRequest, "Invalid request"))
+
+		return
+	}
+
+	orderSizes := []int64{req.OrderSize}
+	if len(req.Lines) > 0 {
+		orderSizes = orderSizes[:0]
+		for _, line := range req.Lines {
+			// the amount of a product measured in kilograms or litres is checked in thousandths of its unit
+			orderSize := line.Quantity
+			if line.Amount != 0 {
+				orderSize = int64(line.Amount)
+			}
+			orderSizes = append(orderSizes, orderSize)
+		}
+	}
+
+	limits := c.service.Limits()
+	for _, orderSize := range orderSizes {
+		if orderSize <= 0 {
+			respondError(
+				ctx,
+				http.StatusBadRequest,
+				model.NewError(model.ErrorCodeInvalidOrderSize, "Order size must be greater than zero"),
+			)
+
+			return
+		}
+
+		if limits.MaxOrderSize > 0 && orderSize > limits.MaxOrderSize {
+			respondError(
+				ctx,
+				http.StatusBadRequest,
+				model.NewError(
+					model.ErrorCodeOrderSizeTooLarge,
+					fmt.Sprintf("Order size cannot exceed %d", limits.MaxOrderSize),
+				),
+			)
+
+			return
+		}
+	}
+
+	result, err := c.service.Calculate(ctx.Request.Context(), req)
+	if err != nil {
+		respondError(ctx, calculationErrorStatus(err), err)
+
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+// GetCatalogs returns all catalogs
+// @Summary Get all catalogs
+// @Description Get a list of all product catalogs with their packs, starting with the default catalog of the available packs
+// @Produce json
+// @Success 200 {array} model.Catalog "List of catalogs"
+// @Router /api/catalogs [get]
+func (c *PacksController) GetCatalogs(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, c.service.GetCatalogs())
+}
+
+// SaveCatalog adds or replaces a catalog
+// @Summary Save catalog
+// @Description Add a product catalog or replace the catalog with the same ID
+// @Accept json
+// @Produce json
+// @Param id path string true "Catalog ID"
+// @Param request body model.Catalog true "Catalog to save"
+// @Success 200 {object} map[string]interface{} "Success response"
+// @Failure 400 {object} model.ErrorResponse "Error response"
+// @Router /api/catalogs/{id} [put]
+func (c *PacksController) SaveCatalog(ctx *gin.Context) {
+	var catalog model.Catalog
+	if err := ctx.ShouldBindJSON(&catalog); err != nil {
+		respondError(ctx, http.StatusBadRequest, model.NewError(model.ErrorCodeInvalidRequest, "Invalid request"))
+
+		return
+	}
+	catalog.ID = ctx.Param("id")
+
+	if err := c.service.SaveCatalog(catalog); err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"success": true})
+}
+
+// RemoveCatalog removes a catalog
+// @Summary Remove catalog
+// @Description Remove a product catalog by its ID
+// @Produce json
+// @Param id path string true "Catalog ID"
+// @Success 200 {object} map[string]interface{} "Success response"
+// @Failure 400 {object} model.ErrorResponse "Error response"
+// @Router /api/catalogs/{id} [delete]
+func (c *PacksController) RemoveCatalog(ctx *gin.Context) {
+	if err := c.service.RemoveCatalog(ctx.Param("id")); err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"success": true})
+}
+
+// GetPackaging returns the packaging hierarchy
+// @Summary Get packaging hierarchy
+// @Description Get the levels of outer containers packs are shipped in, innermost first
+// @Produce json
+// @Success 200 {object} model.Packaging "Packaging hierarchy"
+// @Router /api/packaging [get]
+func (c *PacksController) GetPackaging(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, c.service.GetPackaging())
+}
+
+// SavePackaging replaces the packaging hierarchy
+// @Summary Save packaging hierarchy
+// @Description Replace the levels of outer containers packs are shipped in, e.g. cartons holding items and pallets holding cartons
+// @Accept json
+// @Produce json
+// @Param request body model.Packaging true "Packaging hierarchy to save"
+// @Success 200 {object} map[string]interface{} "Success response"
+// @Failure 400 {object} model.ErrorResponse "Error response"
+// @Router /api/packaging [put]
+func (c *PacksController) SavePackaging(ctx *gin.Context) {
+	var packaging model.Packaging
+	if err := ctx.ShouldBindJSON(&packaging); err != nil {
+		respondError(ctx, http.StatusBadRequest, model.NewError(model.ErrorCodeInvalidRequest, "Invalid request"))
+
+		return
+	}
+
+	if err := c.service.SavePackaging(packaging); err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"success": true})
+}
+
+// GetWarehouses returns all warehouses
+// @Summary Get all warehouses
+// @Description Get a list of all warehouses with their pack stock
+// @Produce json
+// @Success 200 {array} model.Warehouse "List of warehouses"
+// @Router /api/warehouses [get]
+func (c *PacksController) GetWarehouses(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, c.service.GetWarehouses())
+}
+
+// SaveWarehouse adds or replaces a warehouse
+// @Summary Save warehouse
+// @Description Add a warehouse or replace the warehouse with the same ID, together with its pack stock
+// @Accept json
+// @Produce json
+// @Param id path string true "Warehouse ID"
+// @Param request body model.Warehouse true "Warehouse to save"
+// @Success 200 {object} map[string]interface{} "Success response"
+// @Failure 400 {object} model.ErrorResponse "Error response"
+// @Router /api/warehouses/{id} [put]
+func (c *PacksController) SaveWarehouse(ctx *gin.Context) {
+	var warehouse model.Warehouse
+	if err := ctx.ShouldBindJSON(&warehouse); err != nil {
+		respondError(ctx, http.StatusBadRequest, model.NewError(model.ErrorCodeInvalidRequest, "Invalid request"))
+
+		return
+	}
+	warehouse.ID = ctx.Param("id")
+
+	if err := c.service.SaveWarehouse(warehouse); err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"success": true})
+}
+
+// RemoveWarehouse removes a warehouse
+// @Summary Remove warehouse
+// @Description Remove a warehouse by its ID
+// @Produce json
+// @Param id path string true "Warehouse ID"
+// @Success 200 {object} map[string]interface{} "Success response"
+// @Failure 400 {object} model.ErrorResponse "Error response"
+// @Router /api/warehouses/{id} [delete]
+func (c *PacksController) RemoveWarehouse(ctx *gin.Context) {
+	if err := c.service.RemoveWarehouse(ctx.Param("id")); err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"success": true})
+}
+
+// GetWebhooks returns all webhooks
+// @Summary Get all webhooks
+// @Description Get a list of all webhooks with the events they are subscribed to, without their secrets
+// @Produce json
+// @Success 200 {array} model.Webhook "List of webhooks"
+// @Router /api/webhooks [get]
+func (c *PacksController) GetWebhooks(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, c.service.GetWebhooks())
+}
+
+// SaveWebhook adds or replaces a webhook
+// @Summary Save webhook
+// @Description Add a webhook or replace the webhook with the same ID. The events it is subscribed to are posted to its URL as JSON, signed with its secret in the X-Webhook-Signature header
+// @Accept json
+// @Produce json
+// @Param id path string true "Webhook ID"
+// @Param request body model.Webhook true "Webhook to save"
+// @Success 200 {object} map[string]interface{} "Success response"
+// @Failure 400 {object} model.ErrorResponse "Error response"
+// @Router /api/webhooks/{id} [put]
+func (c *PacksController) SaveWebhook(ctx *gin.Context) {
+	var webhook model.Webhook
+	if err := ctx.ShouldBindJSON(&webhook); err != nil {
+		respondError(ctx, http.StatusBadRequest, model.NewError(model.ErrorCodeInvalidRequest, "Invalid request"))
+
+		return
+	}
+	webhook.ID = ctx.Param("id")
+
+	if err := c.service.SaveWebhook(webhook); err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"success": true})
+}
+
+// RemoveWebhook removes a webhook
+// @Summary Remove webhook
+// @Description Remove a webhook by its ID
+// @Produce json
+// @Param id path string true "Webhook ID"
+// @Success 200 {object} map[string]interface{} "Success response"
+// @Failure 400 {object} model.ErrorResponse "Error response"
+// @Router /api/webhooks/{id} [delete]
+func (c *PacksController) RemoveWebhook(ctx *gin.Context) {
+	if err := c.service.RemoveWebhook(ctx.Param("id")); err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"success": true})
+}
+
+// GetDeadLetters returns the undelivered webhook events
+// @Summary Get undelivered webhook events
+// @Description Get the most recent events that could not be delivered to their webhooks within the retry attempts, oldest first
+// @Produce json
+// @Success 200 {array} model.DeadLetter "List of undelivered events"
+// @Router /api/webhooks/dead-letters [get]
+func (c *PacksController) GetDeadLetters(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, c.service.GetDeadLetters())
+}
+
+// AnalyzePacks reports how well a pack set covers a range of order sizes
+// @Summary Analyze pack set coverage
+// @Description Report the overshipment, the largest order that cannot be matched exactly, the pack count distribution and the worst orders of a pack set for a range of order sizes, counting apart the orders the packs cannot hold within their maximum counts
+// @Accept json
+// @Produce json
+// @Param request body model.AnalysisRequest true "Pack set and order size range"
+// @Success 200 {object} model.AnalysisResponse "Analysis result"
+// @Failure 400 {object} model.ErrorResponse "Error response"
+// @Failure 408 {object} model.ErrorResponse "Analysis timed out or was cancelled"
+// @Failure 422 {object} model.ErrorResponse "Analysis exceeded its work budget"
+// @Router /api/analysis [post]
+func (c *PacksController) AnalyzePacks(ctx *gin.Context) {
+	var req model.AnalysisRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		respondError(ctx, http.StatusBadRequest, model.NewError(model.ErrorCodeInvalidRequest, "Invalid request"))
+
+		return
+	}
+
+	result, err := c.service.AnalyzePacks(ctx.Request.Context(), req)
+	if err != nil {
+		respondError(ctx, calculationErrorStatus(err), err)
+
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+// RecommendPacks proposes pack sizes for a distribution of orders
+// @Summary Recommend pack sizes
+// @Description Propose pack sizes that ship the given or the historical orders with the least overshipment and packs, compared with the available packs
+// @Accept json
+// @Produce json
+// @Param request body model.RecommendationRequest true "Order sizes and number of pack sizes"
+// @Success 200 {object} model.RecommendationResponse "Recommendation"
+// @Failure 400 {object} model.ErrorResponse "Error response"
+// @Failure 408 {object} model.ErrorResponse "Recommendation timed out or was cancelled"
+// @Failure 422 {object} model.ErrorResponse "Recommendation exceeded its work budget"
+// @Router /api/recommendation [post]
+func (c *PacksController) RecommendPacks(ctx *gin.Context) {
+	var req model.RecommendationRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		respondError(ctx, http.StatusBadRequest, model.NewError(model.ErrorCodeInvalidRequest, "Invalid request"))
 
 		return
 	}
 
-	if req.OrderSize <= 0 {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Order size must be greater than zero"})
+	result, err := c.service.RecommendPacks(ctx.Request.Context(), req)
+	if err != nil {
+		respondError(ctx, calculationErrorStatus(err), err)
+
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+// ComparePacks compares a candidate pack set with the available packs
+// @Summary Compare pack sets
+// @Description Calculate a list of orders with the available packs and a candidate pack set and report the per-order changes and the changes of the shipped items, packs and cost
+// @Accept json
+// @Produce json
+// @Param request body model.ComparisonRequest true "Candidate pack set and order sizes"
+// @Success 200 {object} model.ComparisonResponse "Comparison result"
+// @Failure 400 {object} model.ErrorResponse "Error response"
+// @Failure 408 {object} model.ErrorResponse "Comparison timed out or was cancelled"
+// @Failure 422 {object} model.ErrorResponse "Comparison exceeded its work budget"
+// @Router /api/comparison [post]
+func (c *PacksController) ComparePacks(ctx *gin.Context) {
+	var req model.ComparisonRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		respondError(ctx, http.StatusBadRequest, model.NewError(model.ErrorCodeInvalidRequest, "Invalid request"))
 
 		return
 	}
 
-	result, err := c.service.CalculatePacks(req.OrderSize)
+	result, err := c.service.ComparePacks(ctx.Request.Context(), req)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, calculationErrorStatus(err), err)
 
 		return
 	}
 
 	ctx.JSON(http.StatusOK, result)
 }
+
+// GetCalculations returns the calculation history
+// @Summary Get calculation history
+// @Description Get the most recent calculations, oldest first
+// @Produce json
+// @Success 200 {array} model.CalculationRecord "Calculation history"
+// @Router /api/history [get]
+func (c *PacksController) GetCalculations(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, c.service.GetCalculations())
+}
+
+// GetLimits returns the bounds of calculation requests
+// @Summary Get limits
+// @Description Get the bounds order sizes and pack sets must stay within
+// @Produce json
+// @Success 200 {object} model.Limits "Limits"
+// @Router /api/limits [get]
+func (c *PacksController) GetLimits(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, c.service.Limits())
+}
+
+// GetRateTables returns the carrier rate tables
+// @Summary Get carrier rate tables
+// @Description Get the carrier rate tables the shipping cost of orders is priced with, loaded from the rates file
+// @Produce json
+// @Success 200 {array} model.RateTable "Carrier rate tables"
+// @Router /api/rates [get]
+func (c *PacksController) GetRateTables(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, c.service.RateTables())
+}
+
+// packsEventsKeepAlive is the interval of the comments sent on an idle stream of pack changes
+const packsEventsKeepAlive = 25 * time.Second
+
+// calculationErrorStatus maps a calculation error to the HTTP status code
+func calculationErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, model.ErrCalculationTimeout), errors.Is(err, context.Canceled):
+		return http.StatusRequestTimeout
+	case errors.Is(err, model.ErrCalculationBudgetExceeded),
+		model.ErrorCodeOf(err) == model.ErrorCodeToleranceExceeded:
+		return http.StatusUnprocessableEntity
+	default:
+		return http.StatusBadRequest
+	}
+}
+
+// respondError writes err with its error code as the response
+func respondError(ctx *gin.Context, status int, err error) {
+	ctx.JSON(status, model.ErrorResponse{
+		Error: err.Error(),
+		Code:  model.ErrorCodeOf(err),
+	})
+}
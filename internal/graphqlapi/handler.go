@@ -0,0 +1,214 @@
+package graphqlapi
+
+import (
+	"context"
+	_ "embed"
+	"net/http"
+	"strings"
+
+	"github.com/alishercodecrafter/orderpackscalculator/internal/model"
+	"github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/relay"
+)
+
+// schema is the GraphQL schema of the API
+//
+//go:embed schema.graphql
+var schema string
+
+// maxDepth is the deepest nesting of fields a query may select
+const maxDepth = 10
+
+// PacksService defines the operations of the pack calculation service the GraphQL API serves
+type PacksService interface {
+	// GetPacks returns all available packs
+	GetPacks() model.Packs
+	// AddPack adds a new pack
+	AddPack(pack model.Pack) error
+	// RemovePack removes a pack by its size
+	RemovePack(packSize model.PackSize) error
+	// ReplacePacks replaces all packs
+	ReplacePacks(packs model.Packs) error
+	// Calculate calculates the optimal number of packs needed for a single-line or a multi-line order
+	Calculate(ctx context.Context, req model.CalculationRequest) (model.CalculationResponse, error)
+	// GetCatalogs returns all catalogs
+	GetCatalogs() []model.Catalog
+	// GetCalculations returns the calculation history, oldest first
+	GetCalculations() []model.CalculationRecord
+}
+
+// NewHandler creates the HTTP handler of the GraphQL API of the service.
+// It executes the query of a POST request with a JSON body of the query, its operation name and its variables.
+func NewHandler(service PacksService) http.Handler {
+	return &relay.Handler{
+		Schema: graphql.MustParseSchema(schema, &resolver{service: service}, graphql.MaxDepth(maxDepth)),
+	}
+}
+
+// resolver resolves the queries and the mutations of the API
+type resolver struct {
+	service PacksService
+}
+
+// Packs returns all available packs
+func (r *resolver) Packs() []*packResolver {
+	return packResolvers(r.service.GetPacks())
+}
+
+// Catalogs returns all catalogs
+func (r *resolver) Catalogs() []*catalogResolver {
+	catalogs := r.service.GetCatalogs()
+
+	result := make([]*catalogResolver, 0, len(catalogs))
+	for _, catalog := range catalogs {
+		result = append(result, &catalogResolver{catalog: catalog})
+	}
+
+	return result
+}
+
+// Calculations returns the calculation history, the most recent records if last is set
+func (r *resolver) Calculations(args struct{ Last *int32 }) ([]*recordResolver, error) {
+	records := r.service.GetCalculations()
+	if args.Last != nil {
+		if *args.Last < 0 {
+			return nil, resolverError(model.NewError(model.ErrorCodeInvalidRequest, "last cannot be negative"))
+		}
+		records = records[max(len(records)-int(*args.Last), 0):]
+	}
+
+	result := make([]*recordResolver, 0, len(records))
+	for _, record := range records {
+		result = append(result, &recordResolver{record: record})
+	}
+
+	return result, nil
+}
+
+// calculationInput is the order of a calculation
+type calculationInput struct {
+	OrderSize *long
+	Lines     *[]orderLineInput
+	Mode      *string
+}
+
+// orderLineInput is a line of a multi-line order
+type orderLineInput struct {
+	Catalog  *graphql.ID
+	Quantity *long
+	Amount   *decimal
+}
+
+// Calculate calculates the packs of an order
+func (r *resolver) Calculate(ctx context.Context, args struct{ Input calculationInput }) (*calculationResolver, error) {
+	req := model.CalculationRequest{OrderSize: int64(deref(args.Input.OrderSize))}
+	if args.Input.Mode != nil {
+		req.Mode = model.ShipmentMode(strings.ToLower(*args.Input.Mode))
+	}
+	if args.Input.Lines != nil {
+		for _, line := range *args.Input.Lines {
+			req.Lines = append(req.Lines, model.OrderLine{
+				Catalog:  string(deref(line.Catalog)),
+				Quantity: int64(deref(line.Quantity)),
+				Amount:   model.Quantity(deref(line.Amount)),
+			})
+		}
+	}
+
+	result, err := r.service.Calculate(ctx, req)
+	if err != nil {
+		return nil, resolverError(err)
+	}
+
+	return &calculationResolver{result: result}, nil
+}
+
+// packInput is a pack to add
+type packInput struct {
+	Size     long
+	Cost     *long
+	MinCount *long
+	MaxCount *long
+	Weight   *long
+	Volume   *long
+	Content  *decimal
+}
+
+// pack converts the input to a pack
+func (p packInput) pack() model.Pack {
+	return model.Pack{
+		Size:     model.PackSize(p.Size),
+		Cost:     int64(deref(p.Cost)),
+		MinCount: int64(deref(p.MinCount)),
+		MaxCount: int64(deref(p.MaxCount)),
+		Weight:   int64(deref(p.Weight)),
+		Volume:   int64(deref(p.Volume)),
+		Content:  model.Quantity(deref(p.Content)),
+	}
+}
+
+// AddPack adds a new pack and returns the available packs
+func (r *resolver) AddPack(args struct{ Pack packInput }) ([]*packResolver, error) {
+	if err := r.service.AddPack(args.Pack.pack()); err != nil {
+		return nil, resolverError(err)
+	}
+
+	return r.Packs(), nil
+}
+
+// RemovePack removes a pack by its size and returns the available packs
+func (r *resolver) RemovePack(args struct{ Size long }) ([]*packResolver, error) {
+	if args.Size <= 0 {
+		return nil, resolverError(model.NewError(model.ErrorCodeInvalidPackSize, "Invalid pack size"))
+	}
+
+	if err := r.service.RemovePack(model.PackSize(args.Size)); err != nil {
+		return nil, resolverError(err)
+	}
+
+	return r.Packs(), nil
+}
+
+// ReplacePacks replaces all packs and returns the available packs
+func (r *resolver) ReplacePacks(args struct{ Packs []packInput }) ([]*packResolver, error) {
+	packs := make(model.Packs, 0, len(args.Packs))
+	for _, pack := range args.Packs {
+		packs = append(packs, pack.pack())
+	}
+
+	if err := r.service.ReplacePacks(packs); err != nil {
+		return nil, resolverError(err)
+	}
+
+	return r.Packs(), nil
+}
+
+// codedError is an error of the service that reports its error code in the extensions of the GraphQL error
+type codedError struct {
+	error
+}
+
+// Extensions returns the error code of the error
+func (e codedError) Extensions() map[string]any {
+	return map[string]any{"code": model.ErrorCodeOf(e.error)}
+}
+
+// resolverError wraps an error of the service to report its error code, if it has one
+func resolverError(err error) error {
+	if model.ErrorCodeOf(err) == "" {
+		return err
+	}
+
+	return codedError{err}
+}
+
+// deref returns the value p points to, or the zero value if it is nil
+func deref[T any](p *T) T {
+	if p == nil {
+		var zero T
+
+		return zero
+	}
+
+	return *p
+}
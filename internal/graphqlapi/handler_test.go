@@ -0,0 +1,174 @@
+package graphqlapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/alishercodecrafter/orderpackscalculator/internal/model"
+	"github.com/alishercodecrafter/orderpackscalculator/internal/repository"
+	"github.com/alishercodecrafter/orderpackscalculator/internal/service"
+	"github.com/stretchr/testify/require"
+)
+
+// graphqlResponse is the JSON response of a GraphQL query
+type graphqlResponse struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []struct {
+		Message    string         `json:"message"`
+		Extensions map[string]any `json:"extensions"`
+	} `json:"errors"`
+}
+
+// newTestHandler creates the handler of a service with the default packs and the given limits
+func newTestHandler(limits model.Limits) http.Handler {
+	return NewHandler(service.NewPacksService(repository.NewMemoryRepository(), service.WithLimits(limits)))
+}
+
+// execute posts the query with its variables to the handler and decodes the response
+func execute(t *testing.T, handler http.Handler, query string, variables map[string]any) graphqlResponse {
+	t.Helper()
+
+	body, err := json.Marshal(map[string]any{"query": query, "variables": variables})
+	require.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(string(body))))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var resp graphqlResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+
+	return resp
+}
+
+func TestHandler_Packs(t *testing.T) {
+	handler := newTestHandler(model.Limits{})
+
+	resp := execute(t, handler, `{ packs { size } }`, nil)
+	require.Empty(t, resp.Errors)
+	require.JSONEq(t, `{"packs":[{"size":250},{"size":500},{"size":1000},{"size":2000},{"size":5000}]}`, string(resp.Data))
+
+	resp = execute(t, handler, `mutation { addPack(pack: {size: 750, cost: 120}) { size cost content } }`, nil)
+	require.Empty(t, resp.Errors)
+	require.Contains(t, string(resp.Data), `{"size":750,"cost":120,"content":null}`)
+
+	resp = execute(t, handler, `mutation { removePack(size: "5000") { size } }`, nil)
+	require.Empty(t, resp.Errors)
+	require.JSONEq(t, `{"removePack":[{"size":250},{"size":500},{"size":750},{"size":1000},{"size":2000}]}`,
+		string(resp.Data))
+
+	resp = execute(t, handler, `mutation($packs: [PackInput!]!) { replacePacks(packs: $packs) { size } }`,
+		map[string]any{"packs": []map[string]any{{"size": 23}, {"size": 31}, {"size": 53}}})
+	require.Empty(t, resp.Errors)
+	require.JSONEq(t, `{"replacePacks":[{"size":23},{"size":31},{"size":53}]}`, string(resp.Data))
+}
+
+func TestHandler_Errors(t *testing.T) {
+	handler := newTestHandler(model.Limits{MaxOrderSize: 1000})
+
+	tests := []struct {
+		name  string
+		query string
+		code  model.ErrorCode
+	}{
+		{
+			name:  "pack exists",
+			query: `mutation { addPack(pack: {size: 250}) { size } }`,
+			code:  model.ErrorCodePackExists,
+		},
+		{
+			name:  "pack not found",
+			query: `mutation { removePack(size: 42) { size } }`,
+			code:  model.ErrorCodePackNotFound,
+		},
+		{
+			name:  "order size too large",
+			query: `{ calculate(input: {orderSize: 1001}) { orderSize } }`,
+			code:  model.ErrorCodeOrderSizeTooLarge,
+		},
+		{
+			name:  "negative last",
+			query: `{ calculations(last: -1) { calculatedAt } }`,
+			code:  model.ErrorCodeInvalidRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := execute(t, handler, tt.query, nil)
+			require.Len(t, resp.Errors, 1)
+			require.NotEmpty(t, resp.Errors[0].Message)
+			require.Equal(t, string(tt.code), resp.Errors[0].Extensions["code"])
+		})
+	}
+}
+
+func TestHandler_Calculate(t *testing.T) {
+	handler := newTestHandler(model.Limits{})
+
+	resp := execute(t, handler,
+		`query($size: Int64) { calculate(input: {orderSize: $size}) { orderSize packs { size count } lines { catalog } } }`,
+		map[string]any{"size": 12001})
+	require.Empty(t, resp.Errors)
+	require.JSONEq(t,
+		`{"calculate":{"orderSize":12001,"packs":[{"size":250,"count":1},{"size":2000,"count":1},{"size":5000,"count":2}],"lines":[]}}`,
+		string(resp.Data))
+
+	resp = execute(t, handler, `{ calculate(input: {orderSize: 251, mode: UNDERSHIP}) { backorder } }`, nil)
+	require.Empty(t, resp.Errors)
+	require.JSONEq(t, `{"calculate":{"backorder":1}}`, string(resp.Data))
+
+	resp = execute(t, handler,
+		`{ calculate(input: {lines: [{quantity: 501}, {quantity: 250}]}) { lines { unit shippedItems } totals { shippedItems } } }`,
+		nil)
+	require.Empty(t, resp.Errors)
+	require.JSONEq(t,
+		`{"calculate":{"lines":[{"unit":"item","shippedItems":750},{"unit":"item","shippedItems":250}],"totals":{"shippedItems":1000}}}`,
+		string(resp.Data))
+
+	resp = execute(t, handler, `{ calculations(last: 1) { result { totals { shippedItems } } } }`, nil)
+	require.Empty(t, resp.Errors)
+	require.JSONEq(t, `{"calculations":[{"result":{"totals":{"shippedItems":1000}}}]}`, string(resp.Data))
+
+	resp = execute(t, handler, `{ calculations { calculatedAt } }`, nil)
+	require.Empty(t, resp.Errors)
+	require.Equal(t, 3, strings.Count(string(resp.Data), "calculatedAt"))
+}
+
+func TestHandler_Catalogs(t *testing.T) {
+	handler := newTestHandler(model.Limits{})
+
+	resp := execute(t, handler, `{ catalogs { id name unit packs { size } tolerance { reject } } }`, nil)
+	require.Empty(t, resp.Errors)
+	require.Contains(t, string(resp.Data), `"id":"default"`)
+	require.Contains(t, string(resp.Data), `"unit":"item"`)
+	require.Contains(t, string(resp.Data), `"tolerance":null`)
+}
+
+func TestScalars(t *testing.T) {
+	var l long
+	require.NoError(t, l.UnmarshalGraphQL(int32(42)))
+	require.Equal(t, long(42), l)
+	require.NoError(t, l.UnmarshalGraphQL(float64(3000000000)))
+	require.Equal(t, long(3000000000), l)
+	require.NoError(t, l.UnmarshalGraphQL("9000000000"))
+	require.Equal(t, long(9000000000), l)
+	require.Error(t, l.UnmarshalGraphQL(1.5))
+	require.Error(t, l.UnmarshalGraphQL("x"))
+
+	var d decimal
+	require.NoError(t, d.UnmarshalGraphQL(12.5))
+	require.Equal(t, decimal(12500), d)
+	require.NoError(t, d.UnmarshalGraphQL("0.125"))
+	require.Equal(t, decimal(125), d)
+	require.NoError(t, d.UnmarshalGraphQL(int32(2)))
+	require.Equal(t, decimal(2000), d)
+	require.Error(t, d.UnmarshalGraphQL("0.0001"))
+
+	data, err := json.Marshal(decimal(12500))
+	require.NoError(t, err)
+	require.Equal(t, "12.5", string(data))
+}
@@ -0,0 +1,246 @@
+package graphqlapi
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+
+	"github.com/alishercodecrafter/orderpackscalculator/internal/model"
+	"github.com/graph-gophers/graphql-go"
+)
+
+// long is the Int64 scalar, a 64-bit integer sent as a number or as a string of digits
+type long int64
+
+// ImplementsGraphQLType reports whether long implements the GraphQL type name
+func (long) ImplementsGraphQLType(name string) bool {
+	return name == "Int64"
+}
+
+// UnmarshalGraphQL decodes the integer from a literal of a query or from a JSON variable
+func (l *long) UnmarshalGraphQL(input any) error {
+	switch v := input.(type) {
+	case int32:
+		*l = long(v)
+	case float64:
+		if v != math.Trunc(v) || v < math.MinInt64 || v >= math.MaxInt64 {
+			return fmt.Errorf("%v is not a 64-bit integer", v)
+		}
+		*l = long(v)
+	case string:
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return fmt.Errorf("%q is not a 64-bit integer", v)
+		}
+		*l = long(n)
+	default:
+		return fmt.Errorf("wrong type for Int64: %T", v)
+	}
+
+	return nil
+}
+
+// MarshalJSON encodes the integer as a JSON number
+func (l long) MarshalJSON() ([]byte, error) {
+	return strconv.AppendInt(nil, int64(l), 10), nil
+}
+
+// decimal is the Decimal scalar, an amount measured in kilograms or litres
+type decimal model.Quantity
+
+// ImplementsGraphQLType reports whether decimal implements the GraphQL type name
+func (decimal) ImplementsGraphQLType(name string) bool {
+	return name == "Decimal"
+}
+
+// UnmarshalGraphQL decodes the amount from a number or a string of a literal of a query or of a JSON variable
+func (d *decimal) UnmarshalGraphQL(input any) error {
+	var s string
+	switch v := input.(type) {
+	case int32:
+		s = strconv.FormatInt(int64(v), 10)
+	case float64:
+		s = strconv.FormatFloat(v, 'f', -1, 64)
+	case string:
+		s = v
+	default:
+		return fmt.Errorf("wrong type for Decimal: %T", v)
+	}
+
+	q, err := model.ParseQuantity(s)
+	if err != nil {
+		return err
+	}
+	*d = decimal(q)
+
+	return nil
+}
+
+// MarshalJSON encodes the amount as a JSON number
+func (d decimal) MarshalJSON() ([]byte, error) {
+	return model.Quantity(d).MarshalJSON()
+}
+
+// optionalDecimal returns the amount, nil if it is zero
+func optionalDecimal(q model.Quantity) *decimal {
+	if q == 0 {
+		return nil
+	}
+	d := decimal(q)
+
+	return &d
+}
+
+// packResolver resolves a pack
+type packResolver struct {
+	pack model.Pack
+}
+
+// packResolvers resolves packs
+func packResolvers(packs model.Packs) []*packResolver {
+	result := make([]*packResolver, 0, len(packs))
+	for _, pack := range packs {
+		result = append(result, &packResolver{pack: pack})
+	}
+
+	return result
+}
+
+func (r *packResolver) Size() long        { return long(r.pack.Size) }
+func (r *packResolver) Cost() long        { return long(r.pack.Cost) }
+func (r *packResolver) MinCount() long    { return long(r.pack.MinCount) }
+func (r *packResolver) MaxCount() long    { return long(r.pack.MaxCount) }
+func (r *packResolver) Weight() long      { return long(r.pack.Weight) }
+func (r *packResolver) Volume() long      { return long(r.pack.Volume) }
+func (r *packResolver) Content() *decimal { return optionalDecimal(r.pack.Content) }
+
+// catalogResolver resolves a catalog
+type catalogResolver struct {
+	catalog model.Catalog
+}
+
+func (r *catalogResolver) ID() graphql.ID         { return graphql.ID(r.catalog.ID) }
+func (r *catalogResolver) Name() string           { return r.catalog.Name }
+func (r *catalogResolver) Unit() string           { return string(cmp.Or(r.catalog.Unit, model.UnitItem)) }
+func (r *catalogResolver) Packs() []*packResolver { return packResolvers(r.catalog.Packs) }
+
+// Tolerance returns the overshipment tolerance of the catalog, nil if it has none
+func (r *catalogResolver) Tolerance() *toleranceResolver {
+	if r.catalog.Tolerance == nil {
+		return nil
+	}
+
+	return &toleranceResolver{tolerance: *r.catalog.Tolerance}
+}
+
+// toleranceResolver resolves an overshipment tolerance
+type toleranceResolver struct {
+	tolerance model.OvershipmentTolerance
+}
+
+func (r *toleranceResolver) MaxItems() long      { return long(r.tolerance.MaxItems) }
+func (r *toleranceResolver) MaxPercent() float64 { return r.tolerance.MaxPercent }
+func (r *toleranceResolver) Reject() bool        { return r.tolerance.Reject }
+
+// packCountResolver resolves the number of packs of a size
+type packCountResolver struct {
+	size  model.PackSize
+	count int64
+}
+
+// packCountResolvers resolves a map of pack size to count as pack counts sorted by size
+func packCountResolvers(packs map[model.PackSize]int64) []*packCountResolver {
+	result := make([]*packCountResolver, 0, len(packs))
+	for size, count := range packs {
+		result = append(result, &packCountResolver{size: size, count: count})
+	}
+	slices.SortFunc(result, func(a, b *packCountResolver) int {
+		return cmp.Compare(a.size, b.size)
+	})
+
+	return result
+}
+
+func (r *packCountResolver) Size() long  { return long(r.size) }
+func (r *packCountResolver) Count() long { return long(r.count) }
+
+// calculationResolver resolves the result of a calculation
+type calculationResolver struct {
+	result model.CalculationResponse
+}
+
+func (r *calculationResolver) OrderSize() long             { return long(r.result.OrderSize) }
+func (r *calculationResolver) Packs() []*packCountResolver { return packCountResolvers(r.result.Packs) }
+func (r *calculationResolver) Backorder() long             { return long(r.result.Backorder) }
+func (r *calculationResolver) ToleranceExceeded() bool     { return r.result.ToleranceExceeded }
+
+// Lines returns the packing plans of the lines of a multi-line order
+func (r *calculationResolver) Lines() []*lineResolver {
+	result := make([]*lineResolver, 0, len(r.result.Lines))
+	for _, line := range r.result.Lines {
+		result = append(result, &lineResolver{line: line})
+	}
+
+	return result
+}
+
+// Totals returns the sums of the packing plans of the lines of a multi-line order, nil for a single-line order
+func (r *calculationResolver) Totals() *totalsResolver {
+	if r.result.Totals == nil {
+		return nil
+	}
+
+	return &totalsResolver{totals: *r.result.Totals}
+}
+
+// lineResolver resolves the packing plan of an order line
+type lineResolver struct {
+	line model.LineResult
+}
+
+func (r *lineResolver) Catalog() graphql.ID         { return graphql.ID(r.line.Catalog) }
+func (r *lineResolver) Quantity() long              { return long(r.line.Quantity) }
+func (r *lineResolver) Unit() string                { return string(cmp.Or(r.line.Unit, model.UnitItem)) }
+func (r *lineResolver) Amount() *decimal            { return optionalDecimal(r.line.Amount) }
+func (r *lineResolver) ShippedAmount() *decimal     { return optionalDecimal(r.line.ShippedAmount) }
+func (r *lineResolver) Packs() []*packCountResolver { return packCountResolvers(r.line.Packs) }
+func (r *lineResolver) ShippedItems() long          { return long(r.line.ShippedItems) }
+func (r *lineResolver) Overshipment() long          { return long(r.line.Overshipment) }
+func (r *lineResolver) Backorder() long             { return long(r.line.Backorder) }
+func (r *lineResolver) PackCount() long             { return long(r.line.PackCount) }
+func (r *lineResolver) Cost() long                  { return long(r.line.Cost) }
+func (r *lineResolver) ToleranceExceeded() bool     { return r.line.ToleranceExceeded }
+
+// totalsResolver resolves the sums of the packing plans of the lines of a multi-line order
+type totalsResolver struct {
+	totals model.ShipmentTotals
+}
+
+func (r *totalsResolver) ShippedItems() long { return long(r.totals.ShippedItems) }
+func (r *totalsResolver) Overshipment() long { return long(r.totals.Overshipment) }
+func (r *totalsResolver) Backorder() long    { return long(r.totals.Backorder) }
+func (r *totalsResolver) PackCount() long    { return long(r.totals.PackCount) }
+func (r *totalsResolver) Cost() long         { return long(r.totals.Cost) }
+
+// recordResolver resolves a calculation of the history
+type recordResolver struct {
+	record model.CalculationRecord
+}
+
+func (r *recordResolver) CalculatedAt() graphql.Time {
+	return graphql.Time{Time: r.record.CalculatedAt}
+}
+
+// Result returns the result of the calculation
+func (r *recordResolver) Result() *calculationResolver {
+	return &calculationResolver{result: r.record.CalculationResponse}
+}
+
+// Ensure the scalars are encoded as JSON numbers
+var (
+	_ json.Marshaler = long(0)
+	_ json.Marshaler = decimal(0)
+)
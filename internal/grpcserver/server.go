@@ -0,0 +1,346 @@
+package grpcserver
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+
+	packsv1 "github.com/alishercodecrafter/orderpackscalculator/api/packs/v1"
+	"github.com/alishercodecrafter/orderpackscalculator/internal/model"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// PacksService defines the operations of the pack calculation service the gRPC API serves
+type PacksService interface {
+	// GetPacks returns all available packs
+	GetPacks() model.Packs
+	// AddPack adds a new pack
+	AddPack(pack model.Pack) error
+	// RemovePack removes a pack by its size
+	RemovePack(packSize model.PackSize) error
+	// ReplacePacks replaces all packs
+	ReplacePacks(packs model.Packs) error
+	// Calculate calculates the optimal number of packs needed for a single-line or a multi-line order
+	Calculate(ctx context.Context, req model.CalculationRequest) (model.CalculationResponse, error)
+}
+
+// Server implements the packs.v1.PacksService gRPC service over the pack calculation service
+type Server struct {
+	packsv1.UnimplementedPacksServiceServer
+
+	service PacksService
+}
+
+// NewServer creates a new Server
+func NewServer(service PacksService) *Server {
+	return &Server{service: service}
+}
+
+// GetPacks returns all available packs
+func (s *Server) GetPacks(context.Context, *packsv1.GetPacksRequest) (*packsv1.GetPacksResponse, error) {
+	return &packsv1.GetPacksResponse{Packs: toProtoPacks(s.service.GetPacks())}, nil
+}
+
+// AddPack adds a new pack
+func (s *Server) AddPack(_ context.Context, req *packsv1.AddPackRequest) (*packsv1.AddPackResponse, error) {
+	if req.GetPack() == nil {
+		return nil, statusError(model.NewError(model.ErrorCodeInvalidRequest, "pack is required"))
+	}
+
+	pack, err := fromProtoPack(req.GetPack())
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	if err := s.service.AddPack(pack); err != nil {
+		return nil, statusError(err)
+	}
+
+	return &packsv1.AddPackResponse{}, nil
+}
+
+// RemovePack removes a pack by its size
+func (s *Server) RemovePack(_ context.Context, req *packsv1.RemovePackRequest) (*packsv1.RemovePackResponse, error) {
+	if req.GetSize() <= 0 {
+		return nil, statusError(model.NewError(model.ErrorCodeInvalidPackSize, "Invalid pack size"))
+	}
+
+	if err := s.service.RemovePack(model.PackSize(req.GetSize())); err != nil {
+		return nil, statusError(err)
+	}
+
+	return &packsv1.RemovePackResponse{}, nil
+}
+
+// ReplacePacks replaces all packs
+func (s *Server) ReplacePacks(_ context.Context, req *packsv1.ReplacePacksRequest) (*packsv1.ReplacePacksResponse, error) {
+	packs := make(model.Packs, 0, len(req.GetPacks()))
+	for _, p := range req.GetPacks() {
+		pack, err := fromProtoPack(p)
+		if err != nil {
+			return nil, statusError(err)
+		}
+		packs = append(packs, pack)
+	}
+
+	if err := s.service.ReplacePacks(packs); err != nil {
+		return nil, statusError(err)
+	}
+
+	return &packsv1.ReplacePacksResponse{}, nil
+}
+
+// Calculate calculates the optimal number of packs needed for a single-line or a multi-line order
+func (s *Server) Calculate(ctx context.Context, req *packsv1.CalculateRequest) (*packsv1.CalculateResponse, error) {
+	result, err := s.calculate(ctx, req)
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	return result, nil
+}
+
+// CalculateStream calculates the orders of the stream one by one and sends the result or the error of each of them.
+// The stream ends when the client closes its side or when the context of the stream is done.
+func (s *Server) CalculateStream(stream packsv1.PacksService_CalculateStreamServer) error {
+	ctx := stream.Context()
+
+	for {
+		req, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		resp := &packsv1.CalculateStreamResponse{Id: req.GetId()}
+
+		result, err := s.calculate(ctx, req.GetOrder())
+		switch {
+		case err != nil && ctx.Err() != nil:
+			// the stream is cancelled or its deadline has passed, no further orders can be answered
+			return statusError(err)
+		case err != nil:
+			resp.Result = &packsv1.CalculateStreamResponse_Error{Error: protoError(err)}
+		default:
+			resp.Result = &packsv1.CalculateStreamResponse_Response{Response: result}
+		}
+
+		if err := stream.Send(resp); err != nil {
+			return err
+		}
+	}
+}
+
+// calculate converts the order to a calculation request, calculates it and converts its result
+func (s *Server) calculate(ctx context.Context, order *packsv1.CalculateRequest) (*packsv1.CalculateResponse, error) {
+	if order == nil {
+		return nil, model.NewError(model.ErrorCodeInvalidRequest, "order is required")
+	}
+
+	req, err := fromProtoCalculateRequest(order)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := s.service.Calculate(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return toProtoCalculateResponse(result), nil
+}
+
+// statusError converts an error of the service to a gRPC status error carrying its error code as a detail
+func statusError(err error) error {
+	st := status.New(statusCode(err), err.Error())
+	if withDetails, detailsErr := st.WithDetails(protoError(err)); detailsErr == nil {
+		st = withDetails
+	}
+
+	return st.Err()
+}
+
+// statusCode returns the gRPC status code of an error of the service
+func statusCode(err error) codes.Code {
+	switch {
+	case errors.Is(err, model.ErrCalculationTimeout), errors.Is(err, context.DeadlineExceeded):
+		return codes.DeadlineExceeded
+	case errors.Is(err, context.Canceled):
+		return codes.Canceled
+	case errors.Is(err, model.ErrCalculationBudgetExceeded):
+		return codes.ResourceExhausted
+	}
+
+	switch model.ErrorCodeOf(err) {
+	case model.ErrorCodeToleranceExceeded:
+		return codes.FailedPrecondition
+	case model.ErrorCodePackExists:
+		return codes.AlreadyExists
+	case model.ErrorCodePackNotFound, model.ErrorCodeCatalogNotFound:
+		return codes.NotFound
+	case model.ErrorCodeCalculationCancelled:
+		return codes.Canceled
+	case "":
+		return codes.Internal
+	default:
+		return codes.InvalidArgument
+	}
+}
+
+// protoError converts an error of the service to its message
+func protoError(err error) *packsv1.Error {
+	return &packsv1.Error{Code: string(model.ErrorCodeOf(err)), Message: err.Error()}
+}
+
+// fromProtoPack converts a pack of the gRPC API to a pack of the service
+func fromProtoPack(p *packsv1.Pack) (model.Pack, error) {
+	content, err := parseQuantity(p.GetContent(), "pack content")
+	if err != nil {
+		return model.Pack{}, err
+	}
+
+	return model.Pack{
+		Size:     model.PackSize(p.GetSize()),
+		Cost:     p.GetCost(),
+		MinCount: p.GetMinCount(),
+		MaxCount: p.GetMaxCount(),
+		Weight:   p.GetWeight(),
+		Volume:   p.GetVolume(),
+		Content:  content,
+	}, nil
+}
+
+// toProtoPacks converts packs of the service to packs of the gRPC API
+func toProtoPacks(packs model.Packs) []*packsv1.Pack {
+	result := make([]*packsv1.Pack, 0, len(packs))
+	for _, pack := range packs {
+		result = append(result, &packsv1.Pack{
+			Size:     int64(pack.Size),
+			Cost:     pack.Cost,
+			MinCount: pack.MinCount,
+			MaxCount: pack.MaxCount,
+			Weight:   pack.Weight,
+			Volume:   pack.Volume,
+			Content:  formatQuantity(pack.Content),
+		})
+	}
+
+	return result
+}
+
+// fromProtoCalculateRequest converts an order of the gRPC API to a calculation request of the service
+func fromProtoCalculateRequest(order *packsv1.CalculateRequest) (model.CalculationRequest, error) {
+	req := model.CalculationRequest{OrderSize: order.GetOrderSize()}
+
+	switch order.GetMode() {
+	case packsv1.ShipmentMode_SHIPMENT_MODE_UNSPECIFIED:
+	case packsv1.ShipmentMode_SHIPMENT_MODE_OVERSHIP:
+		req.Mode = model.ShipmentModeOvership
+	case packsv1.ShipmentMode_SHIPMENT_MODE_UNDERSHIP:
+		req.Mode = model.ShipmentModeUndership
+	default:
+		return model.CalculationRequest{}, model.NewError(
+			model.ErrorCodeInvalidShipmentMode,
+			fmt.Sprintf("unknown shipment mode %d", order.GetMode()),
+		)
+	}
+
+	for _, line := range order.GetLines() {
+		amount, err := parseQuantity(line.GetAmount(), "order amount")
+		if err != nil {
+			return model.CalculationRequest{}, err
+		}
+
+		req.Lines = append(req.Lines, model.OrderLine{
+			Catalog:  line.GetCatalog(),
+			Quantity: line.GetQuantity(),
+			Amount:   amount,
+		})
+	}
+
+	return req, nil
+}
+
+// toProtoCalculateResponse converts a calculation result of the service to a response of the gRPC API
+func toProtoCalculateResponse(result model.CalculationResponse) *packsv1.CalculateResponse {
+	resp := &packsv1.CalculateResponse{
+		OrderSize:         result.OrderSize,
+		Packs:             toProtoPackCounts(result.Packs),
+		Backorder:         result.Backorder,
+		ToleranceExceeded: result.ToleranceExceeded,
+	}
+
+	for _, line := range result.Lines {
+		resp.Lines = append(resp.Lines, &packsv1.LineResult{
+			Catalog:           line.Catalog,
+			Quantity:          line.Quantity,
+			Unit:              string(cmp.Or(line.Unit, model.UnitItem)),
+			Amount:            formatQuantity(line.Amount),
+			ShippedAmount:     formatQuantity(line.ShippedAmount),
+			Packs:             toProtoPackCounts(line.Packs),
+			ShippedItems:      line.ShippedItems,
+			Overshipment:      line.Overshipment,
+			Backorder:         line.Backorder,
+			PackCount:         line.PackCount,
+			Cost:              line.Cost,
+			ToleranceExceeded: line.ToleranceExceeded,
+		})
+	}
+
+	if result.Totals != nil {
+		resp.Totals = &packsv1.ShipmentTotals{
+			ShippedItems: result.Totals.ShippedItems,
+			Overshipment: result.Totals.Overshipment,
+			Backorder:    result.Totals.Backorder,
+			PackCount:    result.Totals.PackCount,
+			Cost:         result.Totals.Cost,
+		}
+	}
+
+	return resp
+}
+
+// toProtoPackCounts converts a map of pack size to count to pack counts sorted by size
+func toProtoPackCounts(packs map[model.PackSize]int64) []*packsv1.PackCount {
+	sizes := make([]model.PackSize, 0, len(packs))
+	for size := range packs {
+		sizes = append(sizes, size)
+	}
+	slices.Sort(sizes)
+
+	result := make([]*packsv1.PackCount, 0, len(sizes))
+	for _, size := range sizes {
+		result = append(result, &packsv1.PackCount{Size: int64(size), Count: packs[size]})
+	}
+
+	return result
+}
+
+// parseQuantity parses an optional decimal amount
+func parseQuantity(s, name string) (model.Quantity, error) {
+	if s == "" {
+		return 0, nil
+	}
+
+	q, err := model.ParseQuantity(s)
+	if err != nil {
+		return 0, model.NewError(model.ErrorCodeInvalidRequest, fmt.Sprintf("invalid %s %q: %v", name, s, err))
+	}
+
+	return q, nil
+}
+
+// formatQuantity formats an amount as a decimal number, an empty string if it is zero
+func formatQuantity(q model.Quantity) string {
+	if q == 0 {
+		return ""
+	}
+
+	return q.String()
+}
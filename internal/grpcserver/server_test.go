@@ -0,0 +1,199 @@
+package grpcserver
+
+import (
+	"context"
+	"io"
+	"net"
+	"testing"
+
+	packsv1 "github.com/alishercodecrafter/orderpackscalculator/api/packs/v1"
+	"github.com/alishercodecrafter/orderpackscalculator/internal/model"
+	"github.com/alishercodecrafter/orderpackscalculator/internal/repository"
+	"github.com/alishercodecrafter/orderpackscalculator/internal/service"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// newTestClient serves the gRPC API of a service with the default packs and the given limits over an in-memory
+// connection and returns a client of it
+func newTestClient(t *testing.T, limits model.Limits) packsv1.PacksServiceClient {
+	t.Helper()
+
+	listener := bufconn.Listen(1 << 20)
+	server := grpc.NewServer()
+	svc := service.NewPacksService(repository.NewMemoryRepository(), service.WithLimits(limits))
+	packsv1.RegisterPacksServiceServer(server, NewServer(svc))
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient(
+		"passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return packsv1.NewPacksServiceClient(conn)
+}
+
+// requireStatus checks the status code of err and the error code of its detail
+func requireStatus(t *testing.T, err error, code codes.Code, errorCode model.ErrorCode) {
+	t.Helper()
+
+	st, ok := status.FromError(err)
+	require.True(t, ok, "not a status error: %v", err)
+	require.Equal(t, code, st.Code())
+	require.Len(t, st.Details(), 1)
+	require.Equal(t, string(errorCode), st.Details()[0].(*packsv1.Error).GetCode())
+}
+
+// packSizes returns the sizes of packs
+func packSizes(packs []*packsv1.Pack) []int64 {
+	sizes := make([]int64, 0, len(packs))
+	for _, pack := range packs {
+		sizes = append(sizes, pack.GetSize())
+	}
+
+	return sizes
+}
+
+func TestServer_Packs(t *testing.T) {
+	ctx := context.Background()
+	client := newTestClient(t, model.Limits{})
+
+	resp, err := client.GetPacks(ctx, &packsv1.GetPacksRequest{})
+	require.NoError(t, err)
+	require.Equal(t, []int64{250, 500, 1000, 2000, 5000}, packSizes(resp.GetPacks()))
+
+	_, err = client.AddPack(ctx, &packsv1.AddPackRequest{Pack: &packsv1.Pack{Size: 750, Cost: 120}})
+	require.NoError(t, err)
+
+	_, err = client.AddPack(ctx, &packsv1.AddPackRequest{Pack: &packsv1.Pack{Size: 750}})
+	requireStatus(t, err, codes.AlreadyExists, model.ErrorCodePackExists)
+
+	_, err = client.RemovePack(ctx, &packsv1.RemovePackRequest{Size: 5000})
+	require.NoError(t, err)
+
+	_, err = client.RemovePack(ctx, &packsv1.RemovePackRequest{Size: 42})
+	requireStatus(t, err, codes.NotFound, model.ErrorCodePackNotFound)
+
+	resp, err = client.GetPacks(ctx, &packsv1.GetPacksRequest{})
+	require.NoError(t, err)
+	require.Equal(t, []int64{250, 500, 750, 1000, 2000}, packSizes(resp.GetPacks()))
+	require.Equal(t, int64(120), resp.GetPacks()[2].GetCost())
+
+	_, err = client.ReplacePacks(ctx, &packsv1.ReplacePacksRequest{
+		Packs: []*packsv1.Pack{{Size: 23}, {Size: 31}, {Size: 53}},
+	})
+	require.NoError(t, err)
+
+	resp, err = client.GetPacks(ctx, &packsv1.GetPacksRequest{})
+	require.NoError(t, err)
+	require.Equal(t, []int64{23, 31, 53}, packSizes(resp.GetPacks()))
+
+	_, err = client.ReplacePacks(ctx, &packsv1.ReplacePacksRequest{Packs: []*packsv1.Pack{{Size: 10, Content: "x"}}})
+	requireStatus(t, err, codes.InvalidArgument, model.ErrorCodeInvalidRequest)
+}
+
+func TestServer_Calculate(t *testing.T) {
+	ctx := context.Background()
+	client := newTestClient(t, model.Limits{MaxOrderSize: 100000})
+
+	resp, err := client.Calculate(ctx, &packsv1.CalculateRequest{OrderSize: 12001})
+	require.NoError(t, err)
+	require.Equal(t, int64(12001), resp.GetOrderSize())
+	require.Equal(t, []*packsv1.PackCount{{Size: 250, Count: 1}, {Size: 2000, Count: 1}, {Size: 5000, Count: 2}},
+		stripPackCounts(resp.GetPacks()))
+
+	resp, err = client.Calculate(ctx, &packsv1.CalculateRequest{
+		OrderSize: 251,
+		Mode:      packsv1.ShipmentMode_SHIPMENT_MODE_UNDERSHIP,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), resp.GetBackorder())
+
+	resp, err = client.Calculate(ctx, &packsv1.CalculateRequest{
+		Lines: []*packsv1.OrderLine{{Quantity: 501}, {Quantity: 250}},
+	})
+	require.NoError(t, err)
+	require.Len(t, resp.GetLines(), 2)
+	require.Equal(t, "item", resp.GetLines()[0].GetUnit())
+	require.Equal(t, int64(750), resp.GetLines()[0].GetShippedItems())
+	require.Equal(t, int64(1000), resp.GetTotals().GetShippedItems())
+
+	_, err = client.Calculate(ctx, &packsv1.CalculateRequest{OrderSize: 100001})
+	requireStatus(t, err, codes.InvalidArgument, model.ErrorCodeOrderSizeTooLarge)
+
+	_, err = client.Calculate(ctx, &packsv1.CalculateRequest{OrderSize: 1, Mode: packsv1.ShipmentMode(7)})
+	requireStatus(t, err, codes.InvalidArgument, model.ErrorCodeInvalidShipmentMode)
+}
+
+func TestServer_CalculateStream(t *testing.T) {
+	client := newTestClient(t, model.Limits{MaxOrderSize: 100000})
+
+	stream, err := client.CalculateStream(context.Background())
+	require.NoError(t, err)
+
+	orders := []*packsv1.CalculateStreamRequest{
+		{Id: "a", Order: &packsv1.CalculateRequest{OrderSize: 1}},
+		{Id: "b", Order: &packsv1.CalculateRequest{OrderSize: 0}},
+		{Id: "c", Order: &packsv1.CalculateRequest{OrderSize: 12001}},
+		{Id: "d"},
+	}
+	for _, order := range orders {
+		require.NoError(t, stream.Send(order))
+	}
+	require.NoError(t, stream.CloseSend())
+
+	var responses []*packsv1.CalculateStreamResponse
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		responses = append(responses, resp)
+	}
+
+	require.Len(t, responses, 4)
+
+	require.Equal(t, "a", responses[0].GetId())
+	require.Equal(t, []*packsv1.PackCount{{Size: 250, Count: 1}}, stripPackCounts(responses[0].GetResponse().GetPacks()))
+
+	require.Equal(t, "b", responses[1].GetId())
+	require.Nil(t, responses[1].GetResponse())
+	require.Equal(t, string(model.ErrorCodeInvalidOrderSize), responses[1].GetError().GetCode())
+
+	require.Equal(t, "c", responses[2].GetId())
+	require.Equal(t, int64(12001), responses[2].GetResponse().GetOrderSize())
+
+	require.Equal(t, "d", responses[3].GetId())
+	require.Equal(t, string(model.ErrorCodeInvalidRequest), responses[3].GetError().GetCode())
+}
+
+func TestServer_CalculateCancelled(t *testing.T) {
+	client := newTestClient(t, model.Limits{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := client.Calculate(ctx, &packsv1.CalculateRequest{OrderSize: 12001})
+	require.Equal(t, codes.Canceled, status.Code(err))
+}
+
+// stripPackCounts copies pack counts without their internal state so that they can be compared
+func stripPackCounts(packs []*packsv1.PackCount) []*packsv1.PackCount {
+	result := make([]*packsv1.PackCount, 0, len(packs))
+	for _, pack := range packs {
+		result = append(result, &packsv1.PackCount{Size: pack.GetSize(), Count: pack.GetCount()})
+	}
+
+	return result
+}
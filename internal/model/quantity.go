@@ -0,0 +1,131 @@
+package model
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Unit represents the unit of measure of the packs and the orders of a product
+type Unit string
+
+const (
+	// UnitItem counts whole items, the unit of a catalog without one
+	UnitItem Unit = "item"
+	// UnitKilogram measures the weight of a product in kilograms
+	UnitKilogram Unit = "kg"
+	// UnitLitre measures the volume of a product in litres
+	UnitLitre Unit = "l"
+)
+
+// Measured reports whether amounts of the unit are fixed-point decimal quantities rather than item counts
+func (u Unit) Measured() bool {
+	return u == UnitKilogram || u == UnitLitre
+}
+
+const (
+	// QuantityDecimals is the number of decimal places of a quantity
+	QuantityDecimals = 3
+	// QuantityScale is the number of fixed-point steps in one unit of a quantity
+	QuantityScale = 1000
+)
+
+// Quantity represents a fixed-point decimal amount of a measured unit, e.g. 12.5 kg,
+// stored as an integer number of thousandths of the unit
+type Quantity int64
+
+// ParseQuantity parses a decimal number with at most QuantityDecimals decimal places, e.g. "12.5"
+func ParseQuantity(s string) (Quantity, error) {
+	whole, fraction, hasPoint := strings.Cut(s, ".")
+	negative := strings.HasPrefix(whole, "-")
+	whole = strings.TrimPrefix(whole, "-")
+
+	if !isDigits(whole) || (hasPoint && !isDigits(fraction)) || len(fraction) > QuantityDecimals {
+		return 0, fmt.Errorf("quantity %q must be a decimal number with at most %d decimal places", s, QuantityDecimals)
+	}
+
+	var steps int64
+	if fraction != "" {
+		// fraction has at most QuantityDecimals digits, so it cannot overflow
+		steps, _ = strconv.ParseInt(fraction+strings.Repeat("0", QuantityDecimals-len(fraction)), 10, 64)
+	}
+
+	units, err := strconv.ParseInt(whole, 10, 64)
+	if err != nil || units > (math.MaxInt64-steps)/QuantityScale {
+		return 0, fmt.Errorf("quantity %q is too large", s)
+	}
+
+	value := units*QuantityScale + steps
+	if negative {
+		value = -value
+	}
+
+	return Quantity(value), nil
+}
+
+// isDigits reports whether s is a non-empty string of decimal digits
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// String formats the quantity as a decimal number without trailing zeros, e.g. "12.5"
+func (q Quantity) String() string {
+	sign, value := "", uint64(q)
+	if q < 0 {
+		sign, value = "-", -value
+	}
+
+	whole, fraction := value/QuantityScale, value%QuantityScale
+	if fraction == 0 {
+		return sign + strconv.FormatUint(whole, 10)
+	}
+
+	return strings.TrimRight(fmt.Sprintf("%s%d.%0*d", sign, whole, QuantityDecimals, fraction), "0")
+}
+
+// MarshalJSON encodes the quantity as a JSON number
+func (q Quantity) MarshalJSON() ([]byte, error) {
+	return []byte(q.String()), nil
+}
+
+// UnmarshalJSON decodes the quantity from a JSON number without losing precision
+func (q *Quantity) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+
+	value, err := ParseQuantity(string(data))
+	if err != nil {
+		return err
+	}
+	*q = value
+
+	return nil
+}
+
+// MarshalText encodes the quantity as a decimal number, e.g. as a JSON object key
+func (q Quantity) MarshalText() ([]byte, error) {
+	return []byte(q.String()), nil
+}
+
+// UnmarshalText decodes the quantity from a decimal number, e.g. from a JSON object key
+func (q *Quantity) UnmarshalText(text []byte) error {
+	value, err := ParseQuantity(string(text))
+	if err != nil {
+		return err
+	}
+	*q = value
+
+	return nil
+}
@@ -0,0 +1,86 @@
+// Package netguard keeps the server from making requests on behalf of its users to addresses that are not public,
+// such as the server itself, its internal network or a cloud metadata endpoint.
+package netguard
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/netip"
+	"syscall"
+)
+
+// Resolver looks up the IP addresses of a host, *net.Resolver implements it
+type Resolver interface {
+	LookupNetIP(ctx context.Context, network, host string) ([]netip.Addr, error)
+}
+
+// Policy decides which addresses may be connected to. Loopback, private, link-local, unspecified and multicast
+// addresses, including the metadata endpoint 169.254.169.254, are refused unless they are in an allowed network.
+// The zero Policy allows no such network and resolves hosts with net.DefaultResolver.
+type Policy struct {
+	// Allowed are the networks that may be connected to even though they are not public
+	Allowed []netip.Prefix
+	// Resolver resolves the hosts, net.DefaultResolver if nil
+	Resolver Resolver
+}
+
+// Allows reports whether the address may be connected to
+func (p Policy) Allows(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	for _, prefix := range p.Allowed {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+
+	return addr.IsValid() &&
+		!addr.IsLoopback() &&
+		!addr.IsPrivate() &&
+		!addr.IsLinkLocalUnicast() &&
+		!addr.IsLinkLocalMulticast() &&
+		!addr.IsInterfaceLocalMulticast() &&
+		!addr.IsMulticast() &&
+		!addr.IsUnspecified()
+}
+
+// CheckHost resolves the host, an IP address or a name, and fails unless all its addresses may be connected to
+func (p Policy) CheckHost(ctx context.Context, host string) error {
+	addrs := []netip.Addr{}
+	if addr, err := netip.ParseAddr(host); err == nil {
+		addrs = append(addrs, addr)
+	} else {
+		resolver := p.Resolver
+		if resolver == nil {
+			resolver = net.DefaultResolver
+		}
+
+		if addrs, err = resolver.LookupNetIP(ctx, "ip", host); err != nil {
+			return fmt.Errorf("resolve %s: %w", host, err)
+		}
+	}
+
+	for _, addr := range addrs {
+		if !p.Allows(addr) {
+			return fmt.Errorf("%s resolves to the address %s that is not public", host, addr)
+		}
+	}
+
+	return nil
+}
+
+// Control is the net.Dialer Control function that fails a connection to an address the policy does not allow.
+// It runs after the host was resolved, for every address dialed, so that a host that resolves to another address
+// when it is dialed than when it was checked is refused too.
+func (p Policy) Control(_, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return err
+	}
+
+	if !p.Allows(addrPort.Addr()) {
+		return fmt.Errorf("address %s is not public", addrPort.Addr())
+	}
+
+	return nil
+}
@@ -0,0 +1,62 @@
+package netguard
+
+import (
+	"context"
+	"errors"
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// stubResolver resolves the hosts to their addresses, failing for unknown hosts
+type stubResolver map[string][]netip.Addr
+
+func (r stubResolver) LookupNetIP(_ context.Context, _, host string) ([]netip.Addr, error) {
+	addrs, ok := r[host]
+	if !ok {
+		return nil, errors.New("no such host")
+	}
+
+	return addrs, nil
+}
+
+func TestPolicy_Allows(t *testing.T) {
+	var policy Policy
+
+	for _, addr := range []string{"93.184.216.34", "2606:2800:220:1:248:1893:25c8:1946"} {
+		require.True(t, policy.Allows(netip.MustParseAddr(addr)), addr)
+	}
+
+	for _, addr := range []string{
+		"127.0.0.1", "::1", "10.1.2.3", "172.16.0.1", "192.168.1.1", "fd00::1",
+		"169.254.169.254", "fe80::1", "0.0.0.0", "::", "224.0.0.1", "::ffff:127.0.0.1",
+	} {
+		require.False(t, policy.Allows(netip.MustParseAddr(addr)), addr)
+	}
+
+	policy.Allowed = []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")}
+	require.True(t, policy.Allows(netip.MustParseAddr("10.1.2.3")))
+	require.False(t, policy.Allows(netip.MustParseAddr("192.168.1.1")))
+}
+
+func TestPolicy_CheckHost(t *testing.T) {
+	policy := Policy{Resolver: stubResolver{
+		"erp.example.com":      {netip.MustParseAddr("93.184.216.34")},
+		"internal.example.com": {netip.MustParseAddr("93.184.216.34"), netip.MustParseAddr("10.0.0.5")},
+	}}
+
+	require.NoError(t, policy.CheckHost(context.Background(), "erp.example.com"))
+	require.NoError(t, policy.CheckHost(context.Background(), "93.184.216.34"))
+	require.ErrorContains(t, policy.CheckHost(context.Background(), "internal.example.com"), "10.0.0.5")
+	require.ErrorContains(t, policy.CheckHost(context.Background(), "169.254.169.254"), "not public")
+	require.ErrorContains(t, policy.CheckHost(context.Background(), "unknown.example.com"), "no such host")
+}
+
+func TestPolicy_Control(t *testing.T) {
+	var policy Policy
+
+	require.NoError(t, policy.Control("tcp4", "93.184.216.34:443", nil))
+	require.ErrorContains(t, policy.Control("tcp4", "169.254.169.254:80", nil), "not public")
+	require.ErrorContains(t, policy.Control("tcp6", "[::1]:8080", nil), "not public")
+}
@@ -2,29 +2,81 @@ package repository
 
 import (
 	"fmt"
+	"slices"
 	"sort"
+	"sync"
 
 	"github.com/alishercodecrafter/orderpackscalculator/internal/model"
 	"github.com/alishercodecrafter/orderpackscalculator/internal/service"
 )
 
-// MemoryRepository implements service.PacksRepository using in-memory storage
+// maxCalculations is the number of most recent calculations kept in the history
+const maxCalculations = 10_000
+
+// maxDeadLetters is the number of most recent undelivered webhook events kept
+const maxDeadLetters = 1_000
+
+// MemoryRepository implements service.PacksRepository using in-memory storage.
+// The available packs are kept as an event log of their changes, optionally persisted in a directory.
 type MemoryRepository struct {
-	packs model.Packs
+	mu           sync.RWMutex
+	packs        *packLog
+	calculations []model.CalculationRecord
+	catalogs     map[string]model.Catalog
+	packaging    model.Packaging
+	warehouses   map[string]model.Warehouse
+	webhooks     map[string]model.Webhook
+	deadLetters  []model.DeadLetter
+}
+
+// defaultPacks returns the packs a new repository starts with
+func defaultPacks() model.Packs {
+	return model.Packs{
+		{Size: 250},
+		{Size: 500},
+		{Size: 1000},
+		{Size: 2000},
+		{Size: 5000},
+	}
 }
 
 // NewMemoryRepository creates a new MemoryRepository
 func NewMemoryRepository() *MemoryRepository {
-	// Initialize with default pack sizes
+	return newMemoryRepository(newPackLog(defaultPacks()))
+}
+
+// OpenMemoryRepository creates a MemoryRepository whose pack changes are persisted in the directory.
+// The packs are restored from the latest snapshot and the changes after it, a new directory starts with the
+// default packs. Only the packs are persisted.
+func OpenMemoryRepository(dir string) (*MemoryRepository, error) {
+	packs, err := openPackLog(dir, defaultPacks())
+	if err != nil {
+		return nil, fmt.Errorf("open packs log: %w", err)
+	}
+
+	return newMemoryRepository(packs), nil
+}
+
+// newMemoryRepository creates a new MemoryRepository of the packs
+func newMemoryRepository(packs *packLog) *MemoryRepository {
 	return &MemoryRepository{
-		packs: model.Packs{
-			{Size: 250},
-			{Size: 500},
-			{Size: 1000},
-			{Size: 2000},
-			{Size: 5000},
-		},
+		packs:      packs,
+		catalogs:   make(map[string]model.Catalog),
+		warehouses: make(map[string]model.Warehouse),
+		webhooks:   make(map[string]model.Webhook),
+	}
+}
+
+// Close closes the file of the persisted pack changes, if there is one
+func (r *MemoryRepository) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.packs.file == nil {
+		return nil
 	}
+
+	return r.packs.file.Close()
 }
 
 // Ensure MemoryRepository implements service.PacksRepository
@@ -32,40 +84,321 @@ var _ service.PacksRepository = (*MemoryRepository)(nil)
 
 // GetPacks returns all available packs
 func (r *MemoryRepository) GetPacks() model.Packs {
-	// Make a copy to prevent external modification
-	result := make(model.Packs, len(r.packs))
-	copy(result, r.packs)
-
-	// Sort by pack size
-	sort.Slice(result, func(i, j int) bool {
-		return result[i].Size < result[j].Size
-	})
+	r.mu.RLock()
+	defer r.mu.RUnlock()
 
-	return result
+	return sortedPacks(r.packs.current)
 }
 
 // AddPack adds a new pack
 func (r *MemoryRepository) AddPack(pack model.Pack) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
 	// Check if pack size already exists
-	for _, p := range r.packs {
+	for _, p := range r.packs.current {
 		if p.Size == pack.Size {
-			return fmt.Errorf("pack size %d already exists", pack.Size)
+			return model.NewError(model.ErrorCodePackExists, fmt.Sprintf("pack size %d already exists", pack.Size))
 		}
 	}
-	r.packs = append(r.packs, pack)
 
-	return nil
+	return r.packs.append(model.PackChange{Type: model.PacksEventAdded, Pack: &pack})
 }
 
 // RemovePack removes a pack by its size
 func (r *MemoryRepository) RemovePack(packSize model.PackSize) error {
-	for i, pack := range r.packs {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, pack := range r.packs.current {
 		if pack.Size == packSize {
-			// Remove the pack by replacing it with the last element and truncating
-			r.packs[i] = r.packs[len(r.packs)-1]
-			r.packs = r.packs[:len(r.packs)-1]
-			return nil
+			return r.packs.append(model.PackChange{Type: model.PacksEventRemoved, Size: packSize})
 		}
 	}
-	return fmt.Errorf("pack size %d not found", packSize)
+	return model.NewError(model.ErrorCodePackNotFound, fmt.Sprintf("pack size %d not found", packSize))
+}
+
+// ReplacePacks replaces all packs
+func (r *MemoryRepository) ReplacePacks(packs model.Packs) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Make a copy to prevent external modification
+	return r.packs.append(model.PackChange{Type: model.PacksEventReplaced, Packs: slices.Clone(packs)})
+}
+
+// GetPackChanges returns the changes of the available packs after the version, oldest first
+func (r *MemoryRepository) GetPackChanges(since int64) ([]model.PackChange, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.packs.since(since)
+}
+
+// GetPacksAt returns the available packs of a version, rebuilt from their changes
+func (r *MemoryRepository) GetPacksAt(version int64) (model.Packs, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	packs, err := r.packs.at(version)
+	if err != nil {
+		return nil, err
+	}
+
+	return sortedPacks(packs), nil
+}
+
+// sortedPacks returns a copy of the packs sorted by size
+func sortedPacks(packs model.Packs) model.Packs {
+	// Make a copy to prevent external modification
+	result := make(model.Packs, len(packs))
+	copy(result, packs)
+
+	// Sort by pack size
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Size < result[j].Size
+	})
+
+	return result
+}
+
+// AddCalculation records a calculation in the history, dropping the oldest one when the history is full
+func (r *MemoryRepository) AddCalculation(calculation model.CalculationRecord) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.calculations) == maxCalculations {
+		r.calculations = append(r.calculations[:0], r.calculations[1:]...)
+	}
+	r.calculations = append(r.calculations, calculation)
+
+	return nil
+}
+
+// GetCalculations returns the calculation history, oldest first
+func (r *MemoryRepository) GetCalculations() []model.CalculationRecord {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	// Make a copy to prevent external modification
+	result := make([]model.CalculationRecord, len(r.calculations))
+	copy(result, r.calculations)
+
+	return result
+}
+
+// GetCatalogs returns all catalogs sorted by ID
+func (r *MemoryRepository) GetCatalogs() []model.Catalog {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := make([]model.Catalog, 0, len(r.catalogs))
+	for _, catalog := range r.catalogs {
+		result = append(result, copyCatalog(catalog))
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].ID < result[j].ID
+	})
+
+	return result
+}
+
+// GetCatalog returns a catalog by its ID
+func (r *MemoryRepository) GetCatalog(id string) (model.Catalog, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	catalog, ok := r.catalogs[id]
+	if !ok {
+		return model.Catalog{}, model.NewError(model.ErrorCodeCatalogNotFound, fmt.Sprintf("catalog %q not found", id))
+	}
+
+	return copyCatalog(catalog), nil
+}
+
+// SaveCatalog adds a catalog or replaces the catalog with the same ID
+func (r *MemoryRepository) SaveCatalog(catalog model.Catalog) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.catalogs[catalog.ID] = copyCatalog(catalog)
+
+	return nil
+}
+
+// RemoveCatalog removes a catalog by its ID
+func (r *MemoryRepository) RemoveCatalog(id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.catalogs[id]; !ok {
+		return model.NewError(model.ErrorCodeCatalogNotFound, fmt.Sprintf("catalog %q not found", id))
+	}
+	delete(r.catalogs, id)
+
+	return nil
+}
+
+// GetPackaging returns the hierarchy of outer containers packs are shipped in
+func (r *MemoryRepository) GetPackaging() model.Packaging {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return copyPackaging(r.packaging)
+}
+
+// SavePackaging replaces the hierarchy of outer containers packs are shipped in
+func (r *MemoryRepository) SavePackaging(packaging model.Packaging) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.packaging = copyPackaging(packaging)
+
+	return nil
+}
+
+// GetWarehouses returns all warehouses sorted by ID
+func (r *MemoryRepository) GetWarehouses() []model.Warehouse {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := make([]model.Warehouse, 0, len(r.warehouses))
+	for _, warehouse := range r.warehouses {
+		result = append(result, copyWarehouse(warehouse))
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].ID < result[j].ID
+	})
+
+	return result
+}
+
+// SaveWarehouse adds a warehouse or replaces the warehouse with the same ID
+func (r *MemoryRepository) SaveWarehouse(warehouse model.Warehouse) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.warehouses[warehouse.ID] = copyWarehouse(warehouse)
+
+	return nil
+}
+
+// RemoveWarehouse removes a warehouse by its ID
+func (r *MemoryRepository) RemoveWarehouse(id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.warehouses[id]; !ok {
+		return model.NewError(model.ErrorCodeWarehouseNotFound, fmt.Sprintf("warehouse %q not found", id))
+	}
+	delete(r.warehouses, id)
+
+	return nil
+}
+
+// GetWebhooks returns all webhooks sorted by ID
+func (r *MemoryRepository) GetWebhooks() []model.Webhook {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := make([]model.Webhook, 0, len(r.webhooks))
+	for _, webhook := range r.webhooks {
+		result = append(result, copyWebhook(webhook))
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].ID < result[j].ID
+	})
+
+	return result
+}
+
+// SaveWebhook adds a webhook or replaces the webhook with the same ID
+func (r *MemoryRepository) SaveWebhook(webhook model.Webhook) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.webhooks[webhook.ID] = copyWebhook(webhook)
+
+	return nil
+}
+
+// RemoveWebhook removes a webhook by its ID
+func (r *MemoryRepository) RemoveWebhook(id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.webhooks[id]; !ok {
+		return model.NewError(model.ErrorCodeWebhookNotFound, fmt.Sprintf("webhook %q not found", id))
+	}
+	delete(r.webhooks, id)
+
+	return nil
+}
+
+// AddDeadLetter records an undelivered webhook event, dropping the oldest one when the record is full
+func (r *MemoryRepository) AddDeadLetter(deadLetter model.DeadLetter) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.deadLetters) == maxDeadLetters {
+		r.deadLetters = append(r.deadLetters[:0], r.deadLetters[1:]...)
+	}
+	r.deadLetters = append(r.deadLetters, deadLetter)
+
+	return nil
+}
+
+// GetDeadLetters returns the undelivered webhook events, oldest first
+func (r *MemoryRepository) GetDeadLetters() []model.DeadLetter {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := make([]model.DeadLetter, len(r.deadLetters))
+	copy(result, r.deadLetters)
+
+	return result
+}
+
+// copyWebhook returns a copy of the webhook
+func copyWebhook(webhook model.Webhook) model.Webhook {
+	webhook.Events = append([]model.WebhookEventType(nil), webhook.Events...)
+
+	return webhook
+}
+
+// copyWarehouse returns a copy of the warehouse with its stock sorted by pack size
+func copyWarehouse(warehouse model.Warehouse) model.Warehouse {
+	stock := make([]model.StockItem, len(warehouse.Stock))
+	copy(stock, warehouse.Stock)
+
+	sort.Slice(stock, func(i, j int) bool {
+		return stock[i].Size < stock[j].Size
+	})
+	warehouse.Stock = stock
+
+	return warehouse
+}
+
+// copyPackaging returns a copy of the packaging hierarchy
+func copyPackaging(packaging model.Packaging) model.Packaging {
+	levels := make([]model.ContainerLevel, len(packaging.Levels))
+	copy(levels, packaging.Levels)
+
+	return model.Packaging{Levels: levels}
+}
+
+// copyCatalog returns a copy of the catalog with its packs sorted by pack size
+func copyCatalog(catalog model.Catalog) model.Catalog {
+	packs := make(model.Packs, len(catalog.Packs))
+	copy(packs, catalog.Packs)
+
+	sort.Slice(packs, func(i, j int) bool {
+		return packs[i].Size < packs[j].Size
+	})
+	catalog.Packs = packs
+
+	return catalog
 }
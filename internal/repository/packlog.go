@@ -0,0 +1,444 @@
+package repository
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alishercodecrafter/orderpackscalculator/internal/model"
+)
+
+const (
+	// snapshotInterval is the number of changes between two snapshots of the packs
+	snapshotInterval = 100
+	// changesFile is the file of the changes after the latest snapshot of a persisted log, one JSON change per line
+	changesFile = "packs.jsonl"
+)
+
+// segmentFile returns the file the changes up to the version are archived in when its snapshot is taken
+func segmentFile(version int64) string {
+	return fmt.Sprintf("packs.%d.jsonl", version)
+}
+
+// snapshotFile returns the file of the snapshot of the version
+func snapshotFile(version int64) string {
+	return fmt.Sprintf("packs.%d.snapshot.json", version)
+}
+
+// packSnapshot is the packs at a version of the event log
+type packSnapshot struct {
+	Version int64       `json:"version"`
+	Packs   model.Packs `json:"packs"`
+}
+
+// packLog is the event log of the available packs. The packs of any version are rebuilt by replaying the changes
+// after the nearest snapshot before it, a snapshot is taken every snapshotInterval changes.
+//
+// A log that is not persisted keeps all changes and snapshots in memory. A persisted log appends every change to
+// changesFile before applying it. When it takes a snapshot it writes it to its own file and archives changesFile
+// as the segment of the snapshot, so that it keeps only the changes after the latest snapshot in memory and is
+// restored from that snapshot and the changes after it. Older changes and versions are read from the segments.
+type packLog struct {
+	// base is the snapshot the changes in memory follow, the empty packs before the first change if there is none
+	base packSnapshot
+	// changes are the changes after the base, the change of version v is changes[v-base.Version-1]
+	changes []model.PackChange
+	// snapshots are the snapshots of the changes in memory sorted by version, the first one is the base
+	snapshots []packSnapshot
+	// current are the packs of the latest version
+	current model.Packs
+	// dir is the directory of a persisted log, empty if the log is not persisted
+	dir  string
+	file *os.File
+	// size is the length of the complete changes in the file, where the next change is written
+	size int64
+	// segments are the sorted versions of the snapshots whose changes are archived in segments
+	segments []int64
+}
+
+// newPackLog creates an event log that is not persisted, starting with the initial packs
+func newPackLog(initial model.Packs) *packLog {
+	l := &packLog{snapshots: []packSnapshot{{}}}
+	// appending to a log that is not persisted cannot fail
+	_ = l.append(model.PackChange{Type: model.PacksEventReplaced, Packs: initial})
+
+	return l
+}
+
+// openPackLog opens the event log persisted in the directory, creating it with the initial packs if it is empty
+func openPackLog(dir string, initial model.Packs) (*packLog, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	l := &packLog{dir: dir}
+	for _, entry := range entries {
+		if version, ok := fileVersion(entry.Name(), ".snapshot.json"); ok && version > l.base.Version {
+			l.base.Version = version
+		}
+
+		if version, ok := fileVersion(entry.Name(), ".jsonl"); ok {
+			l.segments = append(l.segments, version)
+		}
+	}
+	slices.Sort(l.segments)
+
+	if l.base.Version > 0 {
+		if l.base, err = readSnapshot(filepath.Join(dir, snapshotFile(l.base.Version))); err != nil {
+			return nil, err
+		}
+	}
+	l.snapshots = []packSnapshot{l.base}
+
+	path := filepath.Join(dir, changesFile)
+	if l.changes, l.size, err = readChanges(path); err != nil {
+		return nil, err
+	}
+
+	if len(l.changes) > 0 && l.changes[0].Version <= l.base.Version {
+		// the log stopped after taking the latest snapshot and before archiving its changes
+		if last := l.changes[len(l.changes)-1].Version; last != l.base.Version {
+			return nil, fmt.Errorf("changes up to version %d of %s overlap snapshot %d", last, path, l.base.Version)
+		}
+
+		if err := os.Rename(path, filepath.Join(dir, segmentFile(l.base.Version))); err != nil {
+			return nil, err
+		}
+
+		if !slices.Contains(l.segments, l.base.Version) {
+			l.segments = append(l.segments, l.base.Version)
+		}
+		l.changes, l.size = nil, 0
+	}
+
+	if len(l.changes) > 0 && l.changes[0].Version != l.base.Version+1 {
+		return nil, fmt.Errorf(
+			"changes of %s start at version %d, not after snapshot %d",
+			path,
+			l.changes[0].Version,
+			l.base.Version,
+		)
+	}
+	l.current = l.replay(l.base, l.version())
+
+	if l.file, err = os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644); err != nil {
+		return nil, err
+	}
+
+	// drop the torn write of a change that was never applied, so that the next change starts on a line of its own
+	if err := l.file.Truncate(l.size); err != nil {
+		_ = l.file.Close()
+
+		return nil, err
+	}
+
+	if l.version() == 0 {
+		if err := l.append(model.PackChange{Type: model.PacksEventReplaced, Packs: initial}); err != nil {
+			_ = l.file.Close()
+
+			return nil, err
+		}
+	}
+
+	return l, nil
+}
+
+// version returns the version of the latest packs
+func (l *packLog) version() int64 {
+	return l.base.Version + int64(len(l.changes))
+}
+
+// append records the change as the next version and applies it to the packs.
+// It first takes a snapshot if snapshotInterval changes were made since the last one.
+func (l *packLog) append(change model.PackChange) error {
+	if l.version()-l.snapshots[len(l.snapshots)-1].Version >= snapshotInterval {
+		if err := l.snapshot(); err != nil {
+			return fmt.Errorf("snapshot packs version %d: %w", l.version(), err)
+		}
+	}
+
+	change.Version = l.version() + 1
+	if change.ChangedAt.IsZero() {
+		change.ChangedAt = time.Now()
+	}
+
+	if l.file != nil {
+		line, err := json.Marshal(change)
+		if err != nil {
+			return err
+		}
+
+		line = append(line, '\n')
+		if err := l.write(line); err != nil {
+			return err
+		}
+		l.size += int64(len(line))
+	}
+
+	l.changes = append(l.changes, change)
+	l.current = applyChange(l.current, change)
+
+	return nil
+}
+
+// snapshot takes a snapshot of the current packs. A persisted log writes it to its file and archives the changes
+// up to it, so that they are no longer kept in memory.
+func (l *packLog) snapshot() error {
+	snapshot := packSnapshot{Version: l.version(), Packs: l.current}
+	if l.dir == "" {
+		l.snapshots = append(l.snapshots, snapshot)
+
+		return nil
+	}
+
+	if err := writeSnapshot(filepath.Join(l.dir, snapshotFile(snapshot.Version)), snapshot); err != nil {
+		return err
+	}
+
+	path := filepath.Join(l.dir, changesFile)
+	segment := filepath.Join(l.dir, segmentFile(snapshot.Version))
+	if err := os.Rename(path, segment); err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		// keep appending to the changes after the previous snapshot
+		return errors.Join(err, os.Rename(segment, path))
+	}
+	_ = l.file.Close()
+
+	l.file, l.size = file, 0
+	l.segments = append(l.segments, snapshot.Version)
+	l.base, l.changes, l.snapshots = snapshot, nil, []packSnapshot{snapshot}
+
+	return nil
+}
+
+// write writes the line of a change to the file and syncs it. A line that is not written completely is
+// truncated away, so that the file ends with the last change that was applied.
+func (l *packLog) write(line []byte) error {
+	_, err := l.file.Write(line)
+	if err == nil {
+		err = l.file.Sync()
+	}
+
+	if err != nil {
+		if truncateErr := l.file.Truncate(l.size); truncateErr != nil {
+			return errors.Join(err, truncateErr)
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+// at returns the packs of the version, reading the segment of the version if it is before the base
+func (l *packLog) at(version int64) (model.Packs, error) {
+	if version < 1 || version > l.version() {
+		return nil, model.NewError(
+			model.ErrorCodePacksVersionNotFound,
+			fmt.Sprintf("packs version must be between 1 and %d", l.version()),
+		)
+	}
+
+	if version < l.base.Version {
+		return l.archivedAt(version)
+	}
+
+	i := sort.Search(len(l.snapshots), func(i int) bool {
+		return l.snapshots[i].Version > version
+	})
+
+	return l.replay(l.snapshots[i-1], version), nil
+}
+
+// archivedAt returns the packs of a version before the base, replaying its segment after the previous snapshot
+func (l *packLog) archivedAt(version int64) (model.Packs, error) {
+	i := sort.Search(len(l.segments), func(i int) bool {
+		return l.segments[i] >= version
+	})
+	if i == len(l.segments) {
+		return nil, fmt.Errorf("no segment of the packs log holds version %d", version)
+	}
+
+	var snapshot packSnapshot
+	if i > 0 {
+		var err error
+		if snapshot, err = readSnapshot(filepath.Join(l.dir, snapshotFile(l.segments[i-1]))); err != nil {
+			return nil, err
+		}
+	}
+
+	changes, _, err := readChanges(filepath.Join(l.dir, segmentFile(l.segments[i])))
+	if err != nil {
+		return nil, err
+	}
+
+	packs := snapshot.Packs
+	for _, change := range changes {
+		if change.Version > snapshot.Version && change.Version <= version {
+			packs = applyChange(packs, change)
+		}
+	}
+
+	return packs, nil
+}
+
+// since returns the changes after the version, oldest first, reading the segments of the changes before the base
+func (l *packLog) since(version int64) ([]model.PackChange, error) {
+	version = min(max(version, 0), l.version())
+
+	var result []model.PackChange
+	for _, segment := range l.segments {
+		if segment <= version || segment > l.base.Version {
+			continue
+		}
+
+		changes, _, err := readChanges(filepath.Join(l.dir, segmentFile(segment)))
+		if err != nil {
+			return nil, err
+		}
+
+		for _, change := range changes {
+			if change.Version > version {
+				result = append(result, change)
+			}
+		}
+	}
+
+	for _, change := range l.changes[max(version-l.base.Version, 0):] {
+		result = append(result, copyChange(change))
+	}
+
+	return result, nil
+}
+
+// replay applies the changes in memory after the snapshot up to the version to the packs of the snapshot
+func (l *packLog) replay(snapshot packSnapshot, version int64) model.Packs {
+	packs := snapshot.Packs
+	for _, change := range l.changes[snapshot.Version-l.base.Version : version-l.base.Version] {
+		packs = applyChange(packs, change)
+	}
+
+	return packs
+}
+
+// applyChange returns the packs after the change, the packs themselves are not modified
+func applyChange(packs model.Packs, change model.PackChange) model.Packs {
+	switch change.Type {
+	case model.PacksEventAdded:
+		return append(slices.Clone(packs), *change.Pack)
+	case model.PacksEventRemoved:
+		return slices.DeleteFunc(slices.Clone(packs), func(pack model.Pack) bool {
+			return pack.Size == change.Size
+		})
+	default:
+		return slices.Clone(change.Packs)
+	}
+}
+
+// copyChange returns a copy of the change
+func copyChange(change model.PackChange) model.PackChange {
+	if change.Pack != nil {
+		pack := *change.Pack
+		change.Pack = &pack
+	}
+	change.Packs = slices.Clone(change.Packs)
+
+	return change
+}
+
+// fileVersion returns the version in the name of a file of the log, packs.<version> followed by the suffix
+func fileVersion(name, suffix string) (int64, bool) {
+	name, ok := strings.CutPrefix(name, "packs.")
+	if !ok {
+		return 0, false
+	}
+
+	if name, ok = strings.CutSuffix(name, suffix); !ok {
+		return 0, false
+	}
+
+	version, err := strconv.ParseInt(name, 10, 64)
+
+	return version, err == nil && version > 0
+}
+
+// readChanges reads the changes of the log file, none if it does not exist, and the length of its complete lines.
+// A last line without a newline is the torn write of a change that was never applied, it is not read.
+func readChanges(path string) ([]model.PackChange, int64, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, 0, nil
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+
+	size := bytes.LastIndexByte(data, '\n') + 1
+
+	var changes []model.PackChange
+	for line := range bytes.Lines(data[:size]) {
+		var change model.PackChange
+		if err := json.Unmarshal(line, &change); err != nil {
+			return nil, 0, fmt.Errorf("change %d of %s: %w", len(changes)+1, path, err)
+		}
+
+		if len(changes) > 0 && change.Version != changes[len(changes)-1].Version+1 {
+			return nil, 0, fmt.Errorf("change %d of %s has version %d", len(changes)+1, path, change.Version)
+		}
+
+		if change.Type == model.PacksEventAdded && change.Pack == nil {
+			return nil, 0, fmt.Errorf("change %d of %s adds no pack", len(changes)+1, path)
+		}
+		changes = append(changes, change)
+	}
+
+	return changes, int64(size), nil
+}
+
+// readSnapshot reads the snapshot file
+func readSnapshot(path string) (packSnapshot, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return packSnapshot{}, err
+	}
+
+	var snapshot packSnapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return packSnapshot{}, fmt.Errorf("snapshot %s: %w", path, err)
+	}
+
+	return snapshot, nil
+}
+
+// writeSnapshot writes the snapshot file, writing the snapshot to a temporary file first
+// so that a crash never leaves a partial snapshot behind
+func writeSnapshot(path string, snapshot packSnapshot) error {
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return err
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, path)
+}
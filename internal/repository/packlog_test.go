@@ -0,0 +1,217 @@
+package repository
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/alishercodecrafter/orderpackscalculator/internal/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryRepository_PackChanges(t *testing.T) {
+	repo := NewMemoryRepository()
+
+	require.NoError(t, repo.AddPack(model.Pack{Size: 750, Cost: 12}))
+	require.NoError(t, repo.RemovePack(5000))
+	require.Equal(t, model.ErrorCodePackNotFound, model.ErrorCodeOf(repo.RemovePack(42)))
+	require.NoError(t, repo.ReplacePacks(model.Packs{{Size: 53}, {Size: 23}}))
+
+	changes, err := repo.GetPackChanges(0)
+	require.NoError(t, err)
+	require.Len(t, changes, 4)
+	require.Equal(t, model.PacksEventReplaced, changes[0].Type)
+	require.Equal(t, defaultPacks(), changes[0].Packs)
+	require.Equal(t, model.PackChange{
+		Version:   2,
+		Type:      model.PacksEventAdded,
+		Pack:      &model.Pack{Size: 750, Cost: 12},
+		ChangedAt: changes[1].ChangedAt,
+	}, changes[1])
+	require.Equal(t, model.PackSize(5000), changes[2].Size)
+	require.Equal(t, int64(4), changes[3].Version)
+
+	since, err := repo.GetPackChanges(2)
+	require.NoError(t, err)
+	require.Equal(t, changes[2:], since)
+
+	since, err = repo.GetPackChanges(4)
+	require.NoError(t, err)
+	require.Empty(t, since)
+
+	// Test time travel to every version
+	packs, err := repo.GetPacksAt(1)
+	require.NoError(t, err)
+	require.Equal(t, defaultPacks(), packs)
+
+	packs, err = repo.GetPacksAt(3)
+	require.NoError(t, err)
+	require.Equal(t, model.Packs{{Size: 250}, {Size: 500}, {Size: 750, Cost: 12}, {Size: 1000}, {Size: 2000}}, packs)
+
+	packs, err = repo.GetPacksAt(4)
+	require.NoError(t, err)
+	require.Equal(t, repo.GetPacks(), packs)
+	require.Equal(t, model.Packs{{Size: 23}, {Size: 53}}, packs)
+
+	for _, version := range []int64{0, 5} {
+		_, err = repo.GetPacksAt(version)
+		require.Equal(t, model.ErrorCodePacksVersionNotFound, model.ErrorCodeOf(err))
+	}
+}
+
+func TestMemoryRepository_PackSnapshots(t *testing.T) {
+	repo := NewMemoryRepository()
+
+	for i := range 2*snapshotInterval + 10 {
+		require.NoError(t, repo.ReplacePacks(model.Packs{{Size: model.PackSize(i + 1)}}))
+	}
+	require.Len(t, repo.packs.snapshots, 3)
+	require.Equal(t, int64(2*snapshotInterval), repo.packs.snapshots[2].Version)
+
+	// the packs of version v are replaced with the pack of size v-1
+	for _, version := range []int64{2, snapshotInterval - 1, snapshotInterval, snapshotInterval + 1, 2*snapshotInterval + 11} {
+		packs, err := repo.GetPacksAt(version)
+		require.NoError(t, err)
+		require.Equal(t, model.Packs{{Size: model.PackSize(version - 1)}}, packs, version)
+	}
+}
+
+func TestOpenMemoryRepository(t *testing.T) {
+	dir := t.TempDir()
+
+	repo, err := OpenMemoryRepository(dir)
+	require.NoError(t, err)
+	require.Equal(t, defaultPacks(), repo.GetPacks())
+
+	require.NoError(t, repo.AddPack(model.Pack{Size: 750}))
+	for i := range snapshotInterval {
+		require.NoError(t, repo.ReplacePacks(model.Packs{{Size: model.PackSize(i + 1)}}))
+	}
+	require.NoError(t, repo.RemovePack(snapshotInterval))
+	require.NoError(t, repo.AddPack(model.Pack{Size: 42, Cost: 7}))
+	want := repo.GetPacks()
+	wantChanges, err := repo.GetPackChanges(0)
+	require.NoError(t, err)
+	require.Len(t, wantChanges, snapshotInterval+4)
+	require.NoError(t, repo.Close())
+
+	// Test that the changes up to the snapshot are archived
+	for _, name := range []string{snapshotFile(snapshotInterval), segmentFile(snapshotInterval)} {
+		_, err = os.Stat(filepath.Join(dir, name))
+		require.NoError(t, err)
+	}
+
+	// Test that only the snapshot and the changes after it are restored
+	repo, err = OpenMemoryRepository(dir)
+	require.NoError(t, err)
+	defer repo.Close()
+
+	require.Equal(t, want, repo.GetPacks())
+	require.Equal(t, int64(snapshotInterval), repo.packs.base.Version)
+	require.Len(t, repo.packs.changes, 4)
+
+	changes, err := repo.GetPackChanges(0)
+	require.NoError(t, err)
+	require.Len(t, changes, len(wantChanges))
+	for i, change := range changes {
+		require.True(t, change.ChangedAt.Equal(wantChanges[i].ChangedAt))
+		require.Equal(t, wantChanges[i].Version, change.Version)
+		require.Equal(t, wantChanges[i].Pack, change.Pack)
+	}
+
+	packs, err := repo.GetPacksAt(2)
+	require.NoError(t, err)
+	require.Equal(t, model.Packs{{Size: 250}, {Size: 500}, {Size: 750}, {Size: 1000}, {Size: 2000}, {Size: 5000}}, packs)
+
+	changes, err = repo.GetPackChanges(snapshotInterval - 1)
+	require.NoError(t, err)
+	require.Equal(t, int64(snapshotInterval), changes[0].Version)
+	require.Len(t, changes, 5)
+
+	// Test that new changes continue the log
+	require.NoError(t, repo.RemovePack(42))
+	changes, err = repo.GetPackChanges(int64(len(wantChanges)))
+	require.NoError(t, err)
+	require.Equal(t, int64(len(wantChanges)+1), changes[0].Version)
+}
+
+func TestOpenMemoryRepository_SnapshotNotArchived(t *testing.T) {
+	dir := t.TempDir()
+
+	repo, err := OpenMemoryRepository(dir)
+	require.NoError(t, err)
+	for i := range snapshotInterval - 1 {
+		require.NoError(t, repo.ReplacePacks(model.Packs{{Size: model.PackSize(i + 1)}}))
+	}
+	want := repo.GetPacks()
+	require.NoError(t, repo.Close())
+
+	// a crash after writing the snapshot leaves its changes in the changes file
+	require.NoError(t, writeSnapshot(
+		filepath.Join(dir, snapshotFile(snapshotInterval)),
+		packSnapshot{Version: snapshotInterval, Packs: want},
+	))
+
+	repo, err = OpenMemoryRepository(dir)
+	require.NoError(t, err)
+	defer repo.Close()
+
+	require.Equal(t, want, repo.GetPacks())
+	require.Empty(t, repo.packs.changes)
+	_, err = os.Stat(filepath.Join(dir, segmentFile(snapshotInterval)))
+	require.NoError(t, err)
+
+	changes, err := repo.GetPackChanges(0)
+	require.NoError(t, err)
+	require.Len(t, changes, snapshotInterval)
+}
+
+func TestOpenMemoryRepository_TornWrite(t *testing.T) {
+	dir := t.TempDir()
+
+	repo, err := OpenMemoryRepository(dir)
+	require.NoError(t, err)
+	require.NoError(t, repo.AddPack(model.Pack{Size: 750}))
+	require.NoError(t, repo.Close())
+
+	// a crash in the middle of writing the third change leaves a partial line behind
+	path := filepath.Join(dir, changesFile)
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0o644)
+	require.NoError(t, err)
+	_, err = file.WriteString(`{"version":3,"type":"rem`)
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+
+	repo, err = OpenMemoryRepository(dir)
+	require.NoError(t, err)
+	changes, err := repo.GetPackChanges(0)
+	require.NoError(t, err)
+	require.Len(t, changes, 2)
+	require.NoError(t, repo.RemovePack(250))
+	require.NoError(t, repo.Close())
+
+	repo, err = OpenMemoryRepository(dir)
+	require.NoError(t, err)
+	defer repo.Close()
+
+	changes, err = repo.GetPackChanges(0)
+	require.NoError(t, err)
+	require.Len(t, changes, 3)
+	require.Equal(t, model.PackSize(250), changes[2].Size)
+	require.Equal(t, model.Packs{{Size: 500}, {Size: 750}, {Size: 1000}, {Size: 2000}, {Size: 5000}}, repo.GetPacks())
+}
+
+func TestOpenMemoryRepository_Corrupted(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, changesFile), []byte(`{"version":2,"type":"removed","size":250}`+"\n"), 0o644))
+
+	_, err := OpenMemoryRepository(dir)
+	require.ErrorContains(t, err, "start at version 2")
+
+	dir = t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, changesFile), []byte(`{"version":1,"type":"replaced"}`+"\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, snapshotFile(3)), []byte(`{"version":3,"packs":[]}`), 0o644))
+
+	_, err = OpenMemoryRepository(dir)
+	require.ErrorContains(t, err, "overlap snapshot 3")
+}
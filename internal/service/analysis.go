@@ -0,0 +1,192 @@
+package service
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/alishercodecrafter/orderpackscalculator/internal/model"
+	"github.com/alishercodecrafter/orderpackscalculator/pkg/packing"
+)
+
+// defaultWorstOrdersLimit is the number of orders with the worst overshipment reported by default
+const defaultWorstOrdersLimit = 10
+
+// AnalyzePacks reports how well a pack set covers a range of order sizes: the overshipment of the orders,
+// the largest order that cannot be matched exactly, the distribution of pack counts and the worst orders.
+// The orders the packs cannot hold within their maximum counts are counted apart from the others.
+func (s *PacksServiceImpl) AnalyzePacks(
+	ctx context.Context,
+	req model.AnalysisRequest,
+) (model.AnalysisResponse, error) {
+	packs := req.Packs
+	if len(packs) == 0 {
+		packs = s.repo.GetPacks()
+	}
+
+	packList, err := sortPacks(packs)
+	if err != nil {
+		return model.AnalysisResponse{}, err
+	}
+
+	if req.MinOrderSize <= 0 || req.MaxOrderSize < req.MinOrderSize {
+		return model.AnalysisResponse{}, model.NewError(
+			model.ErrorCodeInvalidOrderRange,
+			"order size range must be positive and not empty",
+		)
+	}
+
+	rangeSize := req.MaxOrderSize - req.MinOrderSize + 1
+	if s.limits.MaxAnalysisRange > 0 && (rangeSize <= 0 || rangeSize > s.limits.MaxAnalysisRange) {
+		return model.AnalysisResponse{}, model.NewError(
+			model.ErrorCodeOrderRangeTooLarge,
+			fmt.Sprintf("order size range cannot span more than %d orders", s.limits.MaxAnalysisRange),
+		)
+	}
+
+	if err := s.checkLimits(req.MaxOrderSize, packList); err != nil {
+		return model.AnalysisResponse{}, err
+	}
+
+	worstOrdersLimit := req.WorstOrdersLimit
+	if worstOrdersLimit <= 0 {
+		worstOrdersLimit = defaultWorstOrdersLimit
+	}
+
+	calc, cancel := s.newCalculation(ctx)
+	defer cancel()
+
+	// the orders above the capacity of the packs cannot be shipped, the solver is prepared for the others
+	maxOrderSize := req.MaxOrderSize
+	capacity, limited := packsCapacity(packList)
+	if limited {
+		maxOrderSize = min(maxOrderSize, capacity)
+	}
+
+	solver, err := calc.newSolver(packList, maxOrderSize, model.ShipmentModeOvership)
+	if err != nil {
+		return model.AnalysisResponse{}, err
+	}
+
+	result := model.AnalysisResponse{
+		Packs:                 packList,
+		MinOrderSize:          req.MinOrderSize,
+		MaxOrderSize:          req.MaxOrderSize,
+		GreatestCommonDivisor: solver.Divisor(),
+		PackCountDistribution: make(map[int64]int64),
+		WorstOrders:           make([]model.OrderOvershipment, 0, worstOrdersLimit+1),
+	}
+
+	var totalOvershipment, totalOvershipmentPercent float64
+	for i := int64(0); i < rangeSize; i++ {
+		orderSize := req.MinOrderSize + i
+		if err := calc.step(int64(len(packList))); err != nil {
+			return model.AnalysisResponse{}, err
+		}
+
+		if orderSize > maxOrderSize {
+			result.InfeasibleOrders = req.MaxOrderSize - orderSize + 1
+			result.SmallestInfeasibleOrder = &orderSize
+
+			break
+		}
+
+		packs, err := solver.Solve(orderSize)
+		if err != nil {
+			return model.AnalysisResponse{}, solverError(err)
+		}
+
+		shippedItems, packCount, ok := getAmountOfItemsInPacks(packs)
+		if !ok {
+			return model.AnalysisResponse{}, model.ErrArithmeticOverflow
+		}
+
+		overshipment := shippedItems - orderSize
+		overshipmentPercent := float64(overshipment) * 100 / float64(orderSize)
+
+		totalOvershipment += float64(overshipment)
+		totalOvershipmentPercent += overshipmentPercent
+		result.MaxOvershipment = max(result.MaxOvershipment, overshipment)
+		result.MaxOvershipmentPercent = max(result.MaxOvershipmentPercent, overshipmentPercent)
+		result.PackCountDistribution[packCount]++
+		if overshipment == 0 {
+			result.ExactOrders++
+		}
+
+		result.WorstOrders = addWorstOrder(result.WorstOrders, worstOrdersLimit, model.OrderOvershipment{
+			OrderSize:    orderSize,
+			ShippedItems: shippedItems,
+			Overshipment: overshipment,
+			Packs:        packs,
+		})
+	}
+
+	if feasibleOrders := rangeSize - result.InfeasibleOrders; feasibleOrders > 0 {
+		result.AverageOvershipment = totalOvershipment / float64(feasibleOrders)
+		result.AverageOvershipmentPercent = totalOvershipmentPercent / float64(feasibleOrders)
+	}
+
+	largestUnmatchableOrder, ok, err := solver.LargestUnmatchableOrder()
+	if err != nil {
+		return model.AnalysisResponse{}, solverError(err)
+	}
+
+	if ok {
+		result.LargestUnmatchableOrder = &largestUnmatchableOrder
+	}
+
+	return result, nil
+}
+
+// packsCapacity returns the largest number of items the packs can hold within their maximum counts,
+// false if any pack has no maximum count or the capacity does not fit into int64
+func packsCapacity(packs model.Packs) (int64, bool) {
+	var capacity int64
+	for _, pack := range packs {
+		if pack.MaxCount == 0 {
+			return 0, false
+		}
+
+		items, ok := packing.CheckedMul(int64(pack.Size), pack.MaxCount)
+		if !ok {
+			return 0, false
+		}
+
+		if capacity, ok = packing.CheckedAdd(capacity, items); !ok {
+			return 0, false
+		}
+	}
+
+	return capacity, true
+}
+
+// addWorstOrder adds an order to the list of at most limit orders with the largest overshipment,
+// sorted by overshipment in descending order and then by order size
+func addWorstOrder(
+	worstOrders []model.OrderOvershipment,
+	limit int,
+	order model.OrderOvershipment,
+) []model.OrderOvershipment {
+	if order.Overshipment == 0 {
+		return worstOrders
+	}
+
+	if len(worstOrders) == limit && worstOrders[limit-1].Overshipment >= order.Overshipment {
+		return worstOrders
+	}
+
+	i, _ := slices.BinarySearchFunc(worstOrders, order, func(a, b model.OrderOvershipment) int {
+		if a.Overshipment != b.Overshipment {
+			return cmp.Compare(b.Overshipment, a.Overshipment)
+		}
+
+		return cmp.Compare(a.OrderSize, b.OrderSize)
+	})
+	worstOrders = slices.Insert(worstOrders, i, order)
+	if len(worstOrders) > limit {
+		worstOrders = worstOrders[:limit]
+	}
+
+	return worstOrders
+}
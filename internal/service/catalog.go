@@ -0,0 +1,74 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/alishercodecrafter/orderpackscalculator/internal/model"
+)
+
+// GetCatalogs returns all catalogs, starting with the default catalog of the available packs
+func (s *PacksServiceImpl) GetCatalogs() []model.Catalog {
+	return append([]model.Catalog{s.defaultCatalog()}, s.repo.GetCatalogs()...)
+}
+
+// GetCatalog returns a catalog by its ID, the default catalog of the available packs if the ID is empty
+func (s *PacksServiceImpl) GetCatalog(id string) (model.Catalog, error) {
+	if id == "" || id == model.DefaultCatalogID {
+		return s.defaultCatalog(), nil
+	}
+
+	return s.repo.GetCatalog(id)
+}
+
+// SaveCatalog adds a catalog or replaces the catalog with the same ID
+func (s *PacksServiceImpl) SaveCatalog(catalog model.Catalog) error {
+	if catalog.ID == "" || catalog.ID == model.DefaultCatalogID {
+		return model.NewError(
+			model.ErrorCodeInvalidCatalog,
+			fmt.Sprintf("catalog ID must not be empty or %q", model.DefaultCatalogID),
+		)
+	}
+
+	packs, err := unitPacks(catalog.Unit, catalog.Packs)
+	if err != nil {
+		return err
+	}
+	catalog.Packs = packs
+
+	if _, err := sortPacks(catalog.Packs); err != nil {
+		return err
+	}
+
+	if catalog.Tolerance != nil {
+		if err := validateTolerance(*catalog.Tolerance); err != nil {
+			return err
+		}
+	}
+
+	if s.limits.MaxPacks > 0 && len(catalog.Packs) > s.limits.MaxPacks {
+		return model.NewError(
+			model.ErrorCodeTooManyPacks,
+			fmt.Sprintf("number of packs cannot exceed %d", s.limits.MaxPacks),
+		)
+	}
+
+	return s.repo.SaveCatalog(catalog)
+}
+
+// RemoveCatalog removes a catalog by its ID
+func (s *PacksServiceImpl) RemoveCatalog(id string) error {
+	if id == model.DefaultCatalogID {
+		return model.NewError(model.ErrorCodeInvalidCatalog, "default catalog cannot be removed")
+	}
+
+	return s.repo.RemoveCatalog(id)
+}
+
+// defaultCatalog returns the catalog of the available packs
+func (s *PacksServiceImpl) defaultCatalog() model.Catalog {
+	return model.Catalog{
+		ID:    model.DefaultCatalogID,
+		Name:  "Default",
+		Packs: s.repo.GetPacks(),
+	}
+}
@@ -0,0 +1,170 @@
+package service
+
+import (
+	"context"
+	"maps"
+	"slices"
+
+	"github.com/alishercodecrafter/orderpackscalculator/internal/model"
+	"github.com/alishercodecrafter/orderpackscalculator/pkg/packing"
+)
+
+// ComparePacks calculates the orders with the available packs and with a candidate pack set
+// and reports the changes of each order and of the totals
+func (s *PacksServiceImpl) ComparePacks(
+	ctx context.Context,
+	req model.ComparisonRequest,
+) (model.ComparisonResponse, error) {
+	if len(req.OrderSizes) == 0 {
+		return model.ComparisonResponse{}, model.NewError(model.ErrorCodeNoOrders, "there are no orders to compare")
+	}
+
+	for _, orderSize := range req.OrderSizes {
+		if orderSize <= 0 {
+			return model.ComparisonResponse{}, model.NewError(
+				model.ErrorCodeInvalidOrderSize,
+				"order size must be greater than zero",
+			)
+		}
+	}
+
+	currentPacks, err := sortPacks(s.repo.GetPacks())
+	if err != nil {
+		return model.ComparisonResponse{}, err
+	}
+
+	candidatePacks, err := sortPacks(req.Packs)
+	if err != nil {
+		return model.ComparisonResponse{}, err
+	}
+
+	maxOrderSize := slices.Max(req.OrderSizes)
+	if err := s.checkLimits(maxOrderSize, currentPacks); err != nil {
+		return model.ComparisonResponse{}, err
+	}
+
+	if err := s.checkLimits(maxOrderSize, candidatePacks); err != nil {
+		return model.ComparisonResponse{}, err
+	}
+
+	calc, cancel := s.newCalculation(ctx)
+	defer cancel()
+
+	currentSolver, err := calc.newSolver(currentPacks, maxOrderSize, model.ShipmentModeOvership)
+	if err != nil {
+		return model.ComparisonResponse{}, err
+	}
+
+	candidateSolver, err := calc.newSolver(candidatePacks, maxOrderSize, model.ShipmentModeOvership)
+	if err != nil {
+		return model.ComparisonResponse{}, err
+	}
+
+	result := model.ComparisonResponse{
+		CurrentPacks:   currentPacks,
+		CandidatePacks: candidatePacks,
+		Orders:         make([]model.OrderComparison, 0, len(req.OrderSizes)),
+	}
+
+	for _, orderSize := range req.OrderSizes {
+		if err := calc.step(int64(len(currentPacks) + len(candidatePacks))); err != nil {
+			return model.ComparisonResponse{}, err
+		}
+
+		currentCombination, err := currentSolver.Solve(orderSize)
+		if err != nil {
+			return model.ComparisonResponse{}, solverError(err)
+		}
+
+		candidateCombination, err := candidateSolver.Solve(orderSize)
+		if err != nil {
+			return model.ComparisonResponse{}, solverError(err)
+		}
+
+		current, err := newShipment(orderSize, currentCombination, currentPacks)
+		if err != nil {
+			return model.ComparisonResponse{}, err
+		}
+
+		candidate, err := newShipment(orderSize, candidateCombination, candidatePacks)
+		if err != nil {
+			return model.ComparisonResponse{}, err
+		}
+
+		if err := addShipment(&result.Current, current); err != nil {
+			return model.ComparisonResponse{}, err
+		}
+
+		if err := addShipment(&result.Candidate, candidate); err != nil {
+			return model.ComparisonResponse{}, err
+		}
+
+		result.Orders = append(result.Orders, model.OrderComparison{
+			OrderSize: orderSize,
+			Current:   current,
+			Candidate: candidate,
+			Changed:   !maps.Equal(current.Packs, candidate.Packs),
+		})
+	}
+
+	result.ShippedItemsDelta = result.Candidate.ShippedItems - result.Current.ShippedItems
+	result.PackCountDelta = result.Candidate.PackCount - result.Current.PackCount
+	result.CostDelta = result.Candidate.Cost - result.Current.Cost
+
+	return result, nil
+}
+
+// newShipment describes the packs shipped for an order, taking the pack costs from the pack list
+func newShipment(orderSize int64, packs map[model.PackSize]int64, packList model.Packs) (model.Shipment, error) {
+	shippedItems, packCount, ok := getAmountOfItemsInPacks(packs)
+	if !ok {
+		return model.Shipment{}, model.ErrArithmeticOverflow
+	}
+
+	var cost int64
+	for _, pack := range packList {
+		packsCost, ok := packing.CheckedMul(pack.Cost, packs[pack.Size])
+		if !ok {
+			return model.Shipment{}, model.ErrArithmeticOverflow
+		}
+
+		if cost, ok = packing.CheckedAdd(cost, packsCost); !ok {
+			return model.Shipment{}, model.ErrArithmeticOverflow
+		}
+	}
+
+	return model.Shipment{
+		Packs:        packs,
+		ShippedItems: shippedItems,
+		Overshipment: max(shippedItems-orderSize, 0),
+		Backorder:    max(orderSize-shippedItems, 0),
+		PackCount:    packCount,
+		Cost:         cost,
+	}, nil
+}
+
+// addShipment adds a shipment to the totals
+func addShipment(totals *model.ShipmentTotals, shipment model.Shipment) error {
+	var ok bool
+	if totals.ShippedItems, ok = packing.CheckedAdd(totals.ShippedItems, shipment.ShippedItems); !ok {
+		return model.ErrArithmeticOverflow
+	}
+
+	if totals.Overshipment, ok = packing.CheckedAdd(totals.Overshipment, shipment.Overshipment); !ok {
+		return model.ErrArithmeticOverflow
+	}
+
+	if totals.Backorder, ok = packing.CheckedAdd(totals.Backorder, shipment.Backorder); !ok {
+		return model.ErrArithmeticOverflow
+	}
+
+	if totals.PackCount, ok = packing.CheckedAdd(totals.PackCount, shipment.PackCount); !ok {
+		return model.ErrArithmeticOverflow
+	}
+
+	if totals.Cost, ok = packing.CheckedAdd(totals.Cost, shipment.Cost); !ok {
+		return model.ErrArithmeticOverflow
+	}
+
+	return nil
+}
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"sync"
+
+	"github.com/alishercodecrafter/orderpackscalculator/internal/model"
+)
+
+// packsEventBuffer is the number of events a subscriber can fall behind before its oldest events are dropped.
+// Every event carries all available packs, so a subscriber that misses an event is still up to date with the next one.
+const packsEventBuffer = 16
+
+// packsEvents broadcasts the changes of the available packs to the subscribers, its zero value has none
+type packsEvents struct {
+	mu          sync.Mutex
+	subscribers map[chan model.PacksEvent]struct{}
+}
+
+// subscribe registers a subscriber, the returned function unregisters it and closes its channel
+func (e *packsEvents) subscribe() (<-chan model.PacksEvent, func()) {
+	ch := make(chan model.PacksEvent, packsEventBuffer)
+
+	e.mu.Lock()
+	if e.subscribers == nil {
+		e.subscribers = make(map[chan model.PacksEvent]struct{})
+	}
+	e.subscribers[ch] = struct{}{}
+	e.mu.Unlock()
+
+	var once sync.Once
+	unsubscribe := func() {
+		once.Do(func() {
+			e.mu.Lock()
+			delete(e.subscribers, ch)
+			e.mu.Unlock()
+			close(ch)
+		})
+	}
+
+	return ch, unsubscribe
+}
+
+// publish sends an event with the packs to all subscribers without waiting for them and passes the same event to
+// notify, if it is not nil. The packs are read once, only if there is someone to send the event to, under the lock,
+// so that the last event always carries the packs after the last change even if changes are published concurrently.
+func (e *packsEvents) publish(eventType model.PacksEventType, packs func() model.Packs, notify func(model.PacksEvent)) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if len(e.subscribers) == 0 && notify == nil {
+		return
+	}
+
+	event := model.PacksEvent{Type: eventType, Packs: packs()}
+	if notify != nil {
+		notify(event)
+	}
+
+	for ch := range e.subscribers {
+		select {
+		case ch <- event:
+		default:
+			// the subscriber is behind, drop its oldest event to make room for the newest one
+			select {
+			case <-ch:
+			default:
+			}
+			ch <- event
+		}
+	}
+}
+
+// SubscribePacks subscribes to the changes of the available packs.
+// Each change is sent with all available packs after it. A subscriber that falls behind misses the oldest
+// of the pending events. The returned function ends the subscription and closes the channel.
+func (s *PacksServiceImpl) SubscribePacks() (<-chan model.PacksEvent, func()) {
+	return s.packsEvents.subscribe()
+}
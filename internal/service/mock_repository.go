@@ -8,6 +8,7 @@ import (
 	reflect "reflect"
 
 	model "github.com/alishercodecrafter/orderpackscalculator/internal/model"
+	packing "github.com/alishercodecrafter/orderpackscalculator/pkg/packing"
 	gomock "github.com/golang/mock/gomock"
 )
 
@@ -34,6 +35,34 @@ func (m *MockPacksRepository) EXPECT() *MockPacksRepositoryMockRecorder {
 	return m.recorder
 }
 
+// AddCalculation mocks base method.
+func (m *MockPacksRepository) AddCalculation(arg0 model.CalculationRecord) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddCalculation", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddCalculation indicates an expected call of AddCalculation.
+func (mr *MockPacksRepositoryMockRecorder) AddCalculation(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddCalculation", reflect.TypeOf((*MockPacksRepository)(nil).AddCalculation), arg0)
+}
+
+// AddDeadLetter mocks base method.
+func (m *MockPacksRepository) AddDeadLetter(arg0 model.DeadLetter) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddDeadLetter", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddDeadLetter indicates an expected call of AddDeadLetter.
+func (mr *MockPacksRepositoryMockRecorder) AddDeadLetter(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddDeadLetter", reflect.TypeOf((*MockPacksRepository)(nil).AddDeadLetter), arg0)
+}
+
 // AddPack mocks base method.
 func (m *MockPacksRepository) AddPack(arg0 model.Pack) error {
 	m.ctrl.T.Helper()
@@ -48,6 +77,92 @@ func (mr *MockPacksRepositoryMockRecorder) AddPack(arg0 interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddPack", reflect.TypeOf((*MockPacksRepository)(nil).AddPack), arg0)
 }
 
+// GetCalculations mocks base method.
+func (m *MockPacksRepository) GetCalculations() []model.CalculationRecord {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCalculations")
+	ret0, _ := ret[0].([]model.CalculationRecord)
+	return ret0
+}
+
+// GetCalculations indicates an expected call of GetCalculations.
+func (mr *MockPacksRepositoryMockRecorder) GetCalculations() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCalculations", reflect.TypeOf((*MockPacksRepository)(nil).GetCalculations))
+}
+
+// GetCatalog mocks base method.
+func (m *MockPacksRepository) GetCatalog(arg0 string) (model.Catalog, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCatalog", arg0)
+	ret0, _ := ret[0].(model.Catalog)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCatalog indicates an expected call of GetCatalog.
+func (mr *MockPacksRepositoryMockRecorder) GetCatalog(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCatalog", reflect.TypeOf((*MockPacksRepository)(nil).GetCatalog), arg0)
+}
+
+// GetCatalogs mocks base method.
+func (m *MockPacksRepository) GetCatalogs() []model.Catalog {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCatalogs")
+	ret0, _ := ret[0].([]model.Catalog)
+	return ret0
+}
+
+// GetCatalogs indicates an expected call of GetCatalogs.
+func (mr *MockPacksRepositoryMockRecorder) GetCatalogs() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCatalogs", reflect.TypeOf((*MockPacksRepository)(nil).GetCatalogs))
+}
+
+// GetDeadLetters mocks base method.
+func (m *MockPacksRepository) GetDeadLetters() []model.DeadLetter {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeadLetters")
+	ret0, _ := ret[0].([]model.DeadLetter)
+	return ret0
+}
+
+// GetDeadLetters indicates an expected call of GetDeadLetters.
+func (mr *MockPacksRepositoryMockRecorder) GetDeadLetters() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeadLetters", reflect.TypeOf((*MockPacksRepository)(nil).GetDeadLetters))
+}
+
+// GetPackChanges mocks base method.
+func (m *MockPacksRepository) GetPackChanges(arg0 int64) ([]model.PackChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPackChanges", arg0)
+	ret0, _ := ret[0].([]model.PackChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPackChanges indicates an expected call of GetPackChanges.
+func (mr *MockPacksRepositoryMockRecorder) GetPackChanges(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPackChanges", reflect.TypeOf((*MockPacksRepository)(nil).GetPackChanges), arg0)
+}
+
+// GetPackaging mocks base method.
+func (m *MockPacksRepository) GetPackaging() model.Packaging {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPackaging")
+	ret0, _ := ret[0].(model.Packaging)
+	return ret0
+}
+
+// GetPackaging indicates an expected call of GetPackaging.
+func (mr *MockPacksRepositoryMockRecorder) GetPackaging() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPackaging", reflect.TypeOf((*MockPacksRepository)(nil).GetPackaging))
+}
+
 // GetPacks mocks base method.
 func (m *MockPacksRepository) GetPacks() model.Packs {
 	m.ctrl.T.Helper()
@@ -62,8 +177,65 @@ func (mr *MockPacksRepositoryMockRecorder) GetPacks() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPacks", reflect.TypeOf((*MockPacksRepository)(nil).GetPacks))
 }
 
+// GetPacksAt mocks base method.
+func (m *MockPacksRepository) GetPacksAt(arg0 int64) (model.Packs, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPacksAt", arg0)
+	ret0, _ := ret[0].(model.Packs)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPacksAt indicates an expected call of GetPacksAt.
+func (mr *MockPacksRepositoryMockRecorder) GetPacksAt(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPacksAt", reflect.TypeOf((*MockPacksRepository)(nil).GetPacksAt), arg0)
+}
+
+// GetWarehouses mocks base method.
+func (m *MockPacksRepository) GetWarehouses() []model.Warehouse {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWarehouses")
+	ret0, _ := ret[0].([]model.Warehouse)
+	return ret0
+}
+
+// GetWarehouses indicates an expected call of GetWarehouses.
+func (mr *MockPacksRepositoryMockRecorder) GetWarehouses() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWarehouses", reflect.TypeOf((*MockPacksRepository)(nil).GetWarehouses))
+}
+
+// GetWebhooks mocks base method.
+func (m *MockPacksRepository) GetWebhooks() []model.Webhook {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhooks")
+	ret0, _ := ret[0].([]model.Webhook)
+	return ret0
+}
+
+// GetWebhooks indicates an expected call of GetWebhooks.
+func (mr *MockPacksRepositoryMockRecorder) GetWebhooks() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhooks", reflect.TypeOf((*MockPacksRepository)(nil).GetWebhooks))
+}
+
+// RemoveCatalog mocks base method.
+func (m *MockPacksRepository) RemoveCatalog(arg0 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveCatalog", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveCatalog indicates an expected call of RemoveCatalog.
+func (mr *MockPacksRepositoryMockRecorder) RemoveCatalog(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveCatalog", reflect.TypeOf((*MockPacksRepository)(nil).RemoveCatalog), arg0)
+}
+
 // RemovePack mocks base method.
-func (m *MockPacksRepository) RemovePack(arg0 model.PackSize) error {
+func (m *MockPacksRepository) RemovePack(arg0 packing.Size) error {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "RemovePack", arg0)
 	ret0, _ := ret[0].(error)
@@ -75,3 +247,101 @@ func (mr *MockPacksRepositoryMockRecorder) RemovePack(arg0 interface{}) *gomock.
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemovePack", reflect.TypeOf((*MockPacksRepository)(nil).RemovePack), arg0)
 }
+
+// RemoveWarehouse mocks base method.
+func (m *MockPacksRepository) RemoveWarehouse(arg0 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveWarehouse", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveWarehouse indicates an expected call of RemoveWarehouse.
+func (mr *MockPacksRepositoryMockRecorder) RemoveWarehouse(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveWarehouse", reflect.TypeOf((*MockPacksRepository)(nil).RemoveWarehouse), arg0)
+}
+
+// RemoveWebhook mocks base method.
+func (m *MockPacksRepository) RemoveWebhook(arg0 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveWebhook", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveWebhook indicates an expected call of RemoveWebhook.
+func (mr *MockPacksRepositoryMockRecorder) RemoveWebhook(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveWebhook", reflect.TypeOf((*MockPacksRepository)(nil).RemoveWebhook), arg0)
+}
+
+// ReplacePacks mocks base method.
+func (m *MockPacksRepository) ReplacePacks(arg0 model.Packs) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplacePacks", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReplacePacks indicates an expected call of ReplacePacks.
+func (mr *MockPacksRepositoryMockRecorder) ReplacePacks(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplacePacks", reflect.TypeOf((*MockPacksRepository)(nil).ReplacePacks), arg0)
+}
+
+// SaveCatalog mocks base method.
+func (m *MockPacksRepository) SaveCatalog(arg0 model.Catalog) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveCatalog", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveCatalog indicates an expected call of SaveCatalog.
+func (mr *MockPacksRepositoryMockRecorder) SaveCatalog(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveCatalog", reflect.TypeOf((*MockPacksRepository)(nil).SaveCatalog), arg0)
+}
+
+// SavePackaging mocks base method.
+func (m *MockPacksRepository) SavePackaging(arg0 model.Packaging) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SavePackaging", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SavePackaging indicates an expected call of SavePackaging.
+func (mr *MockPacksRepositoryMockRecorder) SavePackaging(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SavePackaging", reflect.TypeOf((*MockPacksRepository)(nil).SavePackaging), arg0)
+}
+
+// SaveWarehouse mocks base method.
+func (m *MockPacksRepository) SaveWarehouse(arg0 model.Warehouse) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveWarehouse", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveWarehouse indicates an expected call of SaveWarehouse.
+func (mr *MockPacksRepositoryMockRecorder) SaveWarehouse(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveWarehouse", reflect.TypeOf((*MockPacksRepository)(nil).SaveWarehouse), arg0)
+}
+
+// SaveWebhook mocks base method.
+func (m *MockPacksRepository) SaveWebhook(arg0 model.Webhook) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveWebhook", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveWebhook indicates an expected call of SaveWebhook.
+func (mr *MockPacksRepositoryMockRecorder) SaveWebhook(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveWebhook", reflect.TypeOf((*MockPacksRepository)(nil).SaveWebhook), arg0)
+}
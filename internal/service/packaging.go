@@ -0,0 +1,168 @@
+package service
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+
+	"github.com/alishercodecrafter/orderpackscalculator/internal/model"
+	"github.com/alishercodecrafter/orderpackscalculator/pkg/packing"
+)
+
+// maxAlternatives is the largest number of equally good pack combinations compared by secondary criteria
+const maxAlternatives = 1000
+
+// GetPackaging returns the hierarchy of outer containers packs are shipped in
+func (s *PacksServiceImpl) GetPackaging() model.Packaging {
+	return s.repo.GetPackaging()
+}
+
+// SavePackaging replaces the hierarchy of outer containers packs are shipped in
+func (s *PacksServiceImpl) SavePackaging(packaging model.Packaging) error {
+	for _, level := range packaging.Levels {
+		if level.Name == "" || level.Capacity <= 0 {
+			return model.NewError(
+				model.ErrorCodeInvalidPackaging,
+				"container level must have a name and a capacity greater than zero",
+			)
+		}
+	}
+
+	return s.repo.SavePackaging(packaging)
+}
+
+// choosePackaging chooses the combination of packs among equally good alternatives that, together with
+// the packs already shipped, needs the fewest outer containers, and returns it with the packaging plan
+// of all the packs. Without a packaging hierarchy the first alternative is chosen.
+func choosePackaging(
+	packaging *model.Packaging,
+	shipped map[model.PackSize]int64,
+	alternatives []map[model.PackSize]int64,
+) (map[model.PackSize]int64, *model.PackagingPlan, error) {
+	if packaging == nil {
+		return alternatives[0], nil, nil
+	}
+
+	var best map[model.PackSize]int64
+	var bestPlan *model.PackagingPlan
+	var planErr error
+	for _, alternative := range alternatives {
+		packs, err := mergePacks(shipped, alternative)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		// an alternative with a pack that does not fit into a container is only reported if no other one fits
+		plan, err := planPackaging(*packaging, packs)
+		if err != nil {
+			planErr = cmp.Or(planErr, err)
+
+			continue
+		}
+
+		if bestPlan == nil || comparePackagingPlans(plan, bestPlan) < 0 {
+			best, bestPlan = alternative, plan
+		}
+	}
+
+	if bestPlan == nil {
+		return nil, nil, planErr
+	}
+
+	return best, bestPlan, nil
+}
+
+// planPackaging nests packs in the outer containers of a packaging hierarchy.
+// The containers of the first level are filled greedily with the largest packs that still fit,
+// every outer level is filled with as many inner containers as it holds.
+func planPackaging(packaging model.Packaging, packs map[model.PackSize]int64) (*model.PackagingPlan, error) {
+	first := packaging.Levels[0]
+	sizes := slices.Sorted(maps.Keys(packs))
+	slices.Reverse(sizes)
+
+	remaining := maps.Clone(packs)
+	level := model.ContainerPlan{Name: first.Name, Groups: []model.ContainerGroup{}}
+	for _, size := range sizes {
+		if remaining[size] > 0 && int64(size) > first.Capacity {
+			return nil, model.NewError(
+				model.ErrorCodePackDoesNotFitContainer,
+				fmt.Sprintf("pack size %d does not fit into %s of capacity %d", size, first.Name, first.Capacity),
+			)
+		}
+	}
+
+	for {
+		free := first.Capacity
+		contents := make(map[model.PackSize]int64)
+		for _, size := range sizes {
+			if count := min(remaining[size], free/int64(size)); count > 0 {
+				contents[size] = count
+				free -= count * int64(size)
+			}
+		}
+
+		if len(contents) == 0 {
+			break
+		}
+
+		// the same container is filled again as long as every pack size in it has enough packs left
+		repeat := int64(math.MaxInt64)
+		for size, count := range contents {
+			repeat = min(repeat, remaining[size]/count)
+		}
+
+		for size, count := range contents {
+			remaining[size] -= count * repeat
+		}
+
+		level.Count += repeat
+		level.Groups = append(level.Groups, model.ContainerGroup{Count: repeat, Packs: contents})
+	}
+
+	plan := &model.PackagingPlan{Levels: []model.ContainerPlan{level}}
+	inner := level.Count
+	for _, outer := range packaging.Levels[1:] {
+		full, rest := inner/outer.Capacity, inner%outer.Capacity
+		level := model.ContainerPlan{Name: outer.Name, Count: full, Groups: []model.ContainerGroup{}}
+		if full > 0 {
+			level.Groups = append(level.Groups, model.ContainerGroup{Count: full, Contents: outer.Capacity})
+		}
+
+		if rest > 0 {
+			level.Count++
+			level.Groups = append(level.Groups, model.ContainerGroup{Count: 1, Contents: rest})
+		}
+
+		plan.Levels = append(plan.Levels, level)
+		inner = level.Count
+	}
+
+	return plan, nil
+}
+
+// comparePackagingPlans orders packaging plans by the number of containers of the outermost level first
+func comparePackagingPlans(a, b *model.PackagingPlan) int {
+	for i := len(a.Levels) - 1; i >= 0; i-- {
+		if a.Levels[i].Count != b.Levels[i].Count {
+			return cmp.Compare(a.Levels[i].Count, b.Levels[i].Count)
+		}
+	}
+
+	return 0
+}
+
+// mergePacks returns the sum of two maps of pack size to count of packs
+func mergePacks(a, b map[model.PackSize]int64) (map[model.PackSize]int64, error) {
+	result := make(map[model.PackSize]int64, len(a)+len(b))
+	maps.Copy(result, a)
+	for size, count := range b {
+		var ok bool
+		if result[size], ok = packing.CheckedAdd(result[size], count); !ok {
+			return nil, model.ErrArithmeticOverflow
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,258 @@
+package service
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/alishercodecrafter/orderpackscalculator/internal/model"
+	"github.com/alishercodecrafter/orderpackscalculator/pkg/packing"
+)
+
+// maxRecommendationCandidates is the largest number of pack sizes the recommendation chooses from
+const maxRecommendationCandidates = 50
+
+// RecommendPacks proposes PackCount pack sizes that ship the orders with the least overshipment
+// and then the fewest packs, and compares them with the available packs.
+//
+// The pack sizes are chosen among the most frequent order sizes and the available pack sizes.
+// The set is built greedily one pack size at a time and then improved by swapping single pack sizes
+// for the other candidates until no swap improves it.
+func (s *PacksServiceImpl) RecommendPacks(
+	ctx context.Context,
+	req model.RecommendationRequest,
+) (model.RecommendationResponse, error) {
+	if req.PackCount <= 0 {
+		return model.RecommendationResponse{}, model.NewError(
+			model.ErrorCodeInvalidPackCount,
+			"number of pack sizes must be greater than zero",
+		)
+	}
+
+	if s.limits.MaxPacks > 0 && req.PackCount > s.limits.MaxPacks {
+		return model.RecommendationResponse{}, model.NewError(
+			model.ErrorCodeInvalidPackCount,
+			fmt.Sprintf("number of pack sizes must be between 1 and %d", s.limits.MaxPacks),
+		)
+	}
+
+	orderSizes := req.OrderSizes
+	if len(orderSizes) == 0 {
+		for _, calculation := range s.repo.GetCalculations() {
+			// the total quantity of a multi-line order mixes products with different packs
+			if len(calculation.Lines) == 0 {
+				orderSizes = append(orderSizes, calculation.OrderSize)
+			}
+		}
+	}
+
+	if len(orderSizes) == 0 {
+		return model.RecommendationResponse{}, model.NewError(model.ErrorCodeNoOrders, "there are no orders to recommend pack sizes for")
+	}
+
+	// orders maps an order size to the number of orders of that size
+	orders := make(map[int64]int64)
+	for _, orderSize := range orderSizes {
+		if orderSize <= 0 {
+			return model.RecommendationResponse{}, model.NewError(
+				model.ErrorCodeInvalidOrderSize,
+				"order size must be greater than zero",
+			)
+		}
+
+		if s.limits.MaxOrderSize > 0 && orderSize > s.limits.MaxOrderSize {
+			return model.RecommendationResponse{}, model.NewError(
+				model.ErrorCodeOrderSizeTooLarge,
+				fmt.Sprintf("order size cannot exceed %d", s.limits.MaxOrderSize),
+			)
+		}
+
+		orders[orderSize]++
+	}
+
+	calc, cancel := s.newCalculation(ctx)
+	defer cancel()
+
+	current := model.PackSetScore{Packs: s.repo.GetPacks()}
+	if len(current.Packs) > 0 {
+		packList, err := sortPacks(current.Packs)
+		if err != nil {
+			return model.RecommendationResponse{}, err
+		}
+
+		if current, err = calc.scorePacks(packList, orders); err != nil {
+			return model.RecommendationResponse{}, err
+		}
+	}
+
+	candidates := recommendationCandidates(orders, current.Packs)
+	packCount := min(req.PackCount, len(candidates))
+
+	// build the set greedily, adding the candidate that improves the score the most
+	var best model.PackSetScore
+	chosen := make([]model.PackSize, 0, packCount)
+	for len(chosen) < packCount {
+		var bestCandidate model.PackSize
+		for _, candidate := range candidates {
+			if slices.Contains(chosen, candidate) {
+				continue
+			}
+
+			score, err := calc.scorePackSizes(append(slices.Clone(chosen), candidate), orders)
+			if err != nil {
+				return model.RecommendationResponse{}, err
+			}
+
+			if bestCandidate == 0 || compareScores(score, best) < 0 {
+				best, bestCandidate = score, candidate
+			}
+		}
+		chosen = append(chosen, bestCandidate)
+	}
+
+	// swap single pack sizes for other candidates while it improves the score
+	for improved := true; improved; {
+		improved = false
+		for i := range chosen {
+			for _, candidate := range candidates {
+				if slices.Contains(chosen, candidate) {
+					continue
+				}
+
+				swapped := slices.Clone(chosen)
+				swapped[i] = candidate
+				score, err := calc.scorePackSizes(swapped, orders)
+				if err != nil {
+					return model.RecommendationResponse{}, err
+				}
+
+				if compareScores(score, best) < 0 {
+					best, chosen, improved = score, swapped, true
+				}
+			}
+		}
+	}
+
+	result := model.RecommendationResponse{
+		Packs:       best.Packs,
+		OrderCount:  int64(len(orderSizes)),
+		Current:     current,
+		Recommended: best,
+	}
+	if len(current.Packs) > 0 {
+		result.OvershipmentReduction = current.TotalOvershipment - best.TotalOvershipment
+		result.PackCountReduction = current.TotalPacks - best.TotalPacks
+	}
+
+	return result, nil
+}
+
+// recommendationCandidates returns the pack sizes a recommendation chooses from: the most frequent
+// order sizes, as they ship without overshipment, and the available pack sizes
+func recommendationCandidates(orders map[int64]int64, packs model.Packs) []model.PackSize {
+	orderSizes := slices.SortedFunc(maps.Keys(orders), func(a, b int64) int {
+		if orders[a] != orders[b] {
+			return cmp.Compare(orders[b], orders[a])
+		}
+
+		return cmp.Compare(a, b)
+	})
+
+	candidates := make([]model.PackSize, 0, maxRecommendationCandidates)
+	for _, pack := range packs {
+		if len(candidates) < maxRecommendationCandidates {
+			candidates = append(candidates, pack.Size)
+		}
+	}
+
+	for _, orderSize := range orderSizes {
+		if len(candidates) == maxRecommendationCandidates {
+			break
+		}
+
+		if !slices.Contains(candidates, model.PackSize(orderSize)) {
+			candidates = append(candidates, model.PackSize(orderSize))
+		}
+	}
+
+	slices.Sort(candidates)
+
+	return candidates
+}
+
+// compareScores orders pack set scores by total overshipment (Rule #2) and then by total packs (Rule #3)
+func compareScores(a, b model.PackSetScore) int {
+	if a.TotalOvershipment != b.TotalOvershipment {
+		return cmp.Compare(a.TotalOvershipment, b.TotalOvershipment)
+	}
+
+	return cmp.Compare(a.TotalPacks, b.TotalPacks)
+}
+
+// scorePackSizes scores a pack set given by its pack sizes for the orders
+func (c *calculation) scorePackSizes(sizes []model.PackSize, orders map[int64]int64) (model.PackSetScore, error) {
+	packList := make(model.Packs, len(sizes))
+	for i, size := range sizes {
+		packList[i] = model.Pack{Size: size}
+	}
+
+	slices.SortFunc(packList, func(a, b model.Pack) int {
+		return cmp.Compare(b.Size, a.Size)
+	})
+
+	return c.scorePacks(packList, orders)
+}
+
+// scorePacks scores a pack set sorted in descending order by pack size for the orders,
+// which map an order size to the number of orders of that size
+func (c *calculation) scorePacks(packList model.Packs, orders map[int64]int64) (model.PackSetScore, error) {
+	solver, err := c.newSolver(packList, slices.Max(slices.Collect(maps.Keys(orders))), model.ShipmentModeOvership)
+	if err != nil {
+		return model.PackSetScore{}, err
+	}
+
+	score := model.PackSetScore{Packs: packList}
+	var orderCount int64
+	for orderSize, count := range orders {
+		if err := c.step(int64(len(packList))); err != nil {
+			return model.PackSetScore{}, err
+		}
+
+		combination, err := solver.Solve(orderSize)
+		if err != nil {
+			return model.PackSetScore{}, solverError(err)
+		}
+
+		shippedItems, packCount, ok := getAmountOfItemsInPacks(combination)
+		if !ok {
+			return model.PackSetScore{}, model.ErrArithmeticOverflow
+		}
+
+		overshipment, ok := packing.CheckedMul(shippedItems-orderSize, count)
+		if !ok {
+			return model.PackSetScore{}, model.ErrArithmeticOverflow
+		}
+
+		packs, ok := packing.CheckedMul(packCount, count)
+		if !ok {
+			return model.PackSetScore{}, model.ErrArithmeticOverflow
+		}
+
+		if score.TotalOvershipment, ok = packing.CheckedAdd(score.TotalOvershipment, overshipment); !ok {
+			return model.PackSetScore{}, model.ErrArithmeticOverflow
+		}
+
+		if score.TotalPacks, ok = packing.CheckedAdd(score.TotalPacks, packs); !ok {
+			return model.PackSetScore{}, model.ErrArithmeticOverflow
+		}
+
+		orderCount += count
+	}
+
+	score.AverageOvershipment = float64(score.TotalOvershipment) / float64(orderCount)
+	score.AveragePacks = float64(score.TotalPacks) / float64(orderCount)
+
+	return score, nil
+}
@@ -2,10 +2,15 @@ package service
 
 import (
 	"cmp"
+	"context"
 	"fmt"
 	"slices"
+	"sync"
+	"time"
 
 	"github.com/alishercodecrafter/orderpackscalculator/internal/model"
+	"github.com/alishercodecrafter/orderpackscalculator/internal/netguard"
+	"github.com/alishercodecrafter/orderpackscalculator/pkg/packing"
 )
 
 // PacksRepository defines the interface for pack data storage
@@ -16,210 +21,654 @@ type PacksRepository interface {
 	AddPack(pack model.Pack) error
 	// RemovePack removes a pack by its size
 	RemovePack(packSize model.PackSize) error
+	// ReplacePacks replaces all packs
+	ReplacePacks(packs model.Packs) error
+	// GetPackChanges returns the changes of the available packs after the version, oldest first
+	GetPackChanges(since int64) ([]model.PackChange, error)
+	// GetPacksAt returns the available packs of a version
+	GetPacksAt(version int64) (model.Packs, error)
+	// AddCalculation records a calculation in the history
+	AddCalculation(calculation model.CalculationRecord) error
+	// GetCalculations returns the calculation history, oldest first
+	GetCalculations() []model.CalculationRecord
+	// GetCatalogs returns all catalogs except the default one
+	GetCatalogs() []model.Catalog
+	// GetCatalog returns a catalog by its ID
+	GetCatalog(id string) (model.Catalog, error)
+	// SaveCatalog adds a catalog or replaces the catalog with the same ID
+	SaveCatalog(catalog model.Catalog) error
+	// RemoveCatalog removes a catalog by its ID
+	RemoveCatalog(id string) error
+	// GetPackaging returns the hierarchy of outer containers packs are shipped in
+	GetPackaging() model.Packaging
+	// SavePackaging replaces the hierarchy of outer containers packs are shipped in
+	SavePackaging(packaging model.Packaging) error
+	// GetWarehouses returns all warehouses
+	GetWarehouses() []model.Warehouse
+	// SaveWarehouse adds a warehouse or replaces the warehouse with the same ID
+	SaveWarehouse(warehouse model.Warehouse) error
+	// RemoveWarehouse removes a warehouse by its ID
+	RemoveWarehouse(id string) error
+	// GetWebhooks returns all webhooks with their secrets
+	GetWebhooks() []model.Webhook
+	// SaveWebhook adds a webhook or replaces the webhook with the same ID
+	SaveWebhook(webhook model.Webhook) error
+	// RemoveWebhook removes a webhook by its ID
+	RemoveWebhook(id string) error
+	// AddDeadLetter records an undelivered webhook event
+	AddDeadLetter(deadLetter model.DeadLetter) error
+	// GetDeadLetters returns the undelivered webhook events, oldest first
+	GetDeadLetters() []model.DeadLetter
+}
+
+const (
+	// DefaultCalculationTimeout is the default time budget of a single calculation
+	DefaultCalculationTimeout = 5 * time.Second
+	// DefaultCalculationMaxSteps is the default work budget of a single calculation
+	DefaultCalculationMaxSteps = 10_000_000
+)
+
+// DefaultLimits returns the default bounds of calculation requests
+func DefaultLimits() model.Limits {
+	return model.Limits{
+		MaxOrderSize:        1_000_000_000,
+		MaxPacks:            100,
+		MaxOrderToPackRatio: 10_000_000,
+		MaxAnalysisRange:    100_000,
+	}
 }
 
 // PacksServiceImpl handles the business logic for pack calculations
 type PacksServiceImpl struct {
 	repo PacksRepository
+	// calculationTimeout limits the duration of a single calculation, zero means no limit
+	calculationTimeout time.Duration
+	// calculationMaxSteps limits the number of search steps of a single calculation, zero means no limit
+	calculationMaxSteps int
+	// limits bounds the order sizes and the pack sets the service accepts
+	limits model.Limits
+	// packsMu serialises the changes of the available packs, so that the pack count limit is checked and
+	// the change is made at once
+	packsMu sync.Mutex
+	// tolerance bounds the overshipment of the catalogs without a tolerance of their own
+	tolerance model.OvershipmentTolerance
+	// rateTables price the shipping of orders by carrier
+	rateTables []model.RateTable
+	// packsEvents broadcasts the changes of the available packs
+	packsEvents *packsEvents
+	// webhooks delivers the changes of the packs and the calculations to webhooks, nil if there are none
+	webhooks WebhookNotifier
+	// webhookAddresses decides which addresses the URLs of webhooks may resolve to
+	webhookAddresses netguard.Policy
+}
+
+// Option configures a PacksServiceImpl
+type Option func(*PacksServiceImpl)
+
+// WithCalculationTimeout sets the time budget of a single calculation, zero disables the limit
+func WithCalculationTimeout(timeout time.Duration) Option {
+	return func(s *PacksServiceImpl) {
+		s.calculationTimeout = timeout
+	}
+}
+
+// WithCalculationMaxSteps sets the work budget of a single calculation, zero disables the limit
+func WithCalculationMaxSteps(maxSteps int) Option {
+	return func(s *PacksServiceImpl) {
+		s.calculationMaxSteps = maxSteps
+	}
+}
+
+// WithLimits sets the bounds of calculation requests
+func WithLimits(limits model.Limits) Option {
+	return func(s *PacksServiceImpl) {
+		s.limits = limits
+	}
+}
+
+// WithOvershipmentTolerance sets the overshipment tolerance of the catalogs without a tolerance of their own
+func WithOvershipmentTolerance(tolerance model.OvershipmentTolerance) Option {
+	return func(s *PacksServiceImpl) {
+		s.tolerance = tolerance
+	}
+}
+
+// WithRateTables sets the carrier rate tables the shipping cost of orders is priced with
+func WithRateTables(tables []model.RateTable) Option {
+	return func(s *PacksServiceImpl) {
+		s.rateTables = tables
+	}
 }
 
 // NewPacksService creates a new PacksServiceImpl
-func NewPacksService(repo PacksRepository) *PacksServiceImpl {
-	return &PacksServiceImpl{
-		repo: repo,
+func NewPacksService(repo PacksRepository, opts ...Option) *PacksServiceImpl {
+	s := &PacksServiceImpl{
+		repo:                repo,
+		calculationTimeout:  DefaultCalculationTimeout,
+		calculationMaxSteps: DefaultCalculationMaxSteps,
+		limits:              DefaultLimits(),
+		packsEvents:         &packsEvents{},
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
 }
 
-// GetPackSizes returns all available pack sizes
+// GetPacks returns all available packs
 func (s *PacksServiceImpl) GetPacks() model.Packs {
 	return s.repo.GetPacks()
 }
 
+// Limits returns the bounds of calculation requests
+func (s *PacksServiceImpl) Limits() model.Limits {
+	return s.limits
+}
+
 // AddPack adds a new pack
 func (s *PacksServiceImpl) AddPack(pack model.Pack) error {
-	if pack.Size <= 0 {
-		return fmt.Errorf("pack size must be greater than zero")
+	s.packsMu.Lock()
+	defer s.packsMu.Unlock()
+
+	if err := validatePack(pack); err != nil {
+		return err
+	}
+
+	// the available packs are counted in items
+	if _, err := unitPacks(model.UnitItem, model.Packs{pack}); err != nil {
+		return err
+	}
+
+	if s.limits.MaxPacks > 0 && len(s.repo.GetPacks()) >= s.limits.MaxPacks {
+		return model.NewError(
+			model.ErrorCodeTooManyPacks,
+			fmt.Sprintf("number of packs cannot exceed %d", s.limits.MaxPacks),
+		)
 	}
 
-	return s.repo.AddPack(pack)
+	if err := s.repo.AddPack(pack); err != nil {
+		return err
+	}
+	s.packsChanged(model.PacksEventAdded)
+
+	return nil
 }
 
 // RemovePackSize removes a pack size
 func (s *PacksServiceImpl) RemovePack(packSize model.PackSize) error {
-	return s.repo.RemovePack(packSize)
+	s.packsMu.Lock()
+	defer s.packsMu.Unlock()
+
+	if err := s.repo.RemovePack(packSize); err != nil {
+		return err
+	}
+	s.packsChanged(model.PacksEventRemoved)
+
+	return nil
 }
 
-// CalculatePacks calculates the optimal number of packs needed for an order
-func (s *PacksServiceImpl) CalculatePacks(orderSize int) (model.CalculationResponse, error) {
-	packList := s.repo.GetPacks()
-	// If no packList or invalid order size, return empty packsRule2
-	if len(packList) == 0 {
-		return model.CalculationResponse{}, fmt.Errorf("available packsRule2 list is empty")
+// ReplacePacks replaces all available packs at once
+func (s *PacksServiceImpl) ReplacePacks(packs model.Packs) error {
+	s.packsMu.Lock()
+	defer s.packsMu.Unlock()
+
+	if _, err := sortPacks(packs); err != nil {
+		return err
 	}
 
-	if orderSize <= 0 {
-		return model.CalculationResponse{}, fmt.Errorf("order size must be greater than zero")
+	// the available packs are counted in items
+	if _, err := unitPacks(model.UnitItem, packs); err != nil {
+		return err
 	}
 
-	// Sort packs list in descending order based on size
-	slices.SortFunc(packList, func(a, b model.Pack) int {
-		return cmp.Compare(b.Size, a.Size)
-	})
+	if s.limits.MaxPacks > 0 && len(packs) > s.limits.MaxPacks {
+		return model.NewError(
+			model.ErrorCodeTooManyPacks,
+			fmt.Sprintf("number of packs cannot exceed %d", s.limits.MaxPacks),
+		)
+	}
 
-	// calculate packs for the remaining order size
-	results := calculatePacks(orderSize, orderSize, &packList, 0)
+	if err := s.repo.ReplacePacks(packs); err != nil {
+		return err
+	}
+	s.packsChanged(model.PacksEventReplaced)
 
-	return model.CalculationResponse{
-		OrderSize: orderSize,
-		Packs:     getTheBestCombinationOfPacks(results),
-	}, nil
+	return nil
 }
 
-// getAmountOfItemsInPacks calculates the total amount of items in packs and the total count of packs
-// Returns:
-// - amount: the total amount of items in packs
-// - totalCount: the total count of packs
-func getAmountOfItemsInPacks(packs map[model.PackSize]int) (int, int) {
-	amount := 0
-	totalCount := 0
-	for packSize, count := range packs {
-		amount += int(packSize) * count
-		totalCount += count
-	}
-
-	return amount, totalCount
-}
-
-// calculatePacks is a helper function to calculate the optimal number of packs needed for an order
-// Parameters:
-// - originalOrderSize: the original size of the order
-// - orderSize: the current size of the order being processed
-// - packsList: a pointer to the list of available packs sorted in descending order by pack size
-// - startFrom: the index in packsList from which to start processing
-func calculatePacks(
-	originalOrderSize,
-	orderSize int,
-	packsList *model.Packs,
-	startFrom int,
-) []map[model.PackSize]int {
-	// If orderSize is zero or less, return nil
-	if orderSize <= 0 {
-		return nil
+// GetPackChanges returns the event log of the available packs after the version, oldest first.
+// The version of the packs after a change is the position of the change in the log counted from 1.
+func (s *PacksServiceImpl) GetPackChanges(since int64) ([]model.PackChange, error) {
+	if since < 0 {
+		return nil, model.NewError(model.ErrorCodeInvalidRequest, "packs version cannot be negative")
 	}
 
-	// If startFrom index is out of bounds, return nil
-	if startFrom >= len(*packsList) {
-		return nil
+	return s.repo.GetPackChanges(since)
+}
+
+// GetPacksAt returns the available packs of a version, rebuilt from their event log
+func (s *PacksServiceImpl) GetPacksAt(version int64) (model.Packs, error) {
+	return s.repo.GetPacksAt(version)
+}
+
+// CalculatePacks calculates the optimal number of packs needed for an order.
+// The calculation stops when ctx is done or when the configured time or work budget is exhausted.
+func (s *PacksServiceImpl) CalculatePacks(ctx context.Context, orderSize int64) (model.CalculationResponse, error) {
+	return s.Calculate(ctx, model.CalculationRequest{OrderSize: orderSize})
+}
+
+// Calculate calculates the optimal number of packs needed for an order
+func (s *PacksServiceImpl) Calculate(
+	ctx context.Context,
+	req model.CalculationRequest,
+) (model.CalculationResponse, error) {
+	mode := cmp.Or(req.Mode, model.ShipmentModeOvership)
+	if mode != model.ShipmentModeOvership && mode != model.ShipmentModeUndership {
+		return model.CalculationResponse{}, model.NewError(
+			model.ErrorCodeInvalidShipmentMode,
+			fmt.Sprintf("shipment mode must be %q or %q", model.ShipmentModeOvership, model.ShipmentModeUndership),
+		)
 	}
 
-	// list of packs to return
-	result := make([]map[model.PackSize]int, 0, len(*packsList))
+	if req.Warehouses && (len(req.Lines) > 0 || req.Split != nil) {
+		return model.CalculationResponse{}, model.NewError(
+			model.ErrorCodeInvalidRequest,
+			"warehouse allocation is only supported for single-line orders without a shipment split",
+		)
+	}
 
-	// on 1st iteration we pack whole order size for each available pack sizes
-	if startFrom == 0 {
-		for i := 0; i < len(*packsList); i++ {
-			packSize := (*packsList)[i].Size
+	if req.ShippingCost {
+		if len(req.Lines) > 0 || req.Packaging || req.Split != nil || req.Warehouses {
+			return model.CalculationResponse{}, model.NewError(
+				model.ErrorCodeInvalidRequest,
+				"shipping cost is only supported for single-line orders without a packaging plan, a shipment split "+
+					"or warehouse allocation",
+			)
+		}
 
-			count := originalOrderSize / int(packSize)
-			remainOfDivision := originalOrderSize % int(packSize)
-			if remainOfDivision != 0 {
-				count++
-			}
+		if len(s.rateTables) == 0 {
+			return model.CalculationResponse{}, model.NewError(
+				model.ErrorCodeNoRateTables,
+				"there are no carrier rate tables to price the shipping with",
+			)
+		}
+	}
 
-			m := make(map[model.PackSize]int)
-			m[packSize] = count
+	if req.Split != nil {
+		if len(req.Lines) > 0 || req.Packaging {
+			return model.CalculationResponse{}, model.NewError(
+				model.ErrorCodeInvalidSplit,
+				"shipment split is only supported for single-line orders without a packaging plan",
+			)
+		}
 
-			result = append(result, m)
+		if err := validateSplit(*req.Split); err != nil {
+			return model.CalculationResponse{}, err
 		}
 	}
 
-	// on 2nd and further iterations we try remaining order in packs bigger than it
-	for i := startFrom; i < len(*packsList); i++ {
-		packSize := (*packsList)[i].Size
+	calc, cancel := s.newCalculation(ctx)
+	defer cancel()
+
+	var packaging *model.Packaging
+	if req.Packaging {
+		hierarchy := s.repo.GetPackaging()
+		if len(hierarchy.Levels) == 0 {
+			return model.CalculationResponse{}, model.NewError(
+				model.ErrorCodeInvalidPackaging,
+				"there are no container levels to plan the packaging with",
+			)
+		}
+		packaging = &hierarchy
+	}
+
+	var result model.CalculationResponse
+	var err error
+	if len(req.Lines) == 0 {
+		result, err = s.calculateSingleLine(calc, req, mode, packaging)
+	} else {
+		result, err = s.calculateLines(calc, req.Lines, mode, packaging)
+	}
+	if err != nil {
+		return model.CalculationResponse{}, err
+	}
+
+	record := model.CalculationRecord{
+		CalculationResponse: result,
+		CalculatedAt:        time.Now(),
+	}
+	if err := s.repo.AddCalculation(record); err != nil {
+		return model.CalculationResponse{}, err
+	}
+	s.notify(model.WebhookEventCalculationCompleted, record)
+
+	return result, nil
+}
 
-		if orderSize != originalOrderSize && orderSize <= int(packSize) {
-			m := make(map[model.PackSize]int)
-			m[packSize] = 1
-			result = append(result, m)
+// calculateSingleLine calculates the packs of a single-line order in the shipment mode, from the available packs
+// or from the stock of the warehouses, and plans its packaging, its split into shipments or its shipping if requested
+func (s *PacksServiceImpl) calculateSingleLine(
+	calc *calculation,
+	req model.CalculationRequest,
+	mode model.ShipmentMode,
+	packaging *model.Packaging,
+) (model.CalculationResponse, error) {
+	var packList model.Packs
+	var alternatives []map[model.PackSize]int64
+	var allocations []model.WarehouseAllocation
+	if req.Warehouses {
+		packs, warehouseAllocations, err := s.allocate(calc, req.OrderSize, mode)
+		if err != nil {
+			return model.CalculationResponse{}, err
+		}
+		alternatives, allocations = []map[model.PackSize]int64{packs}, warehouseAllocations
+	} else {
+		available := s.repo.GetPacks()
+		if req.Split != nil {
+			var err error
+			if available, err = fittingPacks(*req.Split, available); err != nil {
+				return model.CalculationResponse{}, err
+			}
+		}
+
+		var err error
+		packList, alternatives, err = s.calculateOrder(
+			calc,
+			available,
+			req.OrderSize,
+			mode,
+			packaging != nil || req.Split != nil || req.ShippingCost,
+		)
+		if err != nil {
+			return model.CalculationResponse{}, err
 		}
 	}
 
-	m := make(map[model.PackSize]int)
-	currentPackSize := (*packsList)[startFrom].Size
-	// if orderSize is larger than the current pack size, we can use it
-	if orderSize > int(currentPackSize) {
-		count := orderSize / int(currentPackSize)
-		m[currentPackSize] += count
-		remainOfDivision := orderSize % int(currentPackSize)
-		// if there is no remainder, we can return the result because we have found the best combination
-		if remainOfDivision == 0 {
-			result = append(result, m)
+	packs, plan, err := choosePackaging(packaging, nil, alternatives)
+	if err != nil {
+		return model.CalculationResponse{}, err
+	}
 
-			return result
-		} else {
-			orderSize = remainOfDivision
+	var splitPlan *model.SplitPlan
+	if req.Split != nil {
+		if packs, splitPlan, err = chooseSplit(*req.Split, packList, alternatives); err != nil {
+			return model.CalculationResponse{}, err
 		}
+	}
 
-		// if we have more packs to process,
-		// we can try to find the best combination of packs for the remaining order size
-		if startFrom < len(*packsList)-1 {
-			innerMap := getTheBestCombinationOfPacks(calculatePacks(
-				originalOrderSize,
-				orderSize,
-				packsList,
-				startFrom+1,
-			))
-			for packSize, count := range innerMap {
-				m[packSize] += count
-			}
-		} else {
-			m[currentPackSize]++
+	var quote *model.ShippingQuote
+	if req.ShippingCost {
+		if packs, quote, err = chooseLandedCost(s.rateTables, packList, alternatives); err != nil {
+			return model.CalculationResponse{}, err
 		}
+	}
 
-		result = append(result, m)
-	} else {
-		// go to next pack size
-		result = append(result, getTheBestCombinationOfPacks(calculatePacks(
-			originalOrderSize,
-			originalOrderSize,
-			packsList,
-			startFrom+1,
-		)))
+	// the amount of items does not overflow, calculateOrder checks every alternative
+	shippedItems, _, _ := getAmountOfItemsInPacks(packs)
+	exceeded, err := checkTolerance(s.tolerance, req.OrderSize, shippedItems)
+	if err != nil {
+		return model.CalculationResponse{}, err
 	}
 
-	return result
+	return model.CalculationResponse{
+		OrderSize:         req.OrderSize,
+		Packs:             packs,
+		Backorder:         max(req.OrderSize-shippedItems, 0),
+		ToleranceExceeded: exceeded,
+		Packaging:         plan,
+		Split:             splitPlan,
+		Allocations:       allocations,
+		Shipping:          quote,
+	}, nil
 }
 
-// getTheBestCombinationOfPacks finds the best combination of packs from a list of pack combinations
-func getTheBestCombinationOfPacks(listOfPacks []map[model.PackSize]int) map[model.PackSize]int {
-	if len(listOfPacks) == 0 {
-		return map[model.PackSize]int{}
+// calculateLines calculates the packs of each line of a multi-line order in the shipment mode and sums them up.
+// With a packaging hierarchy each line takes the packs that nest best with the packs of the previous lines.
+func (s *PacksServiceImpl) calculateLines(
+	calc *calculation,
+	lines []model.OrderLine,
+	mode model.ShipmentMode,
+	packaging *model.Packaging,
+) (model.CalculationResponse, error) {
+	result := model.CalculationResponse{
+		Lines:  make([]model.LineResult, 0, len(lines)),
+		Totals: &model.ShipmentTotals{},
 	}
 
-	// exclude empty packs from the list
-	normalListOfPacks := make([]map[model.PackSize]int, 0, len(listOfPacks))
-	for _, packs := range listOfPacks {
-		if len(packs) == 0 {
-			continue
+	// shipped holds the packs of all the lines calculated so far
+	shipped := make(map[model.PackSize]int64)
+
+	for _, line := range lines {
+		catalog, err := s.GetCatalog(line.Catalog)
+		if err != nil {
+			return model.CalculationResponse{}, err
+		}
+
+		if packaging != nil && catalog.Unit.Measured() {
+			return model.CalculationResponse{}, model.NewError(
+				model.ErrorCodeInvalidUnit,
+				fmt.Sprintf("packaging plan is not supported for catalog %q measured in %s", catalog.ID, catalog.Unit),
+			)
+		}
+
+		quantity, err := lineQuantity(catalog, line)
+		if err != nil {
+			return model.CalculationResponse{}, err
+		}
+
+		packList, alternatives, err := s.calculateOrder(calc, catalog.Packs, quantity, mode, packaging != nil)
+		if err != nil {
+			return model.CalculationResponse{}, err
+		}
+
+		packs, plan, err := choosePackaging(packaging, shipped, alternatives)
+		if err != nil {
+			return model.CalculationResponse{}, err
 		}
 
-		normalListOfPacks = append(normalListOfPacks, packs)
+		if shipped, err = mergePacks(shipped, packs); err != nil {
+			return model.CalculationResponse{}, err
+		}
+		result.Packaging = plan
+
+		shipment, err := newShipment(quantity, packs, packList)
+		if err != nil {
+			return model.CalculationResponse{}, err
+		}
+
+		exceeded, err := checkTolerance(s.toleranceOf(catalog), quantity, shipment.ShippedItems)
+		if err != nil {
+			return model.CalculationResponse{}, err
+		}
+		result.ToleranceExceeded = result.ToleranceExceeded || exceeded
+
+		lineResult := model.LineResult{
+			Catalog:           catalog.ID,
+			Quantity:          line.Quantity,
+			Unit:              catalog.Unit,
+			ToleranceExceeded: exceeded,
+			Shipment:          shipment,
+		}
+
+		// the totals count items, a product measured in kilograms or litres only adds its packs
+		counted := shipment
+		if catalog.Unit.Measured() {
+			lineResult.Amount = line.Amount
+			lineResult.ShippedAmount = model.Quantity(shipment.ShippedItems)
+			lineResult.Contents = packContents(shipment.Packs)
+			counted.ShippedItems, counted.Overshipment, counted.Backorder = 0, 0, 0
+		}
+
+		if err := addShipment(result.Totals, counted); err != nil {
+			return model.CalculationResponse{}, err
+		}
+
+		var ok bool
+		if result.OrderSize, ok = packing.CheckedAdd(result.OrderSize, line.Quantity); !ok {
+			return model.CalculationResponse{}, model.ErrArithmeticOverflow
+		}
+
+		result.Lines = append(result.Lines, lineResult)
+	}
+
+	return result, nil
+}
+
+// calculateOrder checks an order against the service limits and finds its best packs in the shipment mode
+// Returns:
+// - packList: the packs sorted in descending order by pack size
+// - alternatives: the maps of pack size to count of the best combination, followed by
+// the other equally good combinations when withAlternatives is set
+func (s *PacksServiceImpl) calculateOrder(
+	calc *calculation,
+	packs model.Packs,
+	orderSize int64,
+	mode model.ShipmentMode,
+	withAlternatives bool,
+) (model.Packs, []map[model.PackSize]int64, error) {
+	if orderSize <= 0 {
+		return nil, nil, model.NewError(model.ErrorCodeInvalidOrderSize, "order size must be greater than zero")
+	}
+
+	packList, err := sortPacks(packs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := s.checkLimits(orderSize, packList); err != nil {
+		return nil, nil, err
+	}
+
+	limit := 1
+	if withAlternatives {
+		limit = maxAlternatives
+	}
+
+	solver, err := calc.newSolver(packList, orderSize, mode)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	combinations, err := solver.Alternatives(orderSize, limit)
+	if err != nil {
+		return nil, nil, solverError(err)
+	}
+
+	alternatives := make([]map[model.PackSize]int64, len(combinations))
+	for i, combination := range combinations {
+		alternatives[i] = combination
 	}
 
-	slices.SortFunc(normalListOfPacks, func(a, b map[model.PackSize]int) int {
-		amountA, countA := getAmountOfItemsInPacks(a)
-		amountB, countB := getAmountOfItemsInPacks(b)
-		// Compare by total amount of items in packs first
-		if amountA != amountB {
-			return cmp.Compare(amountA, amountB)
+	for _, alternative := range alternatives {
+		if _, _, ok := getAmountOfItemsInPacks(alternative); !ok {
+			return nil, nil, model.ErrArithmeticOverflow
 		}
-		// If amounts are equal, compare by the total count of packs
-		return cmp.Compare(countA, countB)
+	}
+
+	return packList, alternatives, nil
+}
+
+// GetCalculations returns the calculation history, oldest first
+func (s *PacksServiceImpl) GetCalculations() []model.CalculationRecord {
+	return s.repo.GetCalculations()
+}
+
+// newCalculation starts a calculation bound by ctx and the time and work budgets of the service
+func (s *PacksServiceImpl) newCalculation(ctx context.Context) (*calculation, context.CancelFunc) {
+	cancel := context.CancelFunc(func() {})
+	if s.calculationTimeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, s.calculationTimeout)
+	}
+
+	return &calculation{budget: packing.NewBudget(ctx, int64(s.calculationMaxSteps))}, cancel
+}
+
+// checkLimits checks that the order size and the pack list sorted in descending order are within the service limits
+func (s *PacksServiceImpl) checkLimits(orderSize int64, packList model.Packs) error {
+	if s.limits.MaxOrderSize > 0 && orderSize > s.limits.MaxOrderSize {
+		return model.NewError(
+			model.ErrorCodeOrderSizeTooLarge,
+			fmt.Sprintf("order size cannot exceed %d", s.limits.MaxOrderSize),
+		)
+	}
+
+	if s.limits.MaxPacks > 0 && len(packList) > s.limits.MaxPacks {
+		return model.NewError(
+			model.ErrorCodeTooManyPacks,
+			fmt.Sprintf("number of packs cannot exceed %d", s.limits.MaxPacks),
+		)
+	}
+
+	smallestPackSize := int64(packList[len(packList)-1].Size)
+	if s.limits.MaxOrderToPackRatio > 0 && orderSize/smallestPackSize > s.limits.MaxOrderToPackRatio {
+		return model.NewError(
+			model.ErrorCodeOrderToPackRatioTooLarge,
+			fmt.Sprintf(
+				"order size cannot exceed %d times the smallest pack size %d",
+				s.limits.MaxOrderToPackRatio,
+				smallestPackSize,
+			),
+		)
+	}
+
+	return nil
+}
+
+// sortPacks validates a pack set given by the user and returns its copy sorted in descending order by pack size
+func sortPacks(packs model.Packs) (model.Packs, error) {
+	if len(packs) == 0 {
+		return nil, model.NewError(model.ErrorCodeNoPacks, "available packs list is empty")
+	}
+
+	result := slices.Clone(packs)
+	slices.SortFunc(result, func(a, b model.Pack) int {
+		return cmp.Compare(b.Size, a.Size)
 	})
 
-	if len(normalListOfPacks) == 0 {
-		return map[model.PackSize]int{}
+	for i, pack := range result {
+		if err := validatePack(pack); err != nil {
+			return nil, err
+		}
+
+		if i > 0 && result[i-1].Size == pack.Size {
+			return nil, model.NewError(model.ErrorCodePackExists, fmt.Sprintf("pack size %d already exists", pack.Size))
+		}
+	}
+
+	return result, nil
+}
+
+// validatePack checks the size, the weight, the volume and the count limits of a pack
+func validatePack(pack model.Pack) error {
+	if pack.Size <= 0 {
+		return model.NewError(model.ErrorCodeInvalidPackSize, "pack size must be greater than zero")
+	}
+
+	if pack.Weight < 0 || pack.Volume < 0 {
+		return model.NewError(
+			model.ErrorCodeInvalidPackWeight,
+			fmt.Sprintf("weight and volume of pack size %d must not be negative", pack.Size),
+		)
+	}
+
+	if pack.MinCount < 0 || pack.MaxCount < 0 || (pack.MaxCount > 0 && pack.MaxCount < pack.MinCount) {
+		return model.NewError(
+			model.ErrorCodeInvalidPackCountLimits,
+			fmt.Sprintf("count limits of pack size %d must not be negative and the maximum not below the minimum", pack.Size),
+		)
 	}
 
-	return normalListOfPacks[0]
+	return nil
+}
+
+// getAmountOfItemsInPacks calculates the total amount of items in packs and the total count of packs
+// Returns:
+// - amount: the total amount of items in packs
+// - totalCount: the total count of packs
+// - ok: false if the amount or the count overflows int64
+func getAmountOfItemsInPacks(packs map[model.PackSize]int64) (int64, int64, bool) {
+	amount, totalCount, err := packing.Combination(packs).Totals()
+
+	return amount, totalCount, err == nil
 }
@@ -1,11 +1,21 @@
 package service
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/alishercodecrafter/orderpackscalculator/internal/model"
+	"github.com/alishercodecrafter/orderpackscalculator/internal/netguard"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/require"
 )
@@ -32,6 +42,7 @@ func TestPacksServiceImpl_AddPack(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockRepo := NewMockPacksRepository(ctrl)
+	mockRepo.EXPECT().GetPacks().Return(model.Packs{{Size: 250}, {Size: 500}}).AnyTimes()
 
 	// Test with valid pack
 	validPack := model.Pack{Size: 100}
@@ -59,6 +70,71 @@ func TestPacksServiceImpl_AddPack(t *testing.T) {
 	err = service.AddPack(errorPack)
 	require.Error(t, err)
 	require.Contains(t, err.Error(), "repo error")
+
+	// Test when the pack limit is reached
+	service = NewPacksService(mockRepo, WithLimits(model.Limits{MaxPacks: 2}))
+	err = service.AddPack(model.Pack{Size: 1000})
+
+	require.Error(t, err)
+	require.Equal(t, model.ErrorCodeTooManyPacks, model.ErrorCodeOf(err))
+}
+
+func TestPacksServiceImpl_AddPackConcurrently(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	packs := model.Packs{{Size: 250}}
+	mockRepo := NewMockPacksRepository(ctrl)
+	mockRepo.EXPECT().GetPacks().DoAndReturn(func() model.Packs {
+		return slices.Clone(packs)
+	}).AnyTimes()
+	mockRepo.EXPECT().AddPack(gomock.Any()).DoAndReturn(func(pack model.Pack) error {
+		time.Sleep(time.Millisecond)
+		packs = append(packs, pack)
+
+		return nil
+	}).AnyTimes()
+
+	service := NewPacksService(mockRepo, WithLimits(model.Limits{MaxPacks: 3}))
+
+	// Test that concurrent adds cannot push the number of packs past the limit
+	var wg sync.WaitGroup
+	for size := 1; size <= 10; size++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_ = service.AddPack(model.Pack{Size: model.PackSize(size)})
+		}()
+	}
+	wg.Wait()
+
+	require.Len(t, packs, 3)
+}
+
+func TestPacksServiceImpl_ReplacePacks(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := NewMockPacksRepository(ctrl)
+	service := NewPacksService(mockRepo, WithLimits(model.Limits{MaxPacks: 2}))
+
+	// Test successful replacement
+	packs := model.Packs{{Size: 23}, {Size: 31}}
+	mockRepo.EXPECT().ReplacePacks(packs).Return(nil)
+	require.NoError(t, service.ReplacePacks(packs))
+
+	// Test invalid pack sets
+	err := service.ReplacePacks(nil)
+	require.Equal(t, model.ErrorCodeNoPacks, model.ErrorCodeOf(err))
+
+	err = service.ReplacePacks(model.Packs{{Size: 23}, {Size: 23}})
+	require.Equal(t, model.ErrorCodePackExists, model.ErrorCodeOf(err))
+
+	err = service.ReplacePacks(model.Packs{{Size: 23}, {Size: 0}})
+	require.Equal(t, model.ErrorCodeInvalidPackSize, model.ErrorCodeOf(err))
+
+	err = service.ReplacePacks(model.Packs{{Size: 23}, {Size: 31}, {Size: 53}})
+	require.Equal(t, model.ErrorCodeTooManyPacks, model.ErrorCodeOf(err))
 }
 
 func TestPacksServiceImpl_RemovePack(t *testing.T) {
@@ -83,6 +159,78 @@ func TestPacksServiceImpl_RemovePack(t *testing.T) {
 	require.Contains(t, err.Error(), "not found")
 }
 
+func TestPacksServiceImpl_SubscribePacks(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := NewMockPacksRepository(ctrl)
+	service := NewPacksService(mockRepo)
+
+	// Without subscribers the packs are not read after a change
+	mockRepo.EXPECT().RemovePack(model.PackSize(250)).Return(nil)
+	require.NoError(t, service.RemovePack(250))
+
+	events, unsubscribe := service.SubscribePacks()
+	other, unsubscribeOther := service.SubscribePacks()
+	defer unsubscribeOther()
+
+	mockRepo.EXPECT().GetPacks().Return(model.Packs{{Size: 500}})
+	mockRepo.EXPECT().AddPack(model.Pack{Size: 750}).Return(nil)
+	mockRepo.EXPECT().GetPacks().Return(model.Packs{{Size: 500}, {Size: 750}})
+	require.NoError(t, service.AddPack(model.Pack{Size: 750}))
+
+	want := model.PacksEvent{Type: model.PacksEventAdded, Packs: model.Packs{{Size: 500}, {Size: 750}}}
+	require.Equal(t, want, <-events)
+	require.Equal(t, want, <-other)
+
+	// A failed change is not published
+	mockRepo.EXPECT().RemovePack(model.PackSize(42)).Return(model.NewError(model.ErrorCodePackNotFound, "not found"))
+	require.Error(t, service.RemovePack(42))
+
+	unsubscribe()
+	_, open := <-events
+	require.False(t, open)
+
+	// A subscriber that falls behind keeps the newest events
+	mockRepo.EXPECT().ReplacePacks(gomock.Any()).Return(nil).Times(packsEventBuffer + 1)
+	for i := range packsEventBuffer + 1 {
+		packs := model.Packs{{Size: model.PackSize(i + 1)}}
+		mockRepo.EXPECT().GetPacks().Return(packs)
+		require.NoError(t, service.ReplacePacks(packs))
+	}
+
+	require.Len(t, other, packsEventBuffer)
+	var last model.PacksEvent
+	for range packsEventBuffer {
+		last = <-other
+	}
+	require.Equal(t, model.PacksEvent{
+		Type:  model.PacksEventReplaced,
+		Packs: model.Packs{{Size: packsEventBuffer + 1}},
+	}, last)
+}
+
+func TestPacksServiceImpl_PackChanges(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := NewMockPacksRepository(ctrl)
+	service := NewPacksService(mockRepo)
+
+	changes := []model.PackChange{{Version: 3, Type: model.PacksEventRemoved, Size: 250}}
+	mockRepo.EXPECT().GetPackChanges(int64(2)).Return(changes, nil)
+	result, err := service.GetPackChanges(2)
+	require.NoError(t, err)
+	require.Equal(t, changes, result)
+
+	_, err = service.GetPackChanges(-1)
+	require.Equal(t, model.ErrorCodeInvalidRequest, model.ErrorCodeOf(err))
+
+	mockRepo.EXPECT().GetPacksAt(int64(9)).Return(nil, model.NewError(model.ErrorCodePacksVersionNotFound, "not found"))
+	_, err = service.GetPacksAt(9)
+	require.Equal(t, model.ErrorCodePacksVersionNotFound, model.ErrorCodeOf(err))
+}
+
 func TestPacksServiceImpl_CalculatePacks(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -98,42 +246,43 @@ func TestPacksServiceImpl_CalculatePacks(t *testing.T) {
 
 	// Since this will be called for each test case, we use AnyTimes()
 	mockRepo.EXPECT().GetPacks().Return(packs).AnyTimes()
+	mockRepo.EXPECT().AddCalculation(gomock.Any()).Return(nil).AnyTimes()
 
 	service := NewPacksService(mockRepo)
 
 	testCases := []struct {
-		orderSize     int
-		expectedPacks map[model.PackSize]int
+		orderSize     int64
+		expectedPacks map[model.PackSize]int64
 	}{
 		{
 			orderSize:     1,
-			expectedPacks: map[model.PackSize]int{250: 1},
+			expectedPacks: map[model.PackSize]int64{250: 1},
 		},
 		{
 			orderSize:     250,
-			expectedPacks: map[model.PackSize]int{250: 1},
+			expectedPacks: map[model.PackSize]int64{250: 1},
 		},
 		{
 			orderSize:     251,
-			expectedPacks: map[model.PackSize]int{500: 1},
+			expectedPacks: map[model.PackSize]int64{500: 1},
 		},
 		{
 			orderSize:     501,
-			expectedPacks: map[model.PackSize]int{250: 1, 500: 1},
+			expectedPacks: map[model.PackSize]int64{250: 1, 500: 1},
 		},
 		{
 			orderSize:     12001,
-			expectedPacks: map[model.PackSize]int{250: 1, 2000: 1, 5000: 2},
+			expectedPacks: map[model.PackSize]int64{250: 1, 2000: 1, 5000: 2},
 		},
 		{
 			orderSize:     14450,
-			expectedPacks: map[model.PackSize]int{5000: 2, 2000: 2, 500: 1},
+			expectedPacks: map[model.PackSize]int64{5000: 2, 2000: 2, 500: 1},
 		},
 	}
 
 	for _, tc := range testCases {
 		t.Run(fmt.Sprintf("Order size %d", tc.orderSize), func(t *testing.T) {
-			result, err := service.CalculatePacks(tc.orderSize)
+			result, err := service.CalculatePacks(context.Background(), tc.orderSize)
 			require.NoError(t, err)
 
 			require.Equal(t, tc.orderSize, result.OrderSize)
@@ -157,12 +306,13 @@ func TestPacksServiceImpl_CalculatePacksEdgeCases(t *testing.T) {
 
 	// Since this will be called for each test case, we use AnyTimes()
 	mockRepo.EXPECT().GetPacks().Return(packs).AnyTimes()
+	mockRepo.EXPECT().AddCalculation(gomock.Any()).Return(nil).AnyTimes()
 
 	service := NewPacksService(mockRepo)
 
 	testCases := []struct {
-		orderSize       int
-		expectedPacks   map[model.PackSize]int
+		orderSize       int64
+		expectedPacks   map[model.PackSize]int64
 		isErrorExpected bool
 	}{
 		{
@@ -175,37 +325,39 @@ func TestPacksServiceImpl_CalculatePacksEdgeCases(t *testing.T) {
 		},
 		{
 			orderSize:     1,
-			expectedPacks: map[model.PackSize]int{10: 1},
+			expectedPacks: map[model.PackSize]int64{10: 1},
 		},
 		{
 			orderSize:     250,
-			expectedPacks: map[model.PackSize]int{100: 2, 50: 1},
+			expectedPacks: map[model.PackSize]int64{100: 2, 50: 1},
 		},
 		{
+			// 255 is the least amount to ship (Rule #2), 2x100+2x20+15 are the fewest packs for it (Rule #3)
 			orderSize:     251,
-			expectedPacks: map[model.PackSize]int{15: 17},
+			expectedPacks: map[model.PackSize]int64{100: 2, 20: 2, 15: 1},
 		},
 		{
 			orderSize:     17,
-			expectedPacks: map[model.PackSize]int{20: 1},
+			expectedPacks: map[model.PackSize]int64{20: 1},
 		},
 		{
 			orderSize:     40,
-			expectedPacks: map[model.PackSize]int{20: 2},
+			expectedPacks: map[model.PackSize]int64{20: 2},
 		},
 		{
+			// 10+15 ships 25 items, which is less than 2x15 (Rule #2)
 			orderSize:     23,
-			expectedPacks: map[model.PackSize]int{15: 2},
+			expectedPacks: map[model.PackSize]int64{10: 1, 15: 1},
 		},
 		{
 			orderSize:     111,
-			expectedPacks: map[model.PackSize]int{100: 1, 15: 1},
+			expectedPacks: map[model.PackSize]int64{100: 1, 15: 1},
 		},
 	}
 
 	for _, tc := range testCases {
 		t.Run(fmt.Sprintf("Order size %d", tc.orderSize), func(t *testing.T) {
-			result, err := service.CalculatePacks(tc.orderSize)
+			result, err := service.CalculatePacks(context.Background(), tc.orderSize)
 			if tc.isErrorExpected {
 				require.Error(t, err)
 
@@ -231,23 +383,24 @@ func TestPacksServiceImpl_CalculatePacksEdgeCases2(t *testing.T) {
 
 	// Since this will be called for each test case, we use AnyTimes()
 	mockRepo.EXPECT().GetPacks().Return(packs).AnyTimes()
+	mockRepo.EXPECT().AddCalculation(gomock.Any()).Return(nil).AnyTimes()
 
 	service := NewPacksService(mockRepo)
 
 	testCases := []struct {
-		orderSize       int
-		expectedPacks   map[model.PackSize]int
+		orderSize       int64
+		expectedPacks   map[model.PackSize]int64
 		isErrorExpected bool
 	}{
 		{
 			orderSize:     14,
-			expectedPacks: map[model.PackSize]int{5: 3},
+			expectedPacks: map[model.PackSize]int64{5: 3},
 		},
 	}
 
 	for _, tc := range testCases {
 		t.Run(fmt.Sprintf("Order size %d", tc.orderSize), func(t *testing.T) {
-			result, err := service.CalculatePacks(tc.orderSize)
+			result, err := service.CalculatePacks(context.Background(), tc.orderSize)
 			if tc.isErrorExpected {
 				require.Error(t, err)
 
@@ -260,3 +413,1170 @@ func TestPacksServiceImpl_CalculatePacksEdgeCases2(t *testing.T) {
 		})
 	}
 }
+
+func TestPacksServiceImpl_CalculatePacksBudget(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := NewMockPacksRepository(ctrl)
+	packs := model.Packs{
+		{Size: 250},
+		{Size: 500},
+		{Size: 1000},
+	}
+
+	mockRepo.EXPECT().GetPacks().Return(packs).AnyTimes()
+	mockRepo.EXPECT().AddCalculation(gomock.Any()).Return(nil).AnyTimes()
+
+	// Test with cancelled context
+	service := NewPacksService(mockRepo)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := service.CalculatePacks(ctx, 12001)
+	require.ErrorIs(t, err, context.Canceled)
+	require.Equal(t, model.ErrorCodeCalculationCancelled, model.ErrorCodeOf(err))
+
+	// Test with expired deadline
+	ctx, cancel = context.WithTimeout(context.Background(), -time.Second)
+	defer cancel()
+
+	_, err = service.CalculatePacks(ctx, 12001)
+	require.ErrorIs(t, err, model.ErrCalculationTimeout)
+
+	// Test with exhausted work budget
+	service = NewPacksService(mockRepo, WithCalculationMaxSteps(1))
+
+	_, err = service.CalculatePacks(context.Background(), 12001)
+	require.ErrorIs(t, err, model.ErrCalculationBudgetExceeded)
+
+	// Test with disabled limits
+	service = NewPacksService(mockRepo, WithCalculationTimeout(0), WithCalculationMaxSteps(0))
+
+	result, err := service.CalculatePacks(context.Background(), 12001)
+	require.NoError(t, err)
+	require.Equal(t, map[model.PackSize]int64{250: 1, 1000: 12}, result.Packs)
+}
+
+func TestPacksServiceImpl_CalculatePacksLimits(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := NewMockPacksRepository(ctrl)
+	packs := model.Packs{
+		{Size: 1},
+		{Size: 250},
+		{Size: 500},
+	}
+
+	mockRepo.EXPECT().GetPacks().Return(packs).AnyTimes()
+	mockRepo.EXPECT().AddCalculation(gomock.Any()).Return(nil).AnyTimes()
+
+	service := NewPacksService(mockRepo, WithLimits(model.Limits{
+		MaxOrderSize:        1_000_000,
+		MaxPacks:            3,
+		MaxOrderToPackRatio: 1000,
+	}))
+
+	testCases := []struct {
+		orderSize    int64
+		expectedCode model.ErrorCode
+	}{
+		{
+			orderSize:    0,
+			expectedCode: model.ErrorCodeInvalidOrderSize,
+		},
+		{
+			orderSize:    1_000_001,
+			expectedCode: model.ErrorCodeOrderSizeTooLarge,
+		},
+		{
+			orderSize:    1001,
+			expectedCode: model.ErrorCodeOrderToPackRatioTooLarge,
+		},
+		{
+			orderSize: 1000,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("Order size %d", tc.orderSize), func(t *testing.T) {
+			_, err := service.CalculatePacks(context.Background(), tc.orderSize)
+			if tc.expectedCode == "" {
+				require.NoError(t, err)
+
+				return
+			}
+
+			require.Error(t, err)
+			require.Equal(t, tc.expectedCode, model.ErrorCodeOf(err))
+		})
+	}
+
+	// Test with more packs than allowed
+	service = NewPacksService(mockRepo, WithLimits(model.Limits{MaxPacks: 2}))
+
+	_, err := service.CalculatePacks(context.Background(), 100)
+	require.Equal(t, model.ErrorCodeTooManyPacks, model.ErrorCodeOf(err))
+}
+
+func TestGetAmountOfItemsInPacks(t *testing.T) {
+	amount, count, ok := getAmountOfItemsInPacks(map[model.PackSize]int64{250: 2, 500: 1})
+	require.True(t, ok)
+	require.Equal(t, int64(1000), amount)
+	require.Equal(t, int64(3), count)
+
+	_, _, ok = getAmountOfItemsInPacks(map[model.PackSize]int64{math.MaxInt64 / 2: 3})
+	require.False(t, ok)
+
+	_, _, ok = getAmountOfItemsInPacks(map[model.PackSize]int64{math.MaxInt64 / 2: 2, 1: 2})
+	require.False(t, ok)
+}
+
+func TestPacksServiceImpl_CalculatePacksLargeOrders(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	testCases := []struct {
+		packs         model.Packs
+		orderSize     int64
+		expectedPacks map[model.PackSize]int64
+		expectedErr   error
+	}{
+		{
+			packs:         model.Packs{{Size: 23}, {Size: 31}, {Size: 53}},
+			orderSize:     500_000,
+			expectedPacks: map[model.PackSize]int64{23: 2, 31: 7, 53: 9429},
+		},
+		{
+			packs:         model.Packs{{Size: 250}, {Size: 500}, {Size: 1000}, {Size: 2000}, {Size: 5000}},
+			orderSize:     1_000_000_000_000_001,
+			expectedPacks: map[model.PackSize]int64{5000: 200_000_000_000, 250: 1},
+		},
+		{
+			packs:         model.Packs{{Size: 499}, {Size: 500}},
+			orderSize:     9_000_000_000_000_000_000,
+			expectedPacks: map[model.PackSize]int64{500: 18_000_000_000_000_000},
+		},
+		{
+			packs:         model.Packs{{Size: 499}, {Size: 500}},
+			orderSize:     9_000_000_000_000_000_001,
+			expectedPacks: map[model.PackSize]int64{499: 499, 500: 18_000_000_000_000_001 - 499},
+		},
+		{
+			packs:       model.Packs{{Size: 2}},
+			orderSize:   math.MaxInt64,
+			expectedErr: model.ErrArithmeticOverflow,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("Order size %d", tc.orderSize), func(t *testing.T) {
+			mockRepo := NewMockPacksRepository(ctrl)
+			mockRepo.EXPECT().GetPacks().Return(tc.packs).AnyTimes()
+			mockRepo.EXPECT().AddCalculation(gomock.Any()).Return(nil).AnyTimes()
+
+			service := NewPacksService(mockRepo, WithLimits(model.Limits{}))
+
+			result, err := service.CalculatePacks(context.Background(), tc.orderSize)
+			if tc.expectedErr != nil {
+				require.ErrorIs(t, err, tc.expectedErr)
+
+				return
+			}
+			require.NoError(t, err)
+
+			amount, _, ok := getAmountOfItemsInPacks(result.Packs)
+			require.True(t, ok)
+			require.GreaterOrEqual(t, amount, tc.orderSize)
+			require.Equal(t, tc.expectedPacks, result.Packs)
+		})
+	}
+}
+
+func TestPacksServiceImpl_CalculatePacksWithCountLimits(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	testCases := []struct {
+		name          string
+		packs         model.Packs
+		orderSize     int64
+		expectedPacks map[model.PackSize]int64
+		expectedCode  model.ErrorCode
+	}{
+		{
+			name:          "Maximum count of the largest pack",
+			packs:         model.Packs{{Size: 250}, {Size: 500}, {Size: 1000}, {Size: 2000}, {Size: 5000, MaxCount: 2}},
+			orderSize:     12001,
+			expectedPacks: map[model.PackSize]int64{5000: 2, 2000: 1, 250: 1},
+		},
+		{
+			name:          "Minimum count of a pack",
+			packs:         model.Packs{{Size: 250, MinCount: 1}, {Size: 500}, {Size: 1000}},
+			orderSize:     1001,
+			expectedPacks: map[model.PackSize]int64{250: 1, 1000: 1},
+		},
+		{
+			name:         "Order larger than the packs can hold",
+			packs:        model.Packs{{Size: 250, MaxCount: 2}, {Size: 500, MaxCount: 1}},
+			orderSize:    1001,
+			expectedCode: model.ErrorCodeNoFeasiblePacks,
+		},
+		{
+			name:         "Maximum count below the minimum count",
+			packs:        model.Packs{{Size: 250, MinCount: 3, MaxCount: 2}},
+			orderSize:    1000,
+			expectedCode: model.ErrorCodeInvalidPackCountLimits,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRepo := NewMockPacksRepository(ctrl)
+			mockRepo.EXPECT().GetPacks().Return(tc.packs).AnyTimes()
+			mockRepo.EXPECT().AddCalculation(gomock.Any()).Return(nil).AnyTimes()
+
+			service := NewPacksService(mockRepo)
+
+			result, err := service.CalculatePacks(context.Background(), tc.orderSize)
+			if tc.expectedCode != "" {
+				require.Equal(t, tc.expectedCode, model.ErrorCodeOf(err))
+
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedPacks, result.Packs)
+		})
+	}
+}
+
+func TestPacksServiceImpl_AnalyzePacks(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := NewMockPacksRepository(ctrl)
+	mockRepo.EXPECT().GetPacks().Return(model.Packs{{Size: 250}, {Size: 500}, {Size: 1000}}).AnyTimes()
+
+	service := NewPacksService(mockRepo)
+
+	// Test with the available packs
+	result, err := service.AnalyzePacks(context.Background(), model.AnalysisRequest{
+		MinOrderSize:     1,
+		MaxOrderSize:     1000,
+		WorstOrdersLimit: 2,
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, int64(250), result.GreatestCommonDivisor)
+	require.Nil(t, result.LargestUnmatchableOrder)
+	require.Equal(t, int64(4), result.ExactOrders)
+	require.Equal(t, int64(249), result.MaxOvershipment)
+	require.InDelta(t, 124.5, result.AverageOvershipment, 0.001)
+	require.Equal(t, map[int64]int64{1: 750, 2: 250}, result.PackCountDistribution)
+	require.Equal(t, []model.OrderOvershipment{
+		{OrderSize: 1, ShippedItems: 250, Overshipment: 249, Packs: map[model.PackSize]int64{250: 1}},
+		{OrderSize: 251, ShippedItems: 500, Overshipment: 249, Packs: map[model.PackSize]int64{500: 1}},
+	}, result.WorstOrders)
+
+	// Test with a pack set given in the request
+	result, err = service.AnalyzePacks(context.Background(), model.AnalysisRequest{
+		Packs:        model.Packs{{Size: 20}, {Size: 6}, {Size: 9}},
+		MinOrderSize: 40,
+		MaxOrderSize: 50,
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, model.Packs{{Size: 20}, {Size: 9}, {Size: 6}}, result.Packs)
+	require.Equal(t, int64(1), result.GreatestCommonDivisor)
+	require.NotNil(t, result.LargestUnmatchableOrder)
+	require.Equal(t, int64(43), *result.LargestUnmatchableOrder)
+	require.Equal(t, int64(10), result.ExactOrders)
+	require.Equal(t, []model.OrderOvershipment{
+		{OrderSize: 43, ShippedItems: 44, Overshipment: 1, Packs: map[model.PackSize]int64{20: 1, 6: 1, 9: 2}},
+	}, result.WorstOrders)
+
+	// Test that the orders the packs cannot hold are counted apart from the others
+	result, err = service.AnalyzePacks(context.Background(), model.AnalysisRequest{
+		Packs:        model.Packs{{Size: 250, MaxCount: 2}, {Size: 500, MaxCount: 1}},
+		MinOrderSize: 901,
+		MaxOrderSize: 1100,
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, int64(100), result.InfeasibleOrders)
+	require.NotNil(t, result.SmallestInfeasibleOrder)
+	require.Equal(t, int64(1001), *result.SmallestInfeasibleOrder)
+	require.Equal(t, int64(1), result.ExactOrders)
+	require.Equal(t, map[int64]int64{3: 100}, result.PackCountDistribution)
+	require.InDelta(t, 49.5, result.AverageOvershipment, 0.001)
+
+	result, err = service.AnalyzePacks(context.Background(), model.AnalysisRequest{
+		Packs:        model.Packs{{Size: 250, MaxCount: 2}},
+		MinOrderSize: 501,
+		MaxOrderSize: 600,
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, int64(100), result.InfeasibleOrders)
+	require.Zero(t, result.AverageOvershipment)
+	require.Empty(t, result.WorstOrders)
+
+	// Test with invalid requests
+	_, err = service.AnalyzePacks(context.Background(), model.AnalysisRequest{MinOrderSize: 10, MaxOrderSize: 9})
+	require.Equal(t, model.ErrorCodeInvalidOrderRange, model.ErrorCodeOf(err))
+
+	_, err = service.AnalyzePacks(context.Background(), model.AnalysisRequest{MinOrderSize: 1, MaxOrderSize: 1_000_000})
+	require.Equal(t, model.ErrorCodeOrderRangeTooLarge, model.ErrorCodeOf(err))
+
+	_, err = service.AnalyzePacks(context.Background(), model.AnalysisRequest{
+		Packs:        model.Packs{{Size: 6}, {Size: 6}},
+		MinOrderSize: 1,
+		MaxOrderSize: 10,
+	})
+	require.Equal(t, model.ErrorCodePackExists, model.ErrorCodeOf(err))
+}
+
+func TestPacksServiceImpl_CalculatePacksHistory(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := NewMockPacksRepository(ctrl)
+	mockRepo.EXPECT().GetPacks().Return(model.Packs{{Size: 250}, {Size: 500}}).AnyTimes()
+
+	// Test that successful calculations are recorded
+	mockRepo.EXPECT().AddCalculation(gomock.Any()).DoAndReturn(func(record model.CalculationRecord) error {
+		require.Equal(t, int64(251), record.OrderSize)
+		require.Equal(t, map[model.PackSize]int64{500: 1}, record.Packs)
+		require.False(t, record.CalculatedAt.IsZero())
+
+		return nil
+	})
+
+	service := NewPacksService(mockRepo)
+	_, err := service.CalculatePacks(context.Background(), 251)
+	require.NoError(t, err)
+
+	// Test when repository returns error
+	mockRepo.EXPECT().AddCalculation(gomock.Any()).Return(errors.New("repo error"))
+
+	_, err = service.CalculatePacks(context.Background(), 251)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "repo error")
+}
+
+func TestPacksServiceImpl_RecommendPacks(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := NewMockPacksRepository(ctrl)
+	mockRepo.EXPECT().GetPacks().Return(model.Packs{{Size: 250}, {Size: 500}, {Size: 1000}}).AnyTimes()
+
+	service := NewPacksService(mockRepo)
+
+	// Test with uploaded order sizes
+	result, err := service.RecommendPacks(context.Background(), model.RecommendationRequest{
+		OrderSizes: []int64{300, 300, 300, 600, 600, 900, 1200},
+		PackCount:  2,
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, model.Packs{{Size: 600}, {Size: 300}}, result.Packs)
+	require.Equal(t, int64(7), result.OrderCount)
+	require.Equal(t, int64(0), result.Recommended.TotalOvershipment)
+	require.Equal(t, int64(9), result.Recommended.TotalPacks)
+	require.Equal(t, int64(1050), result.Current.TotalOvershipment)
+	require.Equal(t, int64(1050), result.OvershipmentReduction)
+
+	// Test with the calculation history
+	mockRepo.EXPECT().GetCalculations().Return([]model.CalculationRecord{
+		{CalculationResponse: model.CalculationResponse{OrderSize: 40}},
+		{CalculationResponse: model.CalculationResponse{OrderSize: 40}},
+		{CalculationResponse: model.CalculationResponse{OrderSize: 13}},
+	})
+
+	result, err = service.RecommendPacks(context.Background(), model.RecommendationRequest{PackCount: 1})
+	require.NoError(t, err)
+
+	require.Equal(t, model.Packs{{Size: 13}}, result.Packs)
+	require.Equal(t, int64(3), result.OrderCount)
+	require.Equal(t, int64(24), result.Recommended.TotalOvershipment)
+
+	// Test with invalid requests
+	_, err = service.RecommendPacks(context.Background(), model.RecommendationRequest{PackCount: 0})
+	require.Equal(t, model.ErrorCodeInvalidPackCount, model.ErrorCodeOf(err))
+	require.EqualError(t, err, "number of pack sizes must be greater than zero")
+
+	_, err = service.RecommendPacks(context.Background(), model.RecommendationRequest{PackCount: 101})
+	require.Equal(t, model.ErrorCodeInvalidPackCount, model.ErrorCodeOf(err))
+	require.EqualError(t, err, "number of pack sizes must be between 1 and 100")
+
+	unlimited := NewPacksService(mockRepo, WithLimits(model.Limits{}))
+	_, err = unlimited.RecommendPacks(context.Background(), model.RecommendationRequest{PackCount: -1})
+	require.EqualError(t, err, "number of pack sizes must be greater than zero")
+
+	mockRepo.EXPECT().GetCalculations().Return(nil)
+
+	_, err = service.RecommendPacks(context.Background(), model.RecommendationRequest{PackCount: 2})
+	require.Equal(t, model.ErrorCodeNoOrders, model.ErrorCodeOf(err))
+}
+
+func TestPacksServiceImpl_ComparePacks(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := NewMockPacksRepository(ctrl)
+	mockRepo.EXPECT().GetPacks().Return(model.Packs{{Size: 250, Cost: 10}, {Size: 500, Cost: 15}}).AnyTimes()
+
+	service := NewPacksService(mockRepo)
+
+	result, err := service.ComparePacks(context.Background(), model.ComparisonRequest{
+		Packs:      model.Packs{{Size: 300, Cost: 12}, {Size: 250, Cost: 10}},
+		OrderSizes: []int64{250, 300},
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, model.Packs{{Size: 500, Cost: 15}, {Size: 250, Cost: 10}}, result.CurrentPacks)
+	require.Equal(t, model.Packs{{Size: 300, Cost: 12}, {Size: 250, Cost: 10}}, result.CandidatePacks)
+	require.Equal(t, []model.OrderComparison{
+		{
+			OrderSize: 250,
+			Current:   model.Shipment{Packs: map[model.PackSize]int64{250: 1}, ShippedItems: 250, PackCount: 1, Cost: 10},
+			Candidate: model.Shipment{Packs: map[model.PackSize]int64{250: 1}, ShippedItems: 250, PackCount: 1, Cost: 10},
+		},
+		{
+			OrderSize: 300,
+			Current: model.Shipment{
+				Packs:        map[model.PackSize]int64{500: 1},
+				ShippedItems: 500,
+				Overshipment: 200,
+				PackCount:    1,
+				Cost:         15,
+			},
+			Candidate: model.Shipment{Packs: map[model.PackSize]int64{300: 1}, ShippedItems: 300, PackCount: 1, Cost: 12},
+			Changed:   true,
+		},
+	}, result.Orders)
+	require.Equal(t, model.ShipmentTotals{ShippedItems: 750, Overshipment: 200, PackCount: 2, Cost: 25}, result.Current)
+	require.Equal(t, model.ShipmentTotals{ShippedItems: 550, PackCount: 2, Cost: 22}, result.Candidate)
+	require.Equal(t, int64(-200), result.ShippedItemsDelta)
+	require.Equal(t, int64(0), result.PackCountDelta)
+	require.Equal(t, int64(-3), result.CostDelta)
+
+	// Test with invalid requests
+	_, err = service.ComparePacks(context.Background(), model.ComparisonRequest{Packs: model.Packs{{Size: 300}}})
+	require.Equal(t, model.ErrorCodeNoOrders, model.ErrorCodeOf(err))
+
+	_, err = service.ComparePacks(context.Background(), model.ComparisonRequest{
+		Packs:      model.Packs{{Size: 0}},
+		OrderSizes: []int64{1},
+	})
+	require.Equal(t, model.ErrorCodeInvalidPackSize, model.ErrorCodeOf(err))
+}
+
+func TestPacksServiceImpl_CalculateLines(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := NewMockPacksRepository(ctrl)
+	mockRepo.EXPECT().GetPacks().Return(model.Packs{{Size: 250, Cost: 10}, {Size: 500, Cost: 15}}).AnyTimes()
+	mockRepo.EXPECT().GetCatalog("bolts").Return(model.Catalog{
+		ID:    "bolts",
+		Name:  "Bolts",
+		Packs: model.Packs{{Size: 100, Cost: 2}, {Size: 400, Cost: 5}},
+	}, nil).AnyTimes()
+	mockRepo.EXPECT().GetCatalog("nuts").Return(model.Catalog{}, model.NewError(model.ErrorCodeCatalogNotFound, "not found"))
+	mockRepo.EXPECT().AddCalculation(gomock.Any()).Return(nil).AnyTimes()
+
+	service := NewPacksService(mockRepo)
+
+	result, err := service.Calculate(context.Background(), model.CalculationRequest{
+		Lines: []model.OrderLine{
+			{Catalog: "bolts", Quantity: 450},
+			{Quantity: 251},
+		},
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, int64(701), result.OrderSize)
+	require.Equal(t, []model.LineResult{
+		{
+			Catalog:  "bolts",
+			Quantity: 450,
+			Shipment: model.Shipment{
+				Packs:        map[model.PackSize]int64{100: 1, 400: 1},
+				ShippedItems: 500,
+				Overshipment: 50,
+				PackCount:    2,
+				Cost:         7,
+			},
+		},
+		{
+			Catalog:  model.DefaultCatalogID,
+			Quantity: 251,
+			Shipment: model.Shipment{
+				Packs:        map[model.PackSize]int64{500: 1},
+				ShippedItems: 500,
+				Overshipment: 249,
+				PackCount:    1,
+				Cost:         15,
+			},
+		},
+	}, result.Lines)
+	require.Equal(t, &model.ShipmentTotals{ShippedItems: 1000, Overshipment: 299, PackCount: 3, Cost: 22}, result.Totals)
+
+	// Test with an unknown catalog
+	_, err = service.Calculate(context.Background(), model.CalculationRequest{
+		Lines: []model.OrderLine{{Catalog: "nuts", Quantity: 10}},
+	})
+	require.Equal(t, model.ErrorCodeCatalogNotFound, model.ErrorCodeOf(err))
+
+	// Test with an invalid quantity
+	_, err = service.Calculate(context.Background(), model.CalculationRequest{
+		Lines: []model.OrderLine{{Catalog: "bolts", Quantity: 0}},
+	})
+	require.Equal(t, model.ErrorCodeInvalidOrderSize, model.ErrorCodeOf(err))
+}
+
+func TestPacksServiceImpl_CalculateMeasuredLines(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := NewMockPacksRepository(ctrl)
+	mockRepo.EXPECT().GetPacks().Return(model.Packs{{Size: 250}, {Size: 500}}).AnyTimes()
+	mockRepo.EXPECT().GetCatalog("flour").Return(model.Catalog{
+		ID:    "flour",
+		Unit:  model.UnitKilogram,
+		Packs: model.Packs{{Size: 500, Content: 500}, {Size: 2000, Content: 2000}, {Size: 5000, Content: 5000}},
+	}, nil).AnyTimes()
+	mockRepo.EXPECT().AddCalculation(gomock.Any()).Return(nil).AnyTimes()
+
+	service := NewPacksService(mockRepo)
+
+	var req model.CalculationRequest
+	require.NoError(t, json.Unmarshal([]byte(`{"lines":[{"catalog":"flour","amount":12.3},{"quantity":251}]}`), &req))
+
+	result, err := service.Calculate(context.Background(), req)
+	require.NoError(t, err)
+
+	require.Equal(t, int64(251), result.OrderSize)
+	require.Equal(t, model.LineResult{
+		Catalog:       "flour",
+		Unit:          model.UnitKilogram,
+		Amount:        12300,
+		ShippedAmount: 12500,
+		Contents:      map[model.Quantity]int64{500: 1, 2000: 1, 5000: 2},
+		Shipment: model.Shipment{
+			Packs:        map[model.PackSize]int64{500: 1, 2000: 1, 5000: 2},
+			ShippedItems: 12500,
+			Overshipment: 200,
+			PackCount:    4,
+		},
+	}, result.Lines[0])
+	require.Equal(t, &model.ShipmentTotals{ShippedItems: 500, Overshipment: 249, PackCount: 5}, result.Totals)
+
+	contents, err := json.Marshal(result.Lines[0].Contents)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"0.5":1,"2":1,"5":2}`, string(contents))
+
+	// Test that an amount is matched exactly
+	result, err = service.Calculate(context.Background(), model.CalculationRequest{
+		Lines: []model.OrderLine{{Catalog: "flour", Amount: 12500}},
+	})
+	require.NoError(t, err)
+	require.Equal(t, model.Quantity(12500), result.Lines[0].ShippedAmount)
+	require.Equal(t, int64(0), result.Lines[0].Overshipment)
+
+	// Test lines that do not match the unit of their catalog
+	_, err = service.Calculate(context.Background(), model.CalculationRequest{
+		Lines: []model.OrderLine{{Catalog: "flour", Quantity: 12}},
+	})
+	require.Equal(t, model.ErrorCodeInvalidUnit, model.ErrorCodeOf(err))
+
+	_, err = service.Calculate(context.Background(), model.CalculationRequest{
+		Lines: []model.OrderLine{{Amount: 1500}},
+	})
+	require.Equal(t, model.ErrorCodeInvalidUnit, model.ErrorCodeOf(err))
+
+	// Test amounts with too many decimal places
+	err = json.Unmarshal([]byte(`{"lines":[{"catalog":"flour","amount":12.3456}]}`), &req)
+	require.Error(t, err)
+}
+
+func TestPacksServiceImpl_CalculateUndership(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := NewMockPacksRepository(ctrl)
+	mockRepo.EXPECT().GetPacks().Return(model.Packs{{Size: 250}, {Size: 500}, {Size: 1000}}).AnyTimes()
+	mockRepo.EXPECT().GetCatalog("bolts").Return(model.Catalog{
+		ID:    "bolts",
+		Name:  "Bolts",
+		Packs: model.Packs{{Size: 100, Cost: 2}, {Size: 400, Cost: 5}},
+	}, nil).AnyTimes()
+	mockRepo.EXPECT().AddCalculation(gomock.Any()).Return(nil).AnyTimes()
+
+	service := NewPacksService(mockRepo)
+
+	// Test with a single-line order
+	result, err := service.Calculate(context.Background(), model.CalculationRequest{
+		OrderSize: 1499,
+		Mode:      model.ShipmentModeUndership,
+	})
+	require.NoError(t, err)
+	require.Equal(t, map[model.PackSize]int64{1000: 1, 250: 1}, result.Packs)
+	require.Equal(t, int64(249), result.Backorder)
+
+	// Test with an order smaller than the smallest pack
+	result, err = service.Calculate(context.Background(), model.CalculationRequest{
+		OrderSize: 100,
+		Mode:      model.ShipmentModeUndership,
+	})
+	require.NoError(t, err)
+	require.Empty(t, result.Packs)
+	require.Equal(t, int64(100), result.Backorder)
+
+	// Test with a multi-line order
+	result, err = service.Calculate(context.Background(), model.CalculationRequest{
+		Lines: []model.OrderLine{
+			{Catalog: "bolts", Quantity: 450},
+			{Quantity: 251},
+		},
+		Mode: model.ShipmentModeUndership,
+	})
+	require.NoError(t, err)
+	require.Equal(t, model.Shipment{
+		Packs:        map[model.PackSize]int64{400: 1},
+		ShippedItems: 400,
+		Backorder:    50,
+		PackCount:    1,
+		Cost:         5,
+	}, result.Lines[0].Shipment)
+	require.Equal(t, &model.ShipmentTotals{ShippedItems: 650, Backorder: 51, PackCount: 2, Cost: 5}, result.Totals)
+
+	// Test with an unknown mode
+	_, err = service.Calculate(context.Background(), model.CalculationRequest{OrderSize: 100, Mode: "exact"})
+	require.Equal(t, model.ErrorCodeInvalidShipmentMode, model.ErrorCodeOf(err))
+}
+
+func TestPacksServiceImpl_CalculateTolerance(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := NewMockPacksRepository(ctrl)
+	mockRepo.EXPECT().GetPacks().Return(model.Packs{{Size: 250}, {Size: 500}, {Size: 1000}}).AnyTimes()
+	mockRepo.EXPECT().GetCatalog("bolts").Return(model.Catalog{
+		ID:        "bolts",
+		Name:      "Bolts",
+		Packs:     model.Packs{{Size: 100}, {Size: 400}},
+		Tolerance: &model.OvershipmentTolerance{MaxItems: 20, Reject: true},
+	}, nil).AnyTimes()
+	mockRepo.EXPECT().AddCalculation(gomock.Any()).Return(nil).AnyTimes()
+
+	service := NewPacksService(mockRepo, WithOvershipmentTolerance(model.OvershipmentTolerance{MaxPercent: 50}))
+
+	// Test that an overshipment within the tolerance is not flagged
+	result, err := service.CalculatePacks(context.Background(), 500)
+	require.NoError(t, err)
+	require.False(t, result.ToleranceExceeded)
+
+	// Test that an overshipment above the global tolerance is flagged
+	result, err = service.CalculatePacks(context.Background(), 251)
+	require.NoError(t, err)
+	require.True(t, result.ToleranceExceeded)
+	require.Equal(t, map[model.PackSize]int64{500: 1}, result.Packs)
+
+	// Test that the lines take the tolerance of their catalog
+	result, err = service.Calculate(context.Background(), model.CalculationRequest{
+		Lines: []model.OrderLine{
+			{Catalog: "bolts", Quantity: 390},
+			{Quantity: 251},
+		},
+	})
+	require.NoError(t, err)
+	require.True(t, result.ToleranceExceeded)
+	require.False(t, result.Lines[0].ToleranceExceeded)
+	require.True(t, result.Lines[1].ToleranceExceeded)
+
+	// Test that a rejecting tolerance rejects the calculation
+	_, err = service.Calculate(context.Background(), model.CalculationRequest{
+		Lines: []model.OrderLine{{Catalog: "bolts", Quantity: 350}},
+	})
+	require.Equal(t, model.ErrorCodeToleranceExceeded, model.ErrorCodeOf(err))
+
+	// Test that the undership mode never exceeds the tolerance
+	result, err = service.Calculate(context.Background(), model.CalculationRequest{
+		OrderSize: 251,
+		Mode:      model.ShipmentModeUndership,
+	})
+	require.NoError(t, err)
+	require.False(t, result.ToleranceExceeded)
+}
+
+func TestPacksServiceImpl_CalculateSplit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := NewMockPacksRepository(ctrl)
+	mockRepo.EXPECT().GetPacks().Return(model.Packs{
+		{Size: 250, Weight: 3},
+		{Size: 500, Weight: 5},
+		{Size: 1000, Weight: 9},
+		{Size: 5000, Weight: 40},
+	}).AnyTimes()
+	mockRepo.EXPECT().AddCalculation(gomock.Any()).Return(nil).AnyTimes()
+
+	service := NewPacksService(mockRepo)
+
+	// Test that the packs larger than a shipment are not used
+	result, err := service.Calculate(context.Background(), model.CalculationRequest{
+		OrderSize: 12001,
+		Split:     &model.ShipmentSplit{MaxItems: 2500},
+	})
+	require.NoError(t, err)
+	require.Equal(t, map[model.PackSize]int64{1000: 12, 250: 1}, result.Packs)
+	require.Equal(t, &model.SplitPlan{
+		Count: 6,
+		Groups: []model.ShipmentGroup{
+			{Count: 1, Packs: map[model.PackSize]int64{1000: 2, 250: 1}, Items: 2250, Weight: 21},
+			{Count: 5, Packs: map[model.PackSize]int64{1000: 2}, Items: 2000, Weight: 18},
+		},
+	}, result.Split)
+
+	// Test a split by weight
+	result, err = service.Calculate(context.Background(), model.CalculationRequest{
+		OrderSize: 12001,
+		Split:     &model.ShipmentSplit{MaxWeight: 50},
+	})
+	require.NoError(t, err)
+	require.Equal(t, &model.SplitPlan{
+		Count: 3,
+		Groups: []model.ShipmentGroup{
+			{Count: 2, Packs: map[model.PackSize]int64{5000: 1, 1000: 1}, Items: 6000, Weight: 49},
+			{Count: 1, Packs: map[model.PackSize]int64{250: 1}, Items: 250, Weight: 3},
+		},
+	}, result.Split)
+
+	// Test that the alternative split into the fewest shipments is chosen
+	packs, plan, err := chooseSplit(
+		model.ShipmentSplit{MaxWeight: 11},
+		model.Packs{{Size: 6, Weight: 10}, {Size: 4, Weight: 6}, {Size: 2, Weight: 1}},
+		[]map[model.PackSize]int64{{4: 2}, {6: 1, 2: 1}},
+	)
+	require.NoError(t, err)
+	require.Equal(t, map[model.PackSize]int64{6: 1, 2: 1}, packs)
+	require.Equal(t, int64(1), plan.Count)
+
+	// Test invalid splits
+	_, err = service.Calculate(context.Background(), model.CalculationRequest{
+		OrderSize: 100,
+		Split:     &model.ShipmentSplit{},
+	})
+	require.Equal(t, model.ErrorCodeInvalidSplit, model.ErrorCodeOf(err))
+
+	_, err = service.Calculate(context.Background(), model.CalculationRequest{
+		Lines: []model.OrderLine{{Quantity: 100}},
+		Split: &model.ShipmentSplit{MaxItems: 1000},
+	})
+	require.Equal(t, model.ErrorCodeInvalidSplit, model.ErrorCodeOf(err))
+
+	_, err = service.Calculate(context.Background(), model.CalculationRequest{
+		OrderSize: 100,
+		Split:     &model.ShipmentSplit{MaxItems: 100},
+	})
+	require.Equal(t, model.ErrorCodePackDoesNotFitShipment, model.ErrorCodeOf(err))
+}
+
+func TestPacksServiceImpl_CalculateWarehouses(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := NewMockPacksRepository(ctrl)
+	mockRepo.EXPECT().GetWarehouses().Return([]model.Warehouse{
+		{ID: "east", Stock: []model.StockItem{{Size: 250, Quantity: 4}, {Size: 1000, Quantity: 1}}},
+		{ID: "west", Stock: []model.StockItem{{Size: 500, Quantity: 2}}},
+	}).AnyTimes()
+	mockRepo.EXPECT().AddCalculation(gomock.Any()).Return(nil).AnyTimes()
+
+	service := NewPacksService(mockRepo)
+
+	// Test that a single warehouse is preferred even with more packs
+	result, err := service.Calculate(context.Background(), model.CalculationRequest{OrderSize: 1500, Warehouses: true})
+	require.NoError(t, err)
+	require.Equal(t, map[model.PackSize]int64{1000: 1, 250: 2}, result.Packs)
+	require.Equal(t, []model.WarehouseAllocation{
+		{Warehouse: "east", Packs: map[model.PackSize]int64{1000: 1, 250: 2}},
+	}, result.Allocations)
+
+	// Test that the order is fulfilled from several warehouses if no single one can fulfil it
+	result, err = service.Calculate(context.Background(), model.CalculationRequest{OrderSize: 2500, Warehouses: true})
+	require.NoError(t, err)
+	require.Equal(t, map[model.PackSize]int64{1000: 1, 500: 2, 250: 2}, result.Packs)
+	require.Equal(t, []model.WarehouseAllocation{
+		{Warehouse: "east", Packs: map[model.PackSize]int64{1000: 1, 250: 2}},
+		{Warehouse: "west", Packs: map[model.PackSize]int64{500: 2}},
+	}, result.Allocations)
+
+	// Test an order larger than the stock of all the warehouses
+	_, err = service.Calculate(context.Background(), model.CalculationRequest{OrderSize: 3001, Warehouses: true})
+	require.Equal(t, model.ErrorCodeInsufficientStock, model.ErrorCodeOf(err))
+
+	// Test that a multi-line order cannot be fulfilled from the warehouses
+	_, err = service.Calculate(context.Background(), model.CalculationRequest{
+		Lines:      []model.OrderLine{{Quantity: 100}},
+		Warehouses: true,
+	})
+	require.Equal(t, model.ErrorCodeInvalidRequest, model.ErrorCodeOf(err))
+}
+
+func TestPacksServiceImpl_CalculateShippingCost(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := NewMockPacksRepository(ctrl)
+	mockRepo.EXPECT().GetPacks().Return(model.Packs{
+		{Size: 100, Cost: 5, Weight: 100},
+		{Size: 200, Cost: 8, Weight: 250},
+		{Size: 300, Cost: 12, Weight: 280},
+	}).AnyTimes()
+	mockRepo.EXPECT().AddCalculation(gomock.Any()).Return(nil).AnyTimes()
+
+	service := NewPacksService(mockRepo, WithRateTables([]model.RateTable{
+		{Carrier: "post", Bands: []model.RateBand{{MaxWeight: 400, Price: 10}, {MaxWeight: 1000, Price: 20}}},
+		{Carrier: "courier", Bands: []model.RateBand{{Price: 25}}},
+	}))
+
+	// Test that the combination with the lowest landed cost is chosen although its packs cost more
+	result, err := service.Calculate(context.Background(), model.CalculationRequest{OrderSize: 400, ShippingCost: true})
+	require.NoError(t, err)
+	require.Equal(t, map[model.PackSize]int64{100: 1, 300: 1}, result.Packs)
+	require.Equal(t, &model.ShippingQuote{
+		Carrier:      "post",
+		Weight:       380,
+		PackCost:     17,
+		ShippingCost: 10,
+		LandedCost:   27,
+	}, result.Shipping)
+
+	// Test that the shipping cost is only supported for single-line orders
+	_, err = service.Calculate(context.Background(), model.CalculationRequest{
+		Lines:        []model.OrderLine{{Quantity: 400}},
+		ShippingCost: true,
+	})
+	require.Equal(t, model.ErrorCodeInvalidRequest, model.ErrorCodeOf(err))
+
+	// Test packs too heavy for every carrier
+	service = NewPacksService(mockRepo, WithRateTables([]model.RateTable{
+		{Carrier: "post", Bands: []model.RateBand{{MaxWeight: 300, Price: 10}}},
+	}))
+	_, err = service.Calculate(context.Background(), model.CalculationRequest{OrderSize: 400, ShippingCost: true})
+	require.Equal(t, model.ErrorCodeNoRateBand, model.ErrorCodeOf(err))
+
+	// Test without rate tables
+	service = NewPacksService(mockRepo)
+	_, err = service.Calculate(context.Background(), model.CalculationRequest{OrderSize: 400, ShippingCost: true})
+	require.Equal(t, model.ErrorCodeNoRateTables, model.ErrorCodeOf(err))
+}
+
+func TestReadRateTables(t *testing.T) {
+	dir := t.TempDir()
+
+	path := filepath.Join(dir, "rates.json")
+	require.NoError(t, os.WriteFile(path, []byte(`[{"carrier":"post","bands":[{"maxWeight":1000,"price":500}]}]`), 0o600))
+
+	tables, err := ReadRateTables(path)
+	require.NoError(t, err)
+	require.Equal(t, []model.RateTable{
+		{Carrier: "post", Bands: []model.RateBand{{MaxWeight: 1000, Price: 500}}},
+	}, tables)
+
+	// Test invalid rate tables
+	for _, data := range []string{
+		`{"carrier":"post"}`,
+		`[{"carrier":"post","bands":[]}]`,
+		`[{"carrier":"","bands":[{"price":1}]}]`,
+		`[{"carrier":"post","bands":[{"price":-1}]}]`,
+		`[{"carrier":"post","bands":[{"price":1}]},{"carrier":"post","bands":[{"price":2}]}]`,
+	} {
+		require.NoError(t, os.WriteFile(path, []byte(data), 0o600))
+
+		_, err := ReadRateTables(path)
+		require.Equal(t, model.ErrorCodeInvalidRateTable, model.ErrorCodeOf(err), data)
+	}
+
+	// Test a missing file
+	_, err = ReadRateTables(filepath.Join(dir, "missing.json"))
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestPacksServiceImpl_Warehouses(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := NewMockPacksRepository(ctrl)
+	service := NewPacksService(mockRepo)
+
+	// Test saving a valid warehouse
+	east := model.Warehouse{ID: "east", Stock: []model.StockItem{{Size: 250, Quantity: 4}}}
+	mockRepo.EXPECT().SaveWarehouse(east).Return(nil)
+	require.NoError(t, service.SaveWarehouse(east))
+
+	// Test saving invalid warehouses
+	err := service.SaveWarehouse(model.Warehouse{Stock: []model.StockItem{{Size: 250, Quantity: 4}}})
+	require.Equal(t, model.ErrorCodeInvalidWarehouse, model.ErrorCodeOf(err))
+
+	err = service.SaveWarehouse(model.Warehouse{ID: "west", Stock: []model.StockItem{{Size: 250, Quantity: -1}}})
+	require.Equal(t, model.ErrorCodeInvalidWarehouse, model.ErrorCodeOf(err))
+
+	err = service.SaveWarehouse(model.Warehouse{
+		ID:    "west",
+		Stock: []model.StockItem{{Size: 250, Quantity: 1}, {Size: 250, Quantity: 2}},
+	})
+	require.Equal(t, model.ErrorCodeInvalidWarehouse, model.ErrorCodeOf(err))
+
+	// Test removing a warehouse
+	mockRepo.EXPECT().RemoveWarehouse("east").Return(nil)
+	require.NoError(t, service.RemoveWarehouse("east"))
+}
+
+func TestPacksServiceImpl_Catalogs(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := NewMockPacksRepository(ctrl)
+	mockRepo.EXPECT().GetPacks().Return(model.Packs{{Size: 250}}).AnyTimes()
+
+	service := NewPacksService(mockRepo)
+
+	// Test that the default catalog comes first
+	bolts := model.Catalog{ID: "bolts", Packs: model.Packs{{Size: 100}}}
+	mockRepo.EXPECT().GetCatalogs().Return([]model.Catalog{bolts})
+
+	require.Equal(t, []model.Catalog{
+		{ID: model.DefaultCatalogID, Name: "Default", Packs: model.Packs{{Size: 250}}},
+		bolts,
+	}, service.GetCatalogs())
+
+	// Test saving a valid catalog
+	mockRepo.EXPECT().SaveCatalog(bolts).Return(nil)
+	require.NoError(t, service.SaveCatalog(bolts))
+
+	// Test saving invalid catalogs
+	err := service.SaveCatalog(model.Catalog{ID: model.DefaultCatalogID, Packs: model.Packs{{Size: 1}}})
+	require.Equal(t, model.ErrorCodeInvalidCatalog, model.ErrorCodeOf(err))
+
+	err = service.SaveCatalog(model.Catalog{ID: "nuts", Packs: model.Packs{{Size: -1}}})
+	require.Equal(t, model.ErrorCodeInvalidPackSize, model.ErrorCodeOf(err))
+
+	err = service.SaveCatalog(model.Catalog{
+		ID:        "nuts",
+		Packs:     model.Packs{{Size: 1}},
+		Tolerance: &model.OvershipmentTolerance{MaxPercent: -5},
+	})
+	require.Equal(t, model.ErrorCodeInvalidTolerance, model.ErrorCodeOf(err))
+
+	// Test that the pack sizes of a measured catalog are its contents in thousandths of the unit
+	mockRepo.EXPECT().SaveCatalog(model.Catalog{
+		ID:    "flour",
+		Unit:  model.UnitKilogram,
+		Packs: model.Packs{{Size: 500, Content: 500}, {Size: 2000, Content: 2000}},
+	}).Return(nil)
+	require.NoError(t, service.SaveCatalog(model.Catalog{
+		ID:    "flour",
+		Unit:  model.UnitKilogram,
+		Packs: model.Packs{{Content: 500}, {Content: 2000}},
+	}))
+
+	err = service.SaveCatalog(model.Catalog{ID: "flour", Unit: model.UnitKilogram, Packs: model.Packs{{Size: 500}}})
+	require.Equal(t, model.ErrorCodeInvalidUnit, model.ErrorCodeOf(err))
+
+	err = service.SaveCatalog(model.Catalog{ID: "nuts", Packs: model.Packs{{Size: 1, Content: 1000}}})
+	require.Equal(t, model.ErrorCodeInvalidUnit, model.ErrorCodeOf(err))
+
+	err = service.SaveCatalog(model.Catalog{ID: "nuts", Unit: "lb", Packs: model.Packs{{Size: 1}}})
+	require.Equal(t, model.ErrorCodeInvalidUnit, model.ErrorCodeOf(err))
+
+	// Test removing catalogs
+	mockRepo.EXPECT().RemoveCatalog("bolts").Return(nil)
+	require.NoError(t, service.RemoveCatalog("bolts"))
+
+	err = service.RemoveCatalog(model.DefaultCatalogID)
+	require.Equal(t, model.ErrorCodeInvalidCatalog, model.ErrorCodeOf(err))
+}
+
+func TestPacksServiceImpl_Packaging(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := NewMockPacksRepository(ctrl)
+	mockRepo.EXPECT().GetPacks().Return(model.Packs{{Size: 2}, {Size: 3}, {Size: 4}, {Size: 5}}).AnyTimes()
+	mockRepo.EXPECT().AddCalculation(gomock.Any()).Return(nil).AnyTimes()
+
+	service := NewPacksService(mockRepo)
+
+	// Test that the packaging plan needs a packaging hierarchy
+	mockRepo.EXPECT().GetPackaging().Return(model.Packaging{})
+	_, err := service.Calculate(context.Background(), model.CalculationRequest{OrderSize: 12, Packaging: true})
+	require.Equal(t, model.ErrorCodeInvalidPackaging, model.ErrorCodeOf(err))
+
+	// Test that among the combinations of 3 packs for 12 items the one needing the fewest cartons is chosen
+	packaging := model.Packaging{Levels: []model.ContainerLevel{
+		{Name: "carton", Capacity: 7},
+		{Name: "pallet", Capacity: 1},
+	}}
+	mockRepo.EXPECT().GetPackaging().Return(packaging).AnyTimes()
+
+	result, err := service.Calculate(context.Background(), model.CalculationRequest{OrderSize: 12, Packaging: true})
+	require.NoError(t, err)
+
+	_, count, _ := getAmountOfItemsInPacks(result.Packs)
+	require.Equal(t, int64(3), count)
+	require.NotEqual(t, map[model.PackSize]int64{4: 3}, result.Packs)
+	require.Equal(t, int64(2), result.Packaging.Levels[0].Count)
+	require.Equal(t, &model.PackagingPlan{Levels: []model.ContainerPlan{
+		result.Packaging.Levels[0],
+		{Name: "pallet", Count: 2, Groups: []model.ContainerGroup{{Count: 2, Contents: 1}}},
+	}}, result.Packaging)
+
+	// Test that repeated cartons are grouped
+	plan, err := planPackaging(model.Packaging{Levels: []model.ContainerLevel{
+		{Name: "carton", Capacity: 10},
+		{Name: "pallet", Capacity: 4},
+	}}, map[model.PackSize]int64{4: 10, 2: 3})
+	require.NoError(t, err)
+	require.Equal(t, &model.PackagingPlan{Levels: []model.ContainerPlan{
+		{Name: "carton", Count: 5, Groups: []model.ContainerGroup{
+			{Count: 3, Packs: map[model.PackSize]int64{4: 2, 2: 1}},
+			{Count: 2, Packs: map[model.PackSize]int64{4: 2}},
+		}},
+		{Name: "pallet", Count: 2, Groups: []model.ContainerGroup{{Count: 1, Contents: 4}, {Count: 1, Contents: 1}}},
+	}}, plan)
+
+	// Test a pack that does not fit into a carton
+	_, err = planPackaging(packaging, map[model.PackSize]int64{8: 1})
+	require.Equal(t, model.ErrorCodePackDoesNotFitContainer, model.ErrorCodeOf(err))
+
+	// Test saving packaging hierarchies
+	mockRepo.EXPECT().SavePackaging(packaging).Return(nil)
+	require.NoError(t, service.SavePackaging(packaging))
+
+	err = service.SavePackaging(model.Packaging{Levels: []model.ContainerLevel{{Name: "carton"}}})
+	require.Equal(t, model.ErrorCodeInvalidPackaging, model.ErrorCodeOf(err))
+}
+
+// stubResolver resolves the hosts to their addresses, failing for unknown hosts
+type stubResolver map[string][]netip.Addr
+
+func (r stubResolver) LookupNetIP(_ context.Context, _, host string) ([]netip.Addr, error) {
+	addrs, ok := r[host]
+	if !ok {
+		return nil, errors.New("no such host")
+	}
+
+	return addrs, nil
+}
+
+// recordingNotifier records the webhook events it is notified of
+type recordingNotifier struct {
+	events []model.WebhookEvent
+}
+
+func (n *recordingNotifier) Notify(event model.WebhookEvent) {
+	n.events = append(n.events, event)
+}
+
+func TestPacksServiceImpl_Webhooks(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := NewMockPacksRepository(ctrl)
+	service := NewPacksService(mockRepo, WithWebhookAddressPolicy(netguard.Policy{
+		Resolver: stubResolver{
+			"erp.example.com":      {netip.MustParseAddr("93.184.216.34")},
+			"internal.example.com": {netip.MustParseAddr("10.0.0.5")},
+		},
+	}))
+
+	// Test saving a valid webhook
+	erp := model.Webhook{
+		ID:     "erp",
+		URL:    "https://erp.example.com/hooks/packs",
+		Events: []model.WebhookEventType{model.WebhookEventPacksAdded, model.WebhookEventCalculationCompleted},
+		Secret: "s3cret",
+	}
+	mockRepo.EXPECT().SaveWebhook(erp).Return(nil)
+	require.NoError(t, service.SaveWebhook(erp))
+
+	// Test saving invalid webhooks
+	invalid := []model.Webhook{
+		{URL: erp.URL, Events: erp.Events, Secret: erp.Secret},
+		{ID: "erp", URL: "erp.example.com/hooks", Events: erp.Events, Secret: erp.Secret},
+		{ID: "erp", URL: "ftp://erp.example.com/hooks", Events: erp.Events, Secret: erp.Secret},
+		{ID: "erp", URL: erp.URL, Events: erp.Events},
+		{ID: "erp", URL: erp.URL, Secret: erp.Secret},
+		{ID: "erp", URL: erp.URL, Events: []model.WebhookEventType{"packs.changed"}, Secret: erp.Secret},
+		{
+			ID:     "erp",
+			URL:    erp.URL,
+			Events: []model.WebhookEventType{model.WebhookEventPacksAdded, model.WebhookEventPacksAdded},
+			Secret: erp.Secret,
+		}, // Test that webhooks must not reach the server itself or its internal network
+		{ID: "erp", URL: "http://127.0.0.1:8080/api/packs", Events: erp.Events, Secret: erp.Secret},
+		{ID: "erp", URL: "http://[::1]/hooks", Events: erp.Events, Secret: erp.Secret},
+		{ID: "erp", URL: "http://169.254.169.254/latest/meta-data", Events: erp.Events, Secret: erp.Secret},
+		{ID: "erp", URL: "http://0.0.0.0/hooks", Events: erp.Events, Secret: erp.Secret},
+		{ID: "erp", URL: "https://internal.example.com/hooks", Events: erp.Events, Secret: erp.Secret},
+		{ID: "erp", URL: "https://unknown.example.com/hooks", Events: erp.Events, Secret: erp.Secret},
+	}
+	for _, webhook := range invalid {
+		err := service.SaveWebhook(webhook)
+		require.Equal(t, model.ErrorCodeInvalidWebhook, model.ErrorCodeOf(err), webhook)
+	}
+
+	// Test that the secrets are not returned
+	mockRepo.EXPECT().GetWebhooks().Return([]model.Webhook{erp})
+	require.Equal(t, []model.Webhook{{ID: erp.ID, URL: erp.URL, Events: erp.Events}}, service.GetWebhooks())
+
+	// Test removing a webhook
+	mockRepo.EXPECT().RemoveWebhook("erp").Return(nil)
+	require.NoError(t, service.RemoveWebhook("erp"))
+}
+
+func TestPacksServiceImpl_WebhookNotifier(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := NewMockPacksRepository(ctrl)
+	notifier := &recordingNotifier{}
+	service := NewPacksService(mockRepo, WithWebhookNotifier(notifier))
+
+	// Test that a change of the packs is notified with the packs after it, read once for the subscribers too
+	events, unsubscribe := service.SubscribePacks()
+	defer unsubscribe()
+
+	mockRepo.EXPECT().RemovePack(model.PackSize(250)).Return(nil)
+	mockRepo.EXPECT().GetPacks().Return(model.Packs{{Size: 500}}).Times(1)
+	require.NoError(t, service.RemovePack(250))
+	require.Equal(t, model.PacksEvent{Type: model.PacksEventRemoved, Packs: model.Packs{{Size: 500}}}, <-events)
+
+	// Test that a failed change is not notified
+	mockRepo.EXPECT().RemovePack(model.PackSize(42)).Return(model.NewError(model.ErrorCodePackNotFound, "not found"))
+	require.Error(t, service.RemovePack(42))
+
+	// Test that a calculation is notified with its record
+	mockRepo.EXPECT().GetPacks().Return(model.Packs{{Size: 500}})
+	mockRepo.EXPECT().AddCalculation(gomock.Any()).Return(nil)
+	result, err := service.CalculatePacks(context.Background(), 501)
+	require.NoError(t, err)
+
+	require.Len(t, notifier.events, 2)
+	require.Equal(t, model.WebhookEventPacksRemoved, notifier.events[0].Type)
+	require.Equal(t, model.PacksEvent{Type: model.PacksEventRemoved, Packs: model.Packs{{Size: 500}}},
+		notifier.events[0].Data)
+	require.False(t, notifier.events[0].CreatedAt.IsZero())
+
+	require.Equal(t, model.WebhookEventCalculationCompleted, notifier.events[1].Type)
+	record, ok := notifier.events[1].Data.(model.CalculationRecord)
+	require.True(t, ok)
+	require.Equal(t, result, record.CalculationResponse)
+}
@@ -0,0 +1,170 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/alishercodecrafter/orderpackscalculator/internal/model"
+	"github.com/alishercodecrafter/orderpackscalculator/pkg/packing"
+)
+
+// RateTables returns the carrier rate tables the shipping cost of orders is priced with
+func (s *PacksServiceImpl) RateTables() []model.RateTable {
+	return s.rateTables
+}
+
+// ReadRateTables reads a JSON array of carrier rate tables from a file and validates them
+func ReadRateTables(path string) ([]model.RateTable, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var tables []model.RateTable
+	if err := json.Unmarshal(data, &tables); err != nil {
+		return nil, model.NewError(
+			model.ErrorCodeInvalidRateTable,
+			fmt.Sprintf("rate tables in %s cannot be parsed: %v", path, err),
+		)
+	}
+
+	if err := validateRateTables(tables); err != nil {
+		return nil, err
+	}
+
+	return tables, nil
+}
+
+// validateRateTables checks that every rate table has a unique carrier and bands without negative values
+func validateRateTables(tables []model.RateTable) error {
+	carriers := make(map[string]bool, len(tables))
+	for _, table := range tables {
+		if table.Carrier == "" || carriers[table.Carrier] {
+			return model.NewError(
+				model.ErrorCodeInvalidRateTable,
+				fmt.Sprintf("carrier %q of a rate table must not be empty or repeated", table.Carrier),
+			)
+		}
+		carriers[table.Carrier] = true
+
+		if len(table.Bands) == 0 {
+			return model.NewError(
+				model.ErrorCodeInvalidRateTable,
+				fmt.Sprintf("rate table of carrier %q has no bands", table.Carrier),
+			)
+		}
+
+		for _, band := range table.Bands {
+			if band.MaxWeight < 0 || band.MaxVolume < 0 || band.Price < 0 {
+				return model.NewError(
+					model.ErrorCodeInvalidRateTable,
+					fmt.Sprintf("bands of the rate table of carrier %q must not be negative", table.Carrier),
+				)
+			}
+		}
+	}
+
+	return nil
+}
+
+// chooseLandedCost chooses the combination of packs among equally good alternatives with the lowest landed cost,
+// the first one on a tie, and returns it with its shipping quote
+func chooseLandedCost(
+	tables []model.RateTable,
+	packList model.Packs,
+	alternatives []map[model.PackSize]int64,
+) (map[model.PackSize]int64, *model.ShippingQuote, error) {
+	var best map[model.PackSize]int64
+	var bestQuote *model.ShippingQuote
+	for _, alternative := range alternatives {
+		quote, err := quoteShipping(tables, packList, alternative)
+		if model.ErrorCodeOf(err) == model.ErrorCodeNoRateBand {
+			continue
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if bestQuote == nil || quote.LandedCost < bestQuote.LandedCost {
+			best, bestQuote = alternative, quote
+		}
+	}
+
+	if bestQuote == nil {
+		return nil, nil, model.NewError(
+			model.ErrorCodeNoRateBand,
+			"packs of the order are too heavy or too large for every carrier",
+		)
+	}
+
+	return best, bestQuote, nil
+}
+
+// quoteShipping prices packs with the cheapest carrier band they fit into, taking the pack costs, weights
+// and volumes from the pack list
+func quoteShipping(
+	tables []model.RateTable,
+	packList model.Packs,
+	packs map[model.PackSize]int64,
+) (*model.ShippingQuote, error) {
+	quote := &model.ShippingQuote{}
+	for _, pack := range packList {
+		count := packs[pack.Size]
+
+		weight, ok := packing.CheckedMul(pack.Weight, count)
+		if !ok {
+			return nil, model.ErrArithmeticOverflow
+		}
+
+		volume, ok := packing.CheckedMul(pack.Volume, count)
+		if !ok {
+			return nil, model.ErrArithmeticOverflow
+		}
+
+		cost, ok := packing.CheckedMul(pack.Cost, count)
+		if !ok {
+			return nil, model.ErrArithmeticOverflow
+		}
+
+		if quote.Weight, ok = packing.CheckedAdd(quote.Weight, weight); !ok {
+			return nil, model.ErrArithmeticOverflow
+		}
+
+		if quote.Volume, ok = packing.CheckedAdd(quote.Volume, volume); !ok {
+			return nil, model.ErrArithmeticOverflow
+		}
+
+		if quote.PackCost, ok = packing.CheckedAdd(quote.PackCost, cost); !ok {
+			return nil, model.ErrArithmeticOverflow
+		}
+	}
+
+	found := false
+	for _, table := range tables {
+		for _, band := range table.Bands {
+			if (band.MaxWeight > 0 && quote.Weight > band.MaxWeight) ||
+				(band.MaxVolume > 0 && quote.Volume > band.MaxVolume) {
+				continue
+			}
+
+			if !found || band.Price < quote.ShippingCost {
+				found, quote.Carrier, quote.ShippingCost = true, table.Carrier, band.Price
+			}
+		}
+	}
+
+	if !found {
+		return nil, model.NewError(
+			model.ErrorCodeNoRateBand,
+			fmt.Sprintf("packs of weight %d and volume %d fit into no carrier rate band", quote.Weight, quote.Volume),
+		)
+	}
+
+	var ok bool
+	if quote.LandedCost, ok = packing.CheckedAdd(quote.PackCost, quote.ShippingCost); !ok {
+		return nil, model.ErrArithmeticOverflow
+	}
+
+	return quote, nil
+}
@@ -0,0 +1,60 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/alishercodecrafter/orderpackscalculator/internal/model"
+	"github.com/alishercodecrafter/orderpackscalculator/pkg/packing"
+)
+
+// calculation holds the state shared by all steps of a single pack calculation
+type calculation struct {
+	// budget bounds the work of all the solvers of the calculation
+	budget *packing.Budget
+}
+
+// step accounts for n search steps and returns an error when the calculation must stop
+func (c *calculation) step(n int64) error {
+	return solverError(c.budget.Step(n))
+}
+
+// newSolver prepares a solver of the packing library for orders up to maxOrderSize
+// with the packs sorted in descending order by size, see packing.Solver for the rules it follows
+func (c *calculation) newSolver(packList model.Packs, maxOrderSize int64, mode model.ShipmentMode) (*packing.Solver, error) {
+	packs := make([]packing.Pack, len(packList))
+	for i, pack := range packList {
+		packs[i] = packing.Pack{Size: pack.Size, MinCount: pack.MinCount, MaxCount: pack.MaxCount}
+	}
+
+	solver, err := packing.NewSolver(packs, maxOrderSize, packing.WithMode(mode), packing.WithBudget(c.budget))
+	if err != nil {
+		return nil, solverError(err)
+	}
+
+	return solver, nil
+}
+
+// solverError maps an error of the packing library to the error of the service with the same meaning
+func solverError(err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, packing.ErrInfeasible):
+		return &model.Error{Code: model.ErrorCodeNoFeasiblePacks, Message: err.Error(), Err: err}
+	case errors.Is(err, packing.ErrOverflow):
+		return model.ErrArithmeticOverflow
+	case errors.Is(err, packing.ErrBudgetExceeded):
+		return model.ErrCalculationBudgetExceeded
+	case errors.Is(err, context.DeadlineExceeded):
+		return model.ErrCalculationTimeout
+	case errors.Is(err, context.Canceled):
+		return &model.Error{
+			Code:    model.ErrorCodeCalculationCancelled,
+			Message: "calculation cancelled",
+			Err:     err,
+		}
+	default:
+		return err
+	}
+}
@@ -0,0 +1,156 @@
+package service
+
+import (
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+
+	"github.com/alishercodecrafter/orderpackscalculator/internal/model"
+	"github.com/alishercodecrafter/orderpackscalculator/pkg/packing"
+)
+
+// validateSplit checks that a shipment split has at least one cap and no negative one
+func validateSplit(split model.ShipmentSplit) error {
+	if split.MaxItems < 0 || split.MaxWeight < 0 || (split.MaxItems == 0 && split.MaxWeight == 0) {
+		return model.NewError(
+			model.ErrorCodeInvalidSplit,
+			"shipment split must have a maximum number of items or a maximum weight and neither can be negative",
+		)
+	}
+
+	return nil
+}
+
+// fittingPacks returns the packs that fit into a single shipment of a split.
+// It returns an error if no pack fits or if a pack that every combination must hold does not fit.
+func fittingPacks(split model.ShipmentSplit, packs model.Packs) (model.Packs, error) {
+	result := make(model.Packs, 0, len(packs))
+	for _, pack := range packs {
+		if packFitsShipment(split, pack) {
+			result = append(result, pack)
+
+			continue
+		}
+
+		if pack.MinCount > 0 {
+			return nil, model.NewError(
+				model.ErrorCodePackDoesNotFitShipment,
+				fmt.Sprintf("pack size %d has a minimum count but does not fit into a shipment", pack.Size),
+			)
+		}
+	}
+
+	if len(result) == 0 {
+		return nil, model.NewError(model.ErrorCodePackDoesNotFitShipment, "no pack fits into a shipment")
+	}
+
+	return result, nil
+}
+
+// packFitsShipment reports whether a single pack is within the caps of a shipment
+func packFitsShipment(split model.ShipmentSplit, pack model.Pack) bool {
+	return (split.MaxItems == 0 || int64(pack.Size) <= split.MaxItems) &&
+		(split.MaxWeight == 0 || pack.Weight <= split.MaxWeight)
+}
+
+// chooseSplit chooses the combination of packs among equally good alternatives that is split into
+// the fewest shipments, and returns it with the plan of the shipments
+func chooseSplit(
+	split model.ShipmentSplit,
+	packList model.Packs,
+	alternatives []map[model.PackSize]int64,
+) (map[model.PackSize]int64, *model.SplitPlan, error) {
+	var best map[model.PackSize]int64
+	var bestPlan *model.SplitPlan
+	for _, alternative := range alternatives {
+		plan, err := planSplit(split, packList, alternative)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if bestPlan == nil || plan.Count < bestPlan.Count {
+			best, bestPlan = alternative, plan
+		}
+	}
+
+	return best, bestPlan, nil
+}
+
+// planSplit splits packs into shipments within the caps of a split.
+// Every shipment is filled greedily with the largest packs that still fit, and the same shipment is repeated
+// as long as every pack size in it has enough packs left. The packs must fit into a shipment one by one.
+func planSplit(
+	split model.ShipmentSplit,
+	packList model.Packs,
+	packs map[model.PackSize]int64,
+) (*model.SplitPlan, error) {
+	weights := make(map[model.PackSize]int64, len(packList))
+	for _, pack := range packList {
+		weights[pack.Size] = pack.Weight
+	}
+
+	sizes := slices.Sorted(maps.Keys(packs))
+	slices.Reverse(sizes)
+
+	remaining := maps.Clone(packs)
+	plan := &model.SplitPlan{Groups: []model.ShipmentGroup{}}
+	for {
+		freeItems, freeWeight := split.MaxItems, split.MaxWeight
+		group := model.ShipmentGroup{Packs: make(map[model.PackSize]int64)}
+		for _, size := range sizes {
+			count := remaining[size]
+			if split.MaxItems > 0 {
+				count = min(count, freeItems/int64(size))
+			}
+
+			if weight := weights[size]; split.MaxWeight > 0 && weight > 0 {
+				count = min(count, freeWeight/weight)
+			}
+
+			if count <= 0 {
+				continue
+			}
+
+			items, ok := packing.CheckedMul(int64(size), count)
+			if !ok {
+				return nil, model.ErrArithmeticOverflow
+			}
+
+			weight, ok := packing.CheckedMul(weights[size], count)
+			if !ok {
+				return nil, model.ErrArithmeticOverflow
+			}
+
+			if group.Items, ok = packing.CheckedAdd(group.Items, items); !ok {
+				return nil, model.ErrArithmeticOverflow
+			}
+
+			if group.Weight, ok = packing.CheckedAdd(group.Weight, weight); !ok {
+				return nil, model.ErrArithmeticOverflow
+			}
+
+			group.Packs[size] = count
+			freeItems -= items
+			freeWeight -= weight
+		}
+
+		if len(group.Packs) == 0 {
+			break
+		}
+
+		group.Count = math.MaxInt64
+		for size, count := range group.Packs {
+			group.Count = min(group.Count, remaining[size]/count)
+		}
+
+		for size, count := range group.Packs {
+			remaining[size] -= count * group.Count
+		}
+
+		plan.Count += group.Count
+		plan.Groups = append(plan.Groups, group)
+	}
+
+	return plan, nil
+}
@@ -0,0 +1,52 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/alishercodecrafter/orderpackscalculator/internal/model"
+)
+
+// validateTolerance checks that the bounds of an overshipment tolerance are not negative
+func validateTolerance(tolerance model.OvershipmentTolerance) error {
+	if tolerance.MaxItems < 0 || tolerance.MaxPercent < 0 {
+		return model.NewError(
+			model.ErrorCodeInvalidTolerance,
+			"overshipment tolerance must not be negative",
+		)
+	}
+
+	return nil
+}
+
+// toleranceOf returns the overshipment tolerance of a catalog, the global tolerance if the catalog has none
+func (s *PacksServiceImpl) toleranceOf(catalog model.Catalog) model.OvershipmentTolerance {
+	if catalog.Tolerance != nil {
+		return *catalog.Tolerance
+	}
+
+	return s.tolerance
+}
+
+// checkTolerance reports whether shipping shippedItems for an order exceeds the overshipment tolerance,
+// and returns an error instead if the tolerance rejects such calculations
+func checkTolerance(tolerance model.OvershipmentTolerance, orderSize, shippedItems int64) (bool, error) {
+	overshipment := shippedItems - orderSize
+	if overshipment <= 0 {
+		return false, nil
+	}
+
+	exceeded := (tolerance.MaxItems > 0 && overshipment > tolerance.MaxItems) ||
+		(tolerance.MaxPercent > 0 && float64(overshipment)*100/float64(orderSize) > tolerance.MaxPercent)
+	if exceeded && tolerance.Reject {
+		return false, model.NewError(
+			model.ErrorCodeToleranceExceeded,
+			fmt.Sprintf(
+				"shipping %d items for order size %d exceeds the overshipment tolerance",
+				shippedItems,
+				orderSize,
+			),
+		)
+	}
+
+	return exceeded, nil
+}
@@ -0,0 +1,83 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/alishercodecrafter/orderpackscalculator/internal/model"
+)
+
+// unitPacks checks the unit of measure of a catalog and that its packs match it, and returns a copy of the packs
+// with the sizes of a product measured in kilograms or litres set to their contents in thousandths of the unit
+func unitPacks(unit model.Unit, packs model.Packs) (model.Packs, error) {
+	switch unit {
+	case "", model.UnitItem:
+		for _, pack := range packs {
+			if pack.Content != 0 {
+				return nil, model.NewError(
+					model.ErrorCodeInvalidUnit,
+					fmt.Sprintf("pack size %d of a product counted in items must not have a content", pack.Size),
+				)
+			}
+		}
+
+		return packs, nil
+	case model.UnitKilogram, model.UnitLitre:
+		result := make(model.Packs, len(packs))
+		for i, pack := range packs {
+			if pack.Content <= 0 {
+				return nil, model.NewError(
+					model.ErrorCodeInvalidUnit,
+					fmt.Sprintf("content of a pack of a product measured in %s must be greater than zero", unit),
+				)
+			}
+
+			pack.Size = model.PackSize(pack.Content)
+			result[i] = pack
+		}
+
+		return result, nil
+	default:
+		return nil, model.NewError(
+			model.ErrorCodeInvalidUnit,
+			fmt.Sprintf("unit must be %q, %q or %q", model.UnitItem, model.UnitKilogram, model.UnitLitre),
+		)
+	}
+}
+
+// lineQuantity returns the order size of a line in the unit of its catalog,
+// in thousandths of the unit for a product measured in kilograms or litres
+func lineQuantity(catalog model.Catalog, line model.OrderLine) (int64, error) {
+	if !catalog.Unit.Measured() {
+		if line.Amount != 0 {
+			return 0, model.NewError(
+				model.ErrorCodeInvalidUnit,
+				fmt.Sprintf("order line of catalog %q counted in items must have a quantity instead of an amount", catalog.ID),
+			)
+		}
+
+		return line.Quantity, nil
+	}
+
+	if line.Quantity != 0 {
+		return 0, model.NewError(
+			model.ErrorCodeInvalidUnit,
+			fmt.Sprintf(
+				"order line of catalog %q measured in %s must have an amount instead of a quantity",
+				catalog.ID,
+				catalog.Unit,
+			),
+		)
+	}
+
+	return int64(line.Amount), nil
+}
+
+// packContents returns the counts of packs of a product measured in kilograms or litres by the content of the pack
+func packContents(packs map[model.PackSize]int64) map[model.Quantity]int64 {
+	result := make(map[model.Quantity]int64, len(packs))
+	for size, count := range packs {
+		result[model.Quantity(size)] = count
+	}
+
+	return result
+}
@@ -0,0 +1,201 @@
+package service
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/alishercodecrafter/orderpackscalculator/internal/model"
+	"github.com/alishercodecrafter/orderpackscalculator/pkg/packing"
+)
+
+// GetWarehouses returns all warehouses
+func (s *PacksServiceImpl) GetWarehouses() []model.Warehouse {
+	return s.repo.GetWarehouses()
+}
+
+// SaveWarehouse adds a warehouse or replaces the warehouse with the same ID
+func (s *PacksServiceImpl) SaveWarehouse(warehouse model.Warehouse) error {
+	if warehouse.ID == "" {
+		return model.NewError(model.ErrorCodeInvalidWarehouse, "warehouse ID must not be empty")
+	}
+
+	sizes := make(map[model.PackSize]bool, len(warehouse.Stock))
+	for _, item := range warehouse.Stock {
+		if item.Size <= 0 || item.Quantity < 0 {
+			return model.NewError(
+				model.ErrorCodeInvalidWarehouse,
+				"stock pack size must be greater than zero and its quantity must not be negative",
+			)
+		}
+
+		if sizes[item.Size] {
+			return model.NewError(
+				model.ErrorCodeInvalidWarehouse,
+				fmt.Sprintf("pack size %d is listed twice in the stock", item.Size),
+			)
+		}
+		sizes[item.Size] = true
+	}
+
+	if s.limits.MaxPacks > 0 && len(warehouse.Stock) > s.limits.MaxPacks {
+		return model.NewError(
+			model.ErrorCodeTooManyPacks,
+			fmt.Sprintf("number of packs cannot exceed %d", s.limits.MaxPacks),
+		)
+	}
+
+	return s.repo.SaveWarehouse(warehouse)
+}
+
+// RemoveWarehouse removes a warehouse by its ID
+func (s *PacksServiceImpl) RemoveWarehouse(id string) error {
+	return s.repo.RemoveWarehouse(id)
+}
+
+// allocate fulfils an order in the shipment mode from the stock of the warehouses.
+// The best packs are those of all the warehouses together. A single warehouse that ships as many items
+// ships the order with the fewest packs it can, otherwise the best packs are taken from as few warehouses as possible.
+// Returns:
+// - packs: the map of pack size to count of all the packs shipped
+// - allocations: the packs each warehouse ships
+func (s *PacksServiceImpl) allocate(
+	calc *calculation,
+	orderSize int64,
+	mode model.ShipmentMode,
+) (map[model.PackSize]int64, []model.WarehouseAllocation, error) {
+	warehouses := s.repo.GetWarehouses()
+
+	var stock []model.StockItem
+	for _, warehouse := range warehouses {
+		stock = append(stock, warehouse.Stock...)
+	}
+
+	pooled, err := stockPacks(stock)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(pooled) == 0 {
+		return nil, nil, model.NewError(model.ErrorCodeInsufficientStock, "there are no packs in stock")
+	}
+
+	_, alternatives, err := s.calculateOrder(calc, pooled, orderSize, mode, true)
+	if model.ErrorCodeOf(err) == model.ErrorCodeNoFeasiblePacks {
+		return nil, nil, model.NewError(
+			model.ErrorCodeInsufficientStock,
+			fmt.Sprintf("warehouses do not have enough packs in stock for order size %d", orderSize),
+		)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// the amount of items does not overflow, calculateOrder checks every alternative
+	shippedItems, _, _ := getAmountOfItemsInPacks(alternatives[0])
+
+	var best map[model.PackSize]int64
+	var bestWarehouse string
+	var bestCount int64
+	for _, warehouse := range warehouses {
+		packs, err := stockPacks(warehouse.Stock)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if len(packs) == 0 {
+			continue
+		}
+
+		_, candidates, err := s.calculateOrder(calc, packs, orderSize, mode, false)
+		if model.ErrorCodeOf(err) == model.ErrorCodeNoFeasiblePacks {
+			continue
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+
+		items, count, _ := getAmountOfItemsInPacks(candidates[0])
+		if items == shippedItems && (best == nil || count < bestCount) {
+			best, bestWarehouse, bestCount = candidates[0], warehouse.ID, count
+		}
+	}
+
+	if best != nil {
+		return best, []model.WarehouseAllocation{{Warehouse: bestWarehouse, Packs: best}}, nil
+	}
+
+	var bestAllocations []model.WarehouseAllocation
+	for _, alternative := range alternatives {
+		allocations := allocateStock(warehouses, alternative)
+		if best == nil || len(allocations) < len(bestAllocations) {
+			best, bestAllocations = alternative, allocations
+		}
+	}
+
+	return best, bestAllocations, nil
+}
+
+// stockPacks returns the packs of the sizes in stock, each limited to the total quantity in stock
+func stockPacks(stock []model.StockItem) (model.Packs, error) {
+	quantities := make(map[model.PackSize]int64)
+	for _, item := range stock {
+		var ok bool
+		if quantities[item.Size], ok = packing.CheckedAdd(quantities[item.Size], item.Quantity); !ok {
+			return nil, model.ErrArithmeticOverflow
+		}
+	}
+
+	packs := make(model.Packs, 0, len(quantities))
+	for _, size := range slices.Sorted(maps.Keys(quantities)) {
+		if quantities[size] > 0 {
+			packs = append(packs, model.Pack{Size: size, MaxCount: quantities[size]})
+		}
+	}
+
+	return packs, nil
+}
+
+// allocateStock takes packs from the stock of the warehouses, which must hold enough of them.
+// The warehouse that can ship the most of the remaining items is taken first until no packs are left.
+func allocateStock(warehouses []model.Warehouse, packs map[model.PackSize]int64) []model.WarehouseAllocation {
+	remaining := maps.Clone(packs)
+	used := make([]bool, len(warehouses))
+
+	var result []model.WarehouseAllocation
+	for len(remaining) > 0 {
+		bestIndex, bestItems := -1, int64(0)
+		for i, warehouse := range warehouses {
+			if used[i] {
+				continue
+			}
+
+			var items int64
+			for _, item := range warehouse.Stock {
+				items += int64(item.Size) * min(item.Quantity, remaining[item.Size])
+			}
+
+			if items > bestItems {
+				bestIndex, bestItems = i, items
+			}
+		}
+		used[bestIndex] = true
+
+		allocation := model.WarehouseAllocation{
+			Warehouse: warehouses[bestIndex].ID,
+			Packs:     make(map[model.PackSize]int64),
+		}
+		for _, item := range warehouses[bestIndex].Stock {
+			if count := min(item.Quantity, remaining[item.Size]); count > 0 {
+				allocation.Packs[item.Size] = count
+				remaining[item.Size] -= count
+				if remaining[item.Size] == 0 {
+					delete(remaining, item.Size)
+				}
+			}
+		}
+		result = append(result, allocation)
+	}
+
+	return result
+}
@@ -0,0 +1,136 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/alishercodecrafter/orderpackscalculator/internal/model"
+	"github.com/alishercodecrafter/orderpackscalculator/internal/netguard"
+)
+
+// webhookResolveTimeout is the time the host of a webhook has to resolve when it is saved
+const webhookResolveTimeout = 5 * time.Second
+
+// WebhookNotifier delivers the events of the service to the webhooks subscribed to them
+type WebhookNotifier interface {
+	// Notify delivers the event without waiting for the webhooks
+	Notify(event model.WebhookEvent)
+}
+
+// WithWebhookNotifier sets the notifier the changes of the packs and the calculations are delivered to webhooks with
+func WithWebhookNotifier(notifier WebhookNotifier) Option {
+	return func(s *PacksServiceImpl) {
+		s.webhooks = notifier
+	}
+}
+
+// WithWebhookAddressPolicy sets the policy of the addresses the URLs of webhooks may resolve to,
+// by default only public addresses are accepted
+func WithWebhookAddressPolicy(policy netguard.Policy) Option {
+	return func(s *PacksServiceImpl) {
+		s.webhookAddresses = policy
+	}
+}
+
+// packsWebhookEvents maps the kind of a change of the available packs to the kind of its webhook event
+var packsWebhookEvents = map[model.PacksEventType]model.WebhookEventType{
+	model.PacksEventAdded:    model.WebhookEventPacksAdded,
+	model.PacksEventRemoved:  model.WebhookEventPacksRemoved,
+	model.PacksEventReplaced: model.WebhookEventPacksReplaced,
+}
+
+// webhookEventTypes are the kinds of events a webhook can subscribe to
+var webhookEventTypes = map[model.WebhookEventType]bool{
+	model.WebhookEventPacksAdded:           true,
+	model.WebhookEventPacksRemoved:         true,
+	model.WebhookEventPacksReplaced:        true,
+	model.WebhookEventCalculationCompleted: true,
+}
+
+// packsChanged publishes a change of the available packs to the subscribers and to the webhooks
+func (s *PacksServiceImpl) packsChanged(eventType model.PacksEventType) {
+	var notify func(model.PacksEvent)
+	if s.webhooks != nil {
+		notify = func(event model.PacksEvent) {
+			s.notify(packsWebhookEvents[eventType], event)
+		}
+	}
+
+	s.packsEvents.publish(eventType, s.repo.GetPacks, notify)
+}
+
+// notify delivers an event with the data to the webhooks, if there is a notifier
+func (s *PacksServiceImpl) notify(eventType model.WebhookEventType, data any) {
+	if s.webhooks == nil {
+		return
+	}
+
+	s.webhooks.Notify(model.WebhookEvent{Type: eventType, CreatedAt: time.Now(), Data: data})
+}
+
+// GetWebhooks returns all webhooks without their secrets
+func (s *PacksServiceImpl) GetWebhooks() []model.Webhook {
+	webhooks := s.repo.GetWebhooks()
+	for i := range webhooks {
+		webhooks[i].Secret = ""
+	}
+
+	return webhooks
+}
+
+// SaveWebhook adds a webhook or replaces the webhook with the same ID.
+// The host of its URL must only resolve to addresses the webhook address policy allows.
+func (s *PacksServiceImpl) SaveWebhook(webhook model.Webhook) error {
+	if webhook.ID == "" {
+		return model.NewError(model.ErrorCodeInvalidWebhook, "webhook ID must not be empty")
+	}
+
+	target, err := url.Parse(webhook.URL)
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+		return model.NewError(model.ErrorCodeInvalidWebhook, "webhook URL must be an absolute http or https URL")
+	}
+
+	if webhook.Secret == "" {
+		return model.NewError(model.ErrorCodeInvalidWebhook, "webhook secret must not be empty")
+	}
+
+	if len(webhook.Events) == 0 {
+		return model.NewError(model.ErrorCodeInvalidWebhook, "webhook must subscribe to at least one event")
+	}
+
+	events := make(map[model.WebhookEventType]bool, len(webhook.Events))
+	for _, event := range webhook.Events {
+		if !webhookEventTypes[event] {
+			return model.NewError(model.ErrorCodeInvalidWebhook, fmt.Sprintf("unknown webhook event %q", event))
+		}
+
+		if events[event] {
+			return model.NewError(
+				model.ErrorCodeInvalidWebhook,
+				fmt.Sprintf("webhook event %q is listed twice", event),
+			)
+		}
+		events[event] = true
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), webhookResolveTimeout)
+	defer cancel()
+
+	if err := s.webhookAddresses.CheckHost(ctx, target.Hostname()); err != nil {
+		return model.NewError(model.ErrorCodeInvalidWebhook, fmt.Sprintf("webhook URL is not allowed: %v", err))
+	}
+
+	return s.repo.SaveWebhook(webhook)
+}
+
+// RemoveWebhook removes a webhook by its ID
+func (s *PacksServiceImpl) RemoveWebhook(id string) error {
+	return s.repo.RemoveWebhook(id)
+}
+
+// GetDeadLetters returns the events that could not be delivered to their webhooks, oldest first
+func (s *PacksServiceImpl) GetDeadLetters() []model.DeadLetter {
+	return s.repo.GetDeadLetters()
+}
@@ -0,0 +1,381 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/alishercodecrafter/orderpackscalculator/internal/model"
+	"github.com/alishercodecrafter/orderpackscalculator/internal/netguard"
+)
+
+const (
+	// EventIDHeader is the header of the ID of the event, the same in every retry of its delivery
+	EventIDHeader = "X-Webhook-Id"
+	// EventTypeHeader is the header of the kind of the event
+	EventTypeHeader = "X-Webhook-Event"
+	// TimestampHeader is the header of the Unix time in seconds the delivery was signed at
+	TimestampHeader = "X-Webhook-Timestamp"
+	// SignatureHeader is the header of the signature of the delivery, "sha256=" followed by the result of Sign
+	SignatureHeader = "X-Webhook-Signature"
+)
+
+const (
+	// DefaultTimeout is the time a webhook has to respond to a delivery of a dispatcher created without WithHTTPClient
+	DefaultTimeout = 10 * time.Second
+	// queueSize is the number of deliveries waiting for a worker before new deliveries are dead-lettered
+	queueSize = 256
+	// workers is the number of deliveries made concurrently
+	workers = 4
+	// maxResponseSize is the largest part of a response body read before the connection is reused
+	maxResponseSize = 64 << 10
+)
+
+var (
+	// errQueueFull is the error of a delivery that could not wait for a worker
+	errQueueFull = errors.New("delivery queue is full")
+	// errStopped is the error of a delivery the dispatcher was shut down before making
+	errStopped = errors.New("dispatcher stopped before delivering")
+)
+
+// Store provides the webhooks and records the events that could not be delivered to them
+type Store interface {
+	// GetWebhooks returns all webhooks with their secrets
+	GetWebhooks() []model.Webhook
+	// AddDeadLetter records an undelivered webhook event
+	AddDeadLetter(deadLetter model.DeadLetter) error
+}
+
+// Dispatcher posts the events of the service to the webhooks subscribed to them.
+// Each event is posted as JSON with a signature of the secret of the webhook. A failed delivery is attempted again
+// with an exponential backoff and recorded as a dead letter once its attempts are exhausted.
+// Unless it is created WithHTTPClient, it only connects to the addresses its address policy allows.
+// Shutdown delivers the queued events and dead-letters the deliveries it could not make.
+type Dispatcher struct {
+	store     Store
+	client    *http.Client
+	addresses netguard.Policy
+	retry     RetryPolicy
+	queue     chan *delivery
+
+	// mu guards closed and retries, so that no delivery is queued or scheduled for a retry once Shutdown starts
+	mu     sync.Mutex
+	closed bool
+	// retries are the timers of the deliveries waiting for a retry
+	retries map[*delivery]*time.Timer
+	// pending counts the deliveries that are queued or being made
+	pending sync.WaitGroup
+	// stop stops the workers, which running counts
+	stop    context.CancelFunc
+	running sync.WaitGroup
+}
+
+// Option configures a Dispatcher
+type Option func(*Dispatcher)
+
+// WithHTTPClient sets the HTTP client the events are posted with
+func WithHTTPClient(client *http.Client) Option {
+	return func(d *Dispatcher) {
+		d.client = client
+	}
+}
+
+// WithAddressPolicy sets the policy of the addresses the events are posted to, by default only public addresses.
+// It has no effect WithHTTPClient.
+func WithAddressPolicy(policy netguard.Policy) Option {
+	return func(d *Dispatcher) {
+		d.addresses = policy
+	}
+}
+
+// WithRetryPolicy sets the retry policy of failed deliveries
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(d *Dispatcher) {
+		d.retry = policy
+	}
+}
+
+// NewDispatcher creates a new Dispatcher of the webhooks of the store, deliveries start with Start
+func NewDispatcher(store Store, opts ...Option) *Dispatcher {
+	d := &Dispatcher{
+		store:   store,
+		retry:   DefaultRetryPolicy(),
+		queue:   make(chan *delivery, queueSize),
+		retries: make(map[*delivery]*time.Timer),
+		stop:    func() {},
+	}
+	for _, opt := range opts {
+		opt(d)
+	}
+
+	if d.client == nil {
+		d.client = newHTTPClient(d.addresses)
+	}
+
+	return d
+}
+
+// delivery is an event posted to a webhook
+type delivery struct {
+	webhook model.Webhook
+	event   model.WebhookEvent
+	// body is the JSON payload of the event
+	body []byte
+	// attempts is the number of attempts made so far
+	attempts int
+	// err is the error of the last attempt
+	err error
+}
+
+// Start starts delivering the events until ctx is done or Shutdown, a delivery waiting for a retry is then
+// dead-lettered
+func (d *Dispatcher) Start(ctx context.Context) {
+	ctx, d.stop = context.WithCancel(ctx)
+	for range workers {
+		d.running.Add(1)
+		go func() {
+			defer d.running.Done()
+
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case dl := <-d.queue:
+					d.deliver(ctx, dl)
+					d.pending.Done()
+				}
+			}
+		}()
+	}
+}
+
+// Shutdown stops queueing events and makes the queued deliveries until ctx is done, without waiting for their
+// retries. The deliveries it could not make, as well as those waiting for a retry, are dead-lettered.
+// It returns the error of ctx if it is done before the queue is drained.
+func (d *Dispatcher) Shutdown(ctx context.Context) error {
+	d.mu.Lock()
+	d.closed = true
+	for dl, timer := range d.retries {
+		timer.Stop()
+		d.deadLetter(dl, stoppedBeforeRetrying(dl.err))
+	}
+	clear(d.retries)
+	d.mu.Unlock()
+
+	drained := make(chan struct{})
+	go func() {
+		d.pending.Wait()
+		close(drained)
+	}()
+
+	var err error
+	select {
+	case <-drained:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	// the deliveries being made when ctx is done are cancelled and dead-lettered by the workers
+	d.stop()
+	d.running.Wait()
+
+	for {
+		select {
+		case dl := <-d.queue:
+			d.deadLetter(dl, errStopped)
+			d.pending.Done()
+		default:
+			return err
+		}
+	}
+}
+
+// Notify queues the event for the webhooks subscribed to its kind without waiting for the deliveries
+func (d *Dispatcher) Notify(event model.WebhookEvent) {
+	var webhooks []model.Webhook
+	for _, webhook := range d.store.GetWebhooks() {
+		if slices.Contains(webhook.Events, event.Type) {
+			webhooks = append(webhooks, webhook)
+		}
+	}
+
+	if len(webhooks) == 0 {
+		return
+	}
+
+	if event.ID == "" {
+		event.ID = newEventID()
+	}
+
+	body, err := json.Marshal(event)
+	for _, webhook := range webhooks {
+		dl := &delivery{webhook: webhook, event: event, body: body}
+		if err != nil {
+			d.deadLetter(dl, err)
+
+			continue
+		}
+		d.enqueue(dl)
+	}
+}
+
+// enqueue queues the delivery for a worker, or dead-letters it if the queue is full or the dispatcher is shut down
+func (d *Dispatcher) enqueue(dl *delivery) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.enqueueLocked(dl)
+}
+
+// enqueueLocked is enqueue with mu held
+func (d *Dispatcher) enqueueLocked(dl *delivery) {
+	if d.closed {
+		d.deadLetter(dl, errStopped)
+
+		return
+	}
+
+	d.pending.Add(1)
+	select {
+	case d.queue <- dl:
+	default:
+		d.pending.Done()
+		d.deadLetter(dl, errQueueFull)
+	}
+}
+
+// deliver attempts the delivery and schedules its retry if it fails, or dead-letters it if it was its last attempt
+func (d *Dispatcher) deliver(ctx context.Context, dl *delivery) {
+	dl.attempts++
+
+	err := d.post(ctx, dl)
+	if err == nil {
+		return
+	}
+	dl.err = err
+
+	if dl.attempts >= d.retry.MaxAttempts {
+		d.deadLetter(dl, err)
+
+		return
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.closed || ctx.Err() != nil {
+		d.deadLetter(dl, stoppedBeforeRetrying(err))
+
+		return
+	}
+
+	d.retries[dl] = time.AfterFunc(d.retry.backoff(dl.attempts), func() {
+		d.mu.Lock()
+		defer d.mu.Unlock()
+
+		// Shutdown has dead-lettered the delivery already
+		if _, ok := d.retries[dl]; !ok {
+			return
+		}
+		delete(d.retries, dl)
+
+		if ctx.Err() != nil {
+			d.deadLetter(dl, stoppedBeforeRetrying(err))
+
+			return
+		}
+		d.enqueueLocked(dl)
+	})
+}
+
+// post posts the event of the delivery to its webhook, failing unless the webhook responds with a 2xx status
+func (d *Dispatcher) post(ctx context.Context, dl *delivery) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, dl.webhook.URL, bytes.NewReader(dl.body))
+	if err != nil {
+		return err
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventIDHeader, dl.event.ID)
+	req.Header.Set(EventTypeHeader, string(dl.event.Type))
+	req.Header.Set(TimestampHeader, timestamp)
+	req.Header.Set(SignatureHeader, "sha256="+Sign(dl.webhook.Secret, timestamp, dl.body))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxResponseSize))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// stoppedBeforeRetrying returns the error of a delivery the dispatcher stopped before retrying after the error
+func stoppedBeforeRetrying(err error) error {
+	return fmt.Errorf("dispatcher stopped before retrying: %w", err)
+}
+
+// deadLetter records the delivery that failed with the error
+func (d *Dispatcher) deadLetter(dl *delivery, err error) {
+	// there is no one to report a failure to record the dead letter to
+	_ = d.store.AddDeadLetter(model.DeadLetter{
+		Webhook:  dl.webhook.ID,
+		URL:      dl.webhook.URL,
+		Event:    dl.event,
+		Attempts: dl.attempts,
+		Error:    err.Error(),
+		FailedAt: time.Now(),
+	})
+}
+
+// newHTTPClient returns the HTTP client of a dispatcher created without WithHTTPClient, which only connects to
+// the addresses the policy allows, also after a redirect or when the host of a webhook resolves to another address
+// than when it was saved. It does not use a proxy, as the addresses it connects to would be the proxy's.
+func newHTTPClient(policy netguard.Policy) *http.Client {
+	dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second, Control: policy.Control}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &http.Client{Timeout: DefaultTimeout, Transport: transport}
+}
+
+// Sign returns the hex-encoded HMAC-SHA256 of the timestamp, a dot and the body with the secret as the key.
+// A webhook verifies a delivery by comparing the signature of its timestamp and body with its signature header.
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// newEventID returns a random ID of an event
+func newEventID() string {
+	id := make([]byte, 16)
+	_, _ = rand.Read(id)
+
+	return hex.EncodeToString(id)
+}
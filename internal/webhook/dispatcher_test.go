@@ -0,0 +1,329 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alishercodecrafter/orderpackscalculator/internal/model"
+	"github.com/alishercodecrafter/orderpackscalculator/internal/netguard"
+	"github.com/alishercodecrafter/orderpackscalculator/internal/repository"
+	"github.com/stretchr/testify/require"
+)
+
+// stub is a local webhook that records the deliveries it receives and responds with its statuses in turn
+type stub struct {
+	mu         sync.Mutex
+	statuses   []int
+	deliveries []*http.Request
+	bodies     [][]byte
+}
+
+// ServeHTTP records the delivery and responds with the next status, 200 once the statuses are used up
+func (s *stub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.deliveries = append(s.deliveries, r)
+	s.bodies = append(s.bodies, body)
+
+	status := http.StatusOK
+	if len(s.statuses) > 0 {
+		status, s.statuses = s.statuses[0], s.statuses[1:]
+	}
+	w.WriteHeader(status)
+}
+
+// received returns the number of deliveries received
+func (s *stub) received() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.deliveries)
+}
+
+// newTestDispatcher starts a dispatcher of the webhooks with fast retries until the test ends
+func newTestDispatcher(t *testing.T, maxAttempts int, webhooks ...model.Webhook) (*Dispatcher, *repository.MemoryRepository) {
+	t.Helper()
+
+	repo := repository.NewMemoryRepository()
+	for _, webhook := range webhooks {
+		require.NoError(t, repo.SaveWebhook(webhook))
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	d := NewDispatcher(
+		repo,
+		WithRetryPolicy(RetryPolicy{
+			MaxAttempts:    maxAttempts,
+			InitialBackoff: time.Millisecond,
+			MaxBackoff:     5 * time.Millisecond,
+		}),
+		WithAddressPolicy(netguard.Policy{Allowed: []netip.Prefix{netip.MustParsePrefix("127.0.0.0/8")}}),
+	)
+	d.Start(ctx)
+
+	return d, repo
+}
+
+func TestDispatcher_Notify(t *testing.T) {
+	packsHook, calculationsHook := &stub{}, &stub{}
+	packsServer, calculationsServer := httptest.NewServer(packsHook), httptest.NewServer(calculationsHook)
+	defer packsServer.Close()
+	defer calculationsServer.Close()
+
+	d, _ := newTestDispatcher(t, 1,
+		model.Webhook{
+			ID:     "erp",
+			URL:    packsServer.URL,
+			Events: []model.WebhookEventType{model.WebhookEventPacksAdded, model.WebhookEventPacksReplaced},
+			Secret: "s3cret",
+		},
+		model.Webhook{
+			ID:     "billing",
+			URL:    calculationsServer.URL,
+			Events: []model.WebhookEventType{model.WebhookEventCalculationCompleted},
+			Secret: "other",
+		},
+	)
+
+	createdAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	d.Notify(model.WebhookEvent{
+		Type:      model.WebhookEventPacksAdded,
+		CreatedAt: createdAt,
+		Data:      model.PacksEvent{Type: model.PacksEventAdded, Packs: model.Packs{{Size: 250}}},
+	})
+
+	require.Eventually(t, func() bool { return packsHook.received() == 1 }, time.Second, time.Millisecond)
+	require.Zero(t, calculationsHook.received())
+
+	req, body := packsHook.deliveries[0], packsHook.bodies[0]
+	require.Equal(t, http.MethodPost, req.Method)
+	require.Equal(t, "application/json", req.Header.Get("Content-Type"))
+	require.Equal(t, string(model.WebhookEventPacksAdded), req.Header.Get(EventTypeHeader))
+	require.Equal(t,
+		"sha256="+Sign("s3cret", req.Header.Get(TimestampHeader), body),
+		req.Header.Get(SignatureHeader))
+
+	var event struct {
+		ID        string           `json:"id"`
+		Type      string           `json:"type"`
+		CreatedAt time.Time        `json:"createdAt"`
+		Data      model.PacksEvent `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(body, &event))
+	require.Equal(t, req.Header.Get(EventIDHeader), event.ID)
+	require.Len(t, event.ID, 32)
+	require.Equal(t, "packs.added", event.Type)
+	require.Equal(t, createdAt, event.CreatedAt)
+	require.Equal(t, model.PacksEvent{Type: model.PacksEventAdded, Packs: model.Packs{{Size: 250}}}, event.Data)
+}
+
+func TestDispatcher_Retry(t *testing.T) {
+	hook := &stub{statuses: []int{http.StatusInternalServerError, http.StatusTooManyRequests}}
+	server := httptest.NewServer(hook)
+	defer server.Close()
+
+	d, repo := newTestDispatcher(t, 3, model.Webhook{
+		ID:     "erp",
+		URL:    server.URL,
+		Events: []model.WebhookEventType{model.WebhookEventCalculationCompleted},
+		Secret: "s3cret",
+	})
+
+	d.Notify(model.WebhookEvent{Type: model.WebhookEventCalculationCompleted, Data: model.CalculationRecord{}})
+
+	require.Eventually(t, func() bool { return hook.received() == 3 }, time.Second, time.Millisecond)
+	require.Equal(t, hook.deliveries[0].Header.Get(EventIDHeader), hook.deliveries[2].Header.Get(EventIDHeader))
+	require.Equal(t, hook.bodies[0], hook.bodies[2])
+	require.Empty(t, repo.GetDeadLetters())
+}
+
+func TestDispatcher_DeadLetter(t *testing.T) {
+	hook := &stub{statuses: []int{http.StatusBadGateway, http.StatusBadGateway, http.StatusServiceUnavailable}}
+	server := httptest.NewServer(hook)
+	defer server.Close()
+
+	d, repo := newTestDispatcher(t, 3, model.Webhook{
+		ID:     "erp",
+		URL:    server.URL,
+		Events: []model.WebhookEventType{model.WebhookEventPacksRemoved},
+		Secret: "s3cret",
+	})
+
+	d.Notify(model.WebhookEvent{Type: model.WebhookEventPacksRemoved, Data: model.PacksEvent{}})
+
+	require.Eventually(t, func() bool { return len(repo.GetDeadLetters()) == 1 }, time.Second, time.Millisecond)
+	require.Equal(t, 3, hook.received())
+
+	deadLetter := repo.GetDeadLetters()[0]
+	require.Equal(t, "erp", deadLetter.Webhook)
+	require.Equal(t, server.URL, deadLetter.URL)
+	require.Equal(t, model.WebhookEventPacksRemoved, deadLetter.Event.Type)
+	require.Equal(t, hook.deliveries[0].Header.Get(EventIDHeader), deadLetter.Event.ID)
+	require.Equal(t, 3, deadLetter.Attempts)
+	require.Equal(t, "webhook responded with status 503", deadLetter.Error)
+}
+
+func TestDispatcher_Unreachable(t *testing.T) {
+	server := httptest.NewServer(&stub{})
+	url := server.URL
+	server.Close()
+
+	d, repo := newTestDispatcher(t, 2, model.Webhook{
+		ID:     "erp",
+		URL:    url,
+		Events: []model.WebhookEventType{model.WebhookEventPacksReplaced},
+		Secret: "s3cret",
+	})
+
+	d.Notify(model.WebhookEvent{Type: model.WebhookEventPacksReplaced, Data: model.PacksEvent{}})
+
+	require.Eventually(t, func() bool { return len(repo.GetDeadLetters()) == 1 }, time.Second, time.Millisecond)
+	require.Equal(t, 2, repo.GetDeadLetters()[0].Attempts)
+}
+
+func TestRetryPolicy_backoff(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 10, InitialBackoff: time.Second, MaxBackoff: 10 * time.Second}
+
+	require.Equal(t, time.Second, policy.backoff(1))
+	require.Equal(t, 2*time.Second, policy.backoff(2))
+	require.Equal(t, 8*time.Second, policy.backoff(4))
+	require.Equal(t, 10*time.Second, policy.backoff(5))
+	require.Equal(t, 10*time.Second, policy.backoff(9))
+}
+
+func TestSign(t *testing.T) {
+	// echo -n '1700000000.{}' | openssl dgst -sha256 -hmac secret
+	require.Equal(t,
+		"b8569b78799ff9e3cbff0fc2d63a33a2b57f3282abd07c37ae5e8e7d79a5f163",
+		Sign("secret", "1700000000", []byte("{}")))
+}
+
+func TestDispatcher_NotPublic(t *testing.T) {
+	hook := &stub{}
+	server := httptest.NewServer(hook)
+	defer server.Close()
+
+	repo := repository.NewMemoryRepository()
+	require.NoError(t, repo.SaveWebhook(model.Webhook{
+		ID:     "erp",
+		URL:    server.URL,
+		Events: []model.WebhookEventType{model.WebhookEventPacksAdded},
+		Secret: "s3cret",
+	}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	d := NewDispatcher(repo, WithRetryPolicy(RetryPolicy{MaxAttempts: 1}))
+	d.Start(ctx)
+
+	d.Notify(model.WebhookEvent{Type: model.WebhookEventPacksAdded, Data: model.PacksEvent{}})
+
+	require.Eventually(t, func() bool { return len(repo.GetDeadLetters()) == 1 }, time.Second, time.Millisecond)
+	require.Contains(t, repo.GetDeadLetters()[0].Error, "address 127.0.0.1 is not public")
+	require.Zero(t, hook.received())
+}
+
+func TestDispatcher_Shutdown(t *testing.T) {
+	hook := &stub{}
+	server := httptest.NewServer(hook)
+	defer server.Close()
+
+	d, repo := newTestDispatcher(t, 3, model.Webhook{
+		ID:     "erp",
+		URL:    server.URL,
+		Events: []model.WebhookEventType{model.WebhookEventPacksAdded},
+		Secret: "s3cret",
+	})
+
+	for range 10 {
+		d.Notify(model.WebhookEvent{Type: model.WebhookEventPacksAdded, Data: model.PacksEvent{}})
+	}
+	require.NoError(t, d.Shutdown(context.Background()))
+	require.Equal(t, 10, hook.received())
+	require.Empty(t, repo.GetDeadLetters())
+
+	d.Notify(model.WebhookEvent{Type: model.WebhookEventPacksAdded, Data: model.PacksEvent{}})
+	require.Equal(t, 10, hook.received())
+	require.Len(t, repo.GetDeadLetters(), 1)
+	require.Zero(t, repo.GetDeadLetters()[0].Attempts)
+	require.Equal(t, "dispatcher stopped before delivering", repo.GetDeadLetters()[0].Error)
+}
+
+func TestDispatcher_ShutdownRetry(t *testing.T) {
+	hook := &stub{statuses: []int{http.StatusInternalServerError}}
+	server := httptest.NewServer(hook)
+	defer server.Close()
+
+	repo := repository.NewMemoryRepository()
+	require.NoError(t, repo.SaveWebhook(model.Webhook{
+		ID:     "erp",
+		URL:    server.URL,
+		Events: []model.WebhookEventType{model.WebhookEventPacksAdded},
+		Secret: "s3cret",
+	}))
+
+	d := NewDispatcher(
+		repo,
+		WithRetryPolicy(RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Hour, MaxBackoff: time.Hour}),
+		WithAddressPolicy(netguard.Policy{Allowed: []netip.Prefix{netip.MustParsePrefix("127.0.0.0/8")}}),
+	)
+	d.Start(context.Background())
+
+	d.Notify(model.WebhookEvent{Type: model.WebhookEventPacksAdded, Data: model.PacksEvent{}})
+	require.Eventually(t, func() bool {
+		d.mu.Lock()
+		defer d.mu.Unlock()
+
+		return len(d.retries) == 1
+	}, time.Second, time.Millisecond)
+
+	require.NoError(t, d.Shutdown(context.Background()))
+	require.Equal(t, 1, hook.received())
+	require.Len(t, repo.GetDeadLetters(), 1)
+	require.Equal(t, 1, repo.GetDeadLetters()[0].Attempts)
+	require.Equal(t,
+		"dispatcher stopped before retrying: webhook responded with status 500",
+		repo.GetDeadLetters()[0].Error)
+}
+
+func TestDispatcher_ShutdownTimeout(t *testing.T) {
+	// the webhook responds only once the delivery is cancelled, which is noticed after the body is read
+	server := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(io.Discard, r.Body)
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	d, repo := newTestDispatcher(t, 3, model.Webhook{
+		ID:     "erp",
+		URL:    server.URL,
+		Events: []model.WebhookEventType{model.WebhookEventPacksAdded},
+		Secret: "s3cret",
+	})
+
+	for range 2 * workers {
+		d.Notify(model.WebhookEvent{Type: model.WebhookEventPacksAdded, Data: model.PacksEvent{}})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	require.ErrorIs(t, d.Shutdown(ctx), context.DeadlineExceeded)
+	require.Len(t, repo.GetDeadLetters(), 2*workers)
+	for _, deadLetter := range repo.GetDeadLetters() {
+		require.Contains(t, deadLetter.Error, "dispatcher stopped before")
+	}
+}
@@ -0,0 +1,37 @@
+package webhook
+
+import "time"
+
+// RetryPolicy decides how often and after which wait a failed delivery is attempted again.
+// A delivery fails when the webhook cannot be reached or does not respond with a 2xx status.
+type RetryPolicy struct {
+	// MaxAttempts is the largest number of attempts of a delivery, 1 or less disables retries
+	MaxAttempts int
+	// InitialBackoff is the wait before the first retry, doubled before each next retry
+	InitialBackoff time.Duration
+	// MaxBackoff caps the wait before a retry
+	MaxBackoff time.Duration
+}
+
+// DefaultRetryPolicy returns the retry policy of a dispatcher created without WithRetryPolicy
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    5,
+		InitialBackoff: time.Second,
+		MaxBackoff:     time.Minute,
+	}
+}
+
+// backoff returns the wait before the retry following the attempt, counted from 1, capped by MaxBackoff
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	wait := p.InitialBackoff
+	for i := 1; i < attempt && wait < p.MaxBackoff; i++ {
+		wait *= 2
+	}
+
+	if p.MaxBackoff > 0 {
+		wait = min(wait, p.MaxBackoff)
+	}
+
+	return wait
+}
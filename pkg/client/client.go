@@ -0,0 +1,318 @@
+// Package client is a typed Go client of the REST API of the order packs calculator.
+//
+// Its methods mirror the operations of the server, take a context and return the responses of the API
+// decoded into the types of the API. Error responses are returned as *APIError with the error code
+// of the API. Requests that fail on the way to the server are retried with backoff, see RetryPolicy.
+//
+//	c := client.New("http://localhost:8080")
+//	result, err := c.Calculate(ctx, client.CalculationRequest{OrderSize: 12001})
+//	if client.ErrorCodeOf(err) == client.ErrorCodeOrderSizeTooLarge {
+//		...
+//	}
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// defaultTimeout is the timeout of an attempt of a request of a client created without WithHTTPClient
+const defaultTimeout = 30 * time.Second
+
+// Client calls the REST API of a server. It is safe for concurrent use.
+type Client struct {
+	baseURL string
+	http    *http.Client
+	retry   RetryPolicy
+}
+
+// Option configures a Client
+type Option func(*Client)
+
+// WithHTTPClient sets the HTTP client the requests are sent with, e.g. to set its timeout or transport
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.http = httpClient
+	}
+}
+
+// WithRetryPolicy sets how failed requests are retried, DefaultRetryPolicy by default
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(c *Client) {
+		c.retry = policy
+	}
+}
+
+// New creates a client of the server at baseURL, e.g. http://localhost:8080
+func New(baseURL string, opts ...Option) *Client {
+	c := &Client{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		http:    &http.Client{Timeout: defaultTimeout},
+		retry:   DefaultRetryPolicy(),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// GetPacks returns all available packs
+func (c *Client) GetPacks(ctx context.Context) (Packs, error) {
+	var packs Packs
+	err := c.do(ctx, http.MethodGet, "/api/packs", nil, &packs)
+
+	return packs, err
+}
+
+// AddPack adds a new pack
+func (c *Client) AddPack(ctx context.Context, pack Pack) error {
+	return c.do(ctx, http.MethodPost, "/api/packs", addPackRequest{Pack: pack}, nil)
+}
+
+// RemovePack removes a pack by its size
+func (c *Client) RemovePack(ctx context.Context, packSize PackSize) error {
+	return c.do(ctx, http.MethodDelete, fmt.Sprintf("/api/packs/%d", packSize), nil, nil)
+}
+
+// ReplacePacks replaces all packs
+func (c *Client) ReplacePacks(ctx context.Context, packs Packs) error {
+	return c.do(ctx, http.MethodPut, "/api/packs", replacePacksRequest{Packs: packs}, nil)
+}
+
+// GetPackChanges returns the changes of the available packs after the version, oldest first
+func (c *Client) GetPackChanges(ctx context.Context, since int64) ([]PackChange, error) {
+	var changes []PackChange
+	err := c.do(ctx, http.MethodGet, fmt.Sprintf("/api/packs/changes?since=%d", since), nil, &changes)
+
+	return changes, err
+}
+
+// GetPacksAt returns the available packs as they were at the version
+func (c *Client) GetPacksAt(ctx context.Context, version int64) (Packs, error) {
+	var packs Packs
+	err := c.do(ctx, http.MethodGet, fmt.Sprintf("/api/packs/versions/%d", version), nil, &packs)
+
+	return packs, err
+}
+
+// Calculate calculates the optimal number of packs needed for a single-line or a multi-line order
+func (c *Client) Calculate(ctx context.Context, req CalculationRequest) (CalculationResponse, error) {
+	var result CalculationResponse
+	err := c.do(ctx, http.MethodPost, "/api/calculate", req, &result)
+
+	return result, err
+}
+
+// GetCatalogs returns all catalogs
+func (c *Client) GetCatalogs(ctx context.Context) ([]Catalog, error) {
+	var catalogs []Catalog
+	err := c.do(ctx, http.MethodGet, "/api/catalogs", nil, &catalogs)
+
+	return catalogs, err
+}
+
+// SaveCatalog adds a catalog or replaces the catalog with the same ID
+func (c *Client) SaveCatalog(ctx context.Context, catalog Catalog) error {
+	return c.do(ctx, http.MethodPut, "/api/catalogs/"+url.PathEscape(catalog.ID), catalog, nil)
+}
+
+// RemoveCatalog removes a catalog by its ID
+func (c *Client) RemoveCatalog(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, "/api/catalogs/"+url.PathEscape(id), nil, nil)
+}
+
+// GetPackaging returns the hierarchy of outer containers packs are shipped in
+func (c *Client) GetPackaging(ctx context.Context) (Packaging, error) {
+	var packaging Packaging
+	err := c.do(ctx, http.MethodGet, "/api/packaging", nil, &packaging)
+
+	return packaging, err
+}
+
+// SavePackaging replaces the hierarchy of outer containers packs are shipped in
+func (c *Client) SavePackaging(ctx context.Context, packaging Packaging) error {
+	return c.do(ctx, http.MethodPut, "/api/packaging", packaging, nil)
+}
+
+// GetWarehouses returns all warehouses
+func (c *Client) GetWarehouses(ctx context.Context) ([]Warehouse, error) {
+	var warehouses []Warehouse
+	err := c.do(ctx, http.MethodGet, "/api/warehouses", nil, &warehouses)
+
+	return warehouses, err
+}
+
+// SaveWarehouse adds a warehouse or replaces the warehouse with the same ID
+func (c *Client) SaveWarehouse(ctx context.Context, warehouse Warehouse) error {
+	return c.do(ctx, http.MethodPut, "/api/warehouses/"+url.PathEscape(warehouse.ID), warehouse, nil)
+}
+
+// RemoveWarehouse removes a warehouse by its ID
+func (c *Client) RemoveWarehouse(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, "/api/warehouses/"+url.PathEscape(id), nil, nil)
+}
+
+// GetWebhooks returns all webhooks without their secrets
+func (c *Client) GetWebhooks(ctx context.Context) ([]Webhook, error) {
+	var webhooks []Webhook
+	err := c.do(ctx, http.MethodGet, "/api/webhooks", nil, &webhooks)
+
+	return webhooks, err
+}
+
+// SaveWebhook adds a webhook or replaces the webhook with the same ID
+func (c *Client) SaveWebhook(ctx context.Context, webhook Webhook) error {
+	return c.do(ctx, http.MethodPut, "/api/webhooks/"+url.PathEscape(webhook.ID), webhook, nil)
+}
+
+// RemoveWebhook removes a webhook by its ID
+func (c *Client) RemoveWebhook(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, "/api/webhooks/"+url.PathEscape(id), nil, nil)
+}
+
+// GetDeadLetters returns the events that could not be delivered to their webhooks, oldest first
+func (c *Client) GetDeadLetters(ctx context.Context) ([]DeadLetter, error) {
+	var deadLetters []DeadLetter
+	err := c.do(ctx, http.MethodGet, "/api/webhooks/dead-letters", nil, &deadLetters)
+
+	return deadLetters, err
+}
+
+// AnalyzePacks reports how well a pack set covers a range of order sizes
+func (c *Client) AnalyzePacks(ctx context.Context, req AnalysisRequest) (AnalysisResponse, error) {
+	var result AnalysisResponse
+	err := c.do(ctx, http.MethodPost, "/api/analysis", req, &result)
+
+	return result, err
+}
+
+// RecommendPacks proposes pack sizes for a distribution of orders
+func (c *Client) RecommendPacks(ctx context.Context, req RecommendationRequest) (RecommendationResponse, error) {
+	var result RecommendationResponse
+	err := c.do(ctx, http.MethodPost, "/api/recommendation", req, &result)
+
+	return result, err
+}
+
+// ComparePacks compares a candidate pack set with the available packs for a list of orders
+func (c *Client) ComparePacks(ctx context.Context, req ComparisonRequest) (ComparisonResponse, error) {
+	var result ComparisonResponse
+	err := c.do(ctx, http.MethodPost, "/api/comparison", req, &result)
+
+	return result, err
+}
+
+// GetCalculations returns the calculation history, oldest first
+func (c *Client) GetCalculations(ctx context.Context) ([]CalculationRecord, error) {
+	var records []CalculationRecord
+	err := c.do(ctx, http.MethodGet, "/api/history", nil, &records)
+
+	return records, err
+}
+
+// Limits returns the bounds of calculation requests
+func (c *Client) Limits(ctx context.Context) (Limits, error) {
+	var limits Limits
+	err := c.do(ctx, http.MethodGet, "/api/limits", nil, &limits)
+
+	return limits, err
+}
+
+// RateTables returns the carrier rate tables the shipping cost of orders is priced with
+func (c *Client) RateTables(ctx context.Context) ([]RateTable, error) {
+	var tables []RateTable
+	err := c.do(ctx, http.MethodGet, "/api/rates", nil, &tables)
+
+	return tables, err
+}
+
+// do sends a request with body encoded as JSON, if it is not nil, retrying it by the retry policy,
+// and decodes the response into out, if it is not nil
+func (c *Client) do(ctx context.Context, method, path string, body, out any) error {
+	var data []byte
+	if body != nil {
+		var err error
+		if data, err = json.Marshal(body); err != nil {
+			return err
+		}
+	}
+
+	// every request but adding a pack can be repeated with the same result
+	idempotent := method != http.MethodPost || path != "/api/packs"
+
+	for attempt := 1; ; attempt++ {
+		resp, respBody, err := c.send(ctx, method, path, data)
+		if err != nil && ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		if attempt >= c.retry.MaxAttempts || !retryable(resp, idempotent) {
+			if err != nil {
+				return err
+			}
+
+			return decodeResponse(resp, respBody, out)
+		}
+
+		if err := sleep(ctx, c.retry.backoff(attempt, resp)); err != nil {
+			return err
+		}
+	}
+}
+
+// send makes a single attempt of a request and reads its response body,
+// the response is nil if the server could not be reached
+func (c *Client) send(ctx context.Context, method, path string, data []byte) (*http.Response, []byte, error) {
+	var reader io.Reader
+	if data != nil {
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if data != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return resp, respBody, nil
+}
+
+// decodeResponse returns the error of an error response or decodes a successful response into out,
+// if it is not nil
+func decodeResponse(resp *http.Response, body []byte, out any) error {
+	if resp.StatusCode >= http.StatusBadRequest {
+		return newAPIError(resp.StatusCode, body)
+	}
+
+	if out == nil {
+		return nil
+	}
+
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("response of %s %s cannot be parsed: %w", resp.Request.Method, resp.Request.URL.Path, err)
+	}
+
+	return nil
+}
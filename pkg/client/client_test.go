@@ -0,0 +1,270 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alishercodecrafter/orderpackscalculator/internal/controller"
+	"github.com/alishercodecrafter/orderpackscalculator/internal/model"
+	"github.com/alishercodecrafter/orderpackscalculator/internal/repository"
+	"github.com/alishercodecrafter/orderpackscalculator/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestServer starts a server of the API with the default packs and the given limits
+func newTestServer(t *testing.T, limits model.Limits) *Client {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	svc := service.NewPacksService(repository.NewMemoryRepository(), service.WithLimits(limits))
+	router := gin.New()
+	controller.NewPacksController(svc).RegisterRoutes(router.Group("/api"))
+
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+
+	return New(server.URL, WithRetryPolicy(RetryPolicy{MaxAttempts: 1}))
+}
+
+// fastRetries retries quickly so that the tests do not wait
+var fastRetries = RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: 10 * time.Millisecond}
+
+func TestClient_Packs(t *testing.T) {
+	ctx := context.Background()
+	c := newTestServer(t, model.Limits{})
+
+	packs, err := c.GetPacks(ctx)
+	require.NoError(t, err)
+	require.Equal(t, Packs{{Size: 250}, {Size: 500}, {Size: 1000}, {Size: 2000}, {Size: 5000}}, packs)
+
+	require.NoError(t, c.AddPack(ctx, Pack{Size: 750}))
+	require.NoError(t, c.RemovePack(ctx, 5000))
+
+	packs, err = c.GetPacks(ctx)
+	require.NoError(t, err)
+	require.Equal(t, Packs{{Size: 250}, {Size: 500}, {Size: 750}, {Size: 1000}, {Size: 2000}}, packs)
+
+	require.NoError(t, c.ReplacePacks(ctx, Packs{{Size: 23}, {Size: 31}, {Size: 53}}))
+
+	packs, err = c.GetPacks(ctx)
+	require.NoError(t, err)
+	require.Equal(t, Packs{{Size: 23}, {Size: 31}, {Size: 53}}, packs)
+}
+
+func TestClient_Calculate(t *testing.T) {
+	ctx := context.Background()
+	c := newTestServer(t, model.Limits{MaxOrderSize: 100000})
+
+	result, err := c.Calculate(ctx, CalculationRequest{OrderSize: 12001})
+	require.NoError(t, err)
+	require.Equal(t, int64(12001), result.OrderSize)
+	require.Equal(t, map[PackSize]int64{250: 1, 2000: 1, 5000: 2}, result.Packs)
+
+	records, err := c.GetCalculations(ctx)
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	require.Equal(t, result.Packs, records[0].Packs)
+
+	limits, err := c.Limits(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(100000), limits.MaxOrderSize)
+}
+
+func TestClient_Catalogs(t *testing.T) {
+	ctx := context.Background()
+	c := newTestServer(t, model.Limits{})
+
+	catalog := Catalog{ID: "flour", Name: "Flour", Packs: Packs{{Size: 10}, {Size: 25}}}
+	require.NoError(t, c.SaveCatalog(ctx, catalog))
+
+	catalogs, err := c.GetCatalogs(ctx)
+	require.NoError(t, err)
+	require.Len(t, catalogs, 2)
+	require.Equal(t, model.DefaultCatalogID, catalogs[0].ID)
+	require.Equal(t, catalog, catalogs[1])
+
+	require.NoError(t, c.RemoveCatalog(ctx, catalog.ID))
+
+	err = c.RemoveCatalog(ctx, catalog.ID)
+	require.Equal(t, ErrorCodeCatalogNotFound, ErrorCodeOf(err))
+}
+
+func TestClient_APIErrors(t *testing.T) {
+	ctx := context.Background()
+	c := newTestServer(t, model.Limits{MaxOrderSize: 1000})
+
+	tests := []struct {
+		name       string
+		call       func() error
+		statusCode int
+		code       ErrorCode
+	}{
+		{
+			name:       "pack exists",
+			call:       func() error { return c.AddPack(ctx, Pack{Size: 250}) },
+			statusCode: http.StatusBadRequest,
+			code:       ErrorCodePackExists,
+		},
+		{
+			name:       "pack not found",
+			call:       func() error { return c.RemovePack(ctx, 42) },
+			statusCode: http.StatusBadRequest,
+			code:       ErrorCodePackNotFound,
+		},
+		{
+			name: "order size too large",
+			call: func() error {
+				_, err := c.Calculate(ctx, CalculationRequest{OrderSize: 1001})
+
+				return err
+			},
+			statusCode: http.StatusBadRequest,
+			code:       ErrorCodeOrderSizeTooLarge,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.call()
+
+			var apiErr *APIError
+			require.ErrorAs(t, err, &apiErr)
+			require.Equal(t, tt.statusCode, apiErr.StatusCode)
+			require.Equal(t, tt.code, apiErr.Code)
+			require.NotEmpty(t, apiErr.Message)
+			require.ErrorIs(t, err, &APIError{Code: tt.code})
+		})
+	}
+}
+
+func TestClient_ErrorWithoutBody(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	_, err := New(server.URL).GetPacks(context.Background())
+
+	var apiErr *APIError
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusForbidden, apiErr.StatusCode)
+	require.Equal(t, ErrorCode(""), apiErr.Code)
+	require.Equal(t, http.StatusText(http.StatusForbidden), apiErr.Message)
+}
+
+func TestClient_Retries(t *testing.T) {
+	tests := []struct {
+		name         string
+		call         func(c *Client) error
+		statuses     []int
+		wantAttempts int32
+		wantErr      bool
+	}{
+		{
+			name:         "unavailable then ok",
+			call:         func(c *Client) error { _, err := c.GetPacks(context.Background()); return err },
+			statuses:     []int{http.StatusServiceUnavailable, http.StatusBadGateway, http.StatusOK},
+			wantAttempts: 3,
+		},
+		{
+			name:         "attempts exhausted",
+			call:         func(c *Client) error { _, err := c.GetPacks(context.Background()); return err },
+			statuses:     []int{http.StatusServiceUnavailable, http.StatusServiceUnavailable, http.StatusServiceUnavailable},
+			wantAttempts: 3,
+			wantErr:      true,
+		},
+		{
+			name:         "client error is not retried",
+			call:         func(c *Client) error { _, err := c.GetPacks(context.Background()); return err },
+			statuses:     []int{http.StatusBadRequest, http.StatusOK},
+			wantAttempts: 1,
+			wantErr:      true,
+		},
+		{
+			name:         "adding a pack is not retried after a gateway timeout",
+			call:         func(c *Client) error { return c.AddPack(context.Background(), Pack{Size: 1}) },
+			statuses:     []int{http.StatusGatewayTimeout, http.StatusOK},
+			wantAttempts: 1,
+			wantErr:      true,
+		},
+		{
+			name:         "adding a pack is retried when rate limited",
+			call:         func(c *Client) error { return c.AddPack(context.Background(), Pack{Size: 1}) },
+			statuses:     []int{http.StatusTooManyRequests, http.StatusCreated},
+			wantAttempts: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attempts atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				status := tt.statuses[attempts.Add(1)-1]
+				w.WriteHeader(status)
+				if status < http.StatusBadRequest {
+					_, _ = w.Write([]byte(`[]`))
+				}
+			}))
+			defer server.Close()
+
+			err := tt.call(New(server.URL, WithRetryPolicy(fastRetries)))
+			if tt.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+			require.Equal(t, tt.wantAttempts, attempts.Load())
+		})
+	}
+}
+
+func TestClient_RetryUnreachableServer(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+
+	_, err := New(server.URL, WithRetryPolicy(fastRetries)).GetPacks(context.Background())
+	require.Error(t, err)
+
+	var apiErr *APIError
+	require.False(t, errors.As(err, &apiErr))
+}
+
+func TestClient_ContextCancelled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Retry-After", "60")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	c := New(server.URL, WithRetryPolicy(RetryPolicy{MaxAttempts: 5, InitialBackoff: time.Millisecond, MaxBackoff: time.Minute}))
+
+	start := time.Now()
+	_, err := c.GetPacks(ctx)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.Less(t, time.Since(start), 5*time.Second)
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 10, InitialBackoff: 100 * time.Millisecond, MaxBackoff: time.Second}
+
+	require.Equal(t, 100*time.Millisecond, policy.backoff(1, nil))
+	require.Equal(t, 200*time.Millisecond, policy.backoff(2, nil))
+	require.Equal(t, 800*time.Millisecond, policy.backoff(4, nil))
+	require.Equal(t, time.Second, policy.backoff(5, nil))
+	require.Equal(t, time.Second, policy.backoff(50, nil))
+
+	resp := &http.Response{Header: http.Header{"Retry-After": {"0"}}}
+	require.Equal(t, time.Duration(0), policy.backoff(3, resp))
+
+	resp.Header.Set("Retry-After", "30")
+	require.Equal(t, time.Second, policy.backoff(1, resp))
+}
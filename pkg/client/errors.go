@@ -0,0 +1,159 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// ErrorCode is a machine-readable identifier of an error returned by the API
+type ErrorCode string
+
+const (
+	// ErrorCodeInvalidRequest means the request body could not be parsed
+	ErrorCodeInvalidRequest ErrorCode = "INVALID_REQUEST"
+	// ErrorCodeInvalidPackSize means the pack size is not a positive number
+	ErrorCodeInvalidPackSize ErrorCode = "INVALID_PACK_SIZE"
+	// ErrorCodeInvalidPackCountLimits means the minimum or the maximum count of packs of a size is negative
+	// or the maximum is below the minimum
+	ErrorCodeInvalidPackCountLimits ErrorCode = "INVALID_PACK_COUNT_LIMITS"
+	// ErrorCodeInvalidPackWeight means the weight or the volume of a pack is negative
+	ErrorCodeInvalidPackWeight ErrorCode = "INVALID_PACK_WEIGHT"
+	// ErrorCodePackExists means a pack with the same size already exists
+	ErrorCodePackExists ErrorCode = "PACK_EXISTS"
+	// ErrorCodePackNotFound means no pack with the given size exists
+	ErrorCodePackNotFound ErrorCode = "PACK_NOT_FOUND"
+	// ErrorCodePacksVersionNotFound means the version of the packs is not in their event log
+	ErrorCodePacksVersionNotFound ErrorCode = "PACKS_VERSION_NOT_FOUND"
+	// ErrorCodeInvalidCatalog means the catalog ID is empty or reserved
+	ErrorCodeInvalidCatalog ErrorCode = "INVALID_CATALOG"
+	// ErrorCodeCatalogNotFound means no catalog with the given ID exists
+	ErrorCodeCatalogNotFound ErrorCode = "CATALOG_NOT_FOUND"
+	// ErrorCodeInvalidUnit means the unit of measure of a catalog is not known, or the packs or the order lines
+	// of a catalog do not match its unit
+	ErrorCodeInvalidUnit ErrorCode = "INVALID_UNIT"
+	// ErrorCodeNoPacks means there are no packs to calculate with
+	ErrorCodeNoPacks ErrorCode = "NO_PACKS"
+	// ErrorCodeTooManyPacks means the number of packs exceeds Limits.MaxPacks
+	ErrorCodeTooManyPacks ErrorCode = "TOO_MANY_PACKS"
+	// ErrorCodeInvalidOrderSize means the order size is not a positive number
+	ErrorCodeInvalidOrderSize ErrorCode = "INVALID_ORDER_SIZE"
+	// ErrorCodeOrderSizeTooLarge means the order size exceeds Limits.MaxOrderSize
+	ErrorCodeOrderSizeTooLarge ErrorCode = "ORDER_SIZE_TOO_LARGE"
+	// ErrorCodeOrderToPackRatioTooLarge means the order is too large for the smallest pack, see Limits.MaxOrderToPackRatio
+	ErrorCodeOrderToPackRatioTooLarge ErrorCode = "ORDER_TO_PACK_RATIO_TOO_LARGE"
+	// ErrorCodeInvalidOrderRange means the order size range is empty or not positive
+	ErrorCodeInvalidOrderRange ErrorCode = "INVALID_ORDER_RANGE"
+	// ErrorCodeOrderRangeTooLarge means the order size range exceeds Limits.MaxAnalysisRange
+	ErrorCodeOrderRangeTooLarge ErrorCode = "ORDER_RANGE_TOO_LARGE"
+	// ErrorCodeNoOrders means there are no orders to recommend pack sizes for
+	ErrorCodeNoOrders ErrorCode = "NO_ORDERS"
+	// ErrorCodeInvalidPackCount means the number of pack sizes to recommend is not within Limits.MaxPacks
+	ErrorCodeInvalidPackCount ErrorCode = "INVALID_PACK_COUNT"
+	// ErrorCodeNoFeasiblePacks means the order is larger than the packs can hold within their maximum counts,
+	// or smaller than their minimum counts in the undership mode
+	ErrorCodeNoFeasiblePacks ErrorCode = "NO_FEASIBLE_PACKS"
+	// ErrorCodeInvalidShipmentMode means the shipment mode of a calculation is not known
+	ErrorCodeInvalidShipmentMode ErrorCode = "INVALID_SHIPMENT_MODE"
+	// ErrorCodeInvalidTolerance means the overshipment tolerance of a catalog is negative
+	ErrorCodeInvalidTolerance ErrorCode = "INVALID_TOLERANCE"
+	// ErrorCodeToleranceExceeded means the overshipment of the best combination exceeds a rejecting tolerance
+	ErrorCodeToleranceExceeded ErrorCode = "TOLERANCE_EXCEEDED"
+	// ErrorCodeInvalidPackaging means a container level has no name or no capacity
+	ErrorCodeInvalidPackaging ErrorCode = "INVALID_PACKAGING"
+	// ErrorCodePackDoesNotFitContainer means a pack is larger than the capacity of the first container level
+	ErrorCodePackDoesNotFitContainer ErrorCode = "PACK_DOES_NOT_FIT_CONTAINER"
+	// ErrorCodeInvalidSplit means the caps of a shipment split are negative or missing,
+	// or the split is requested for a multi-line order or together with a packaging plan
+	ErrorCodeInvalidSplit ErrorCode = "INVALID_SPLIT"
+	// ErrorCodePackDoesNotFitShipment means no pack, or a pack with a minimum count, fits into a split shipment
+	ErrorCodePackDoesNotFitShipment ErrorCode = "PACK_DOES_NOT_FIT_SHIPMENT"
+	// ErrorCodeInvalidWarehouse means a warehouse has no ID, a stock item with a non-positive size,
+	// a negative quantity or a pack size listed twice
+	ErrorCodeInvalidWarehouse ErrorCode = "INVALID_WAREHOUSE"
+	// ErrorCodeWarehouseNotFound means no warehouse with the given ID exists
+	ErrorCodeWarehouseNotFound ErrorCode = "WAREHOUSE_NOT_FOUND"
+	// ErrorCodeInsufficientStock means the warehouses together do not have enough packs in stock for an order
+	ErrorCodeInsufficientStock ErrorCode = "INSUFFICIENT_STOCK"
+	// ErrorCodeInvalidRateTable means a carrier rate table has no carrier name, no bands, a negative band
+	// or the same carrier as another table
+	ErrorCodeInvalidRateTable ErrorCode = "INVALID_RATE_TABLE"
+	// ErrorCodeNoRateTables means there are no carrier rate tables to price the shipping of an order with
+	ErrorCodeNoRateTables ErrorCode = "NO_RATE_TABLES"
+	// ErrorCodeNoRateBand means the packs of an order are too heavy or too large for every carrier rate band
+	ErrorCodeNoRateBand ErrorCode = "NO_RATE_BAND"
+	// ErrorCodeInvalidWebhook means a webhook has no ID, no secret, no events or an unknown event type,
+	// or its URL is not an absolute http or https URL
+	ErrorCodeInvalidWebhook ErrorCode = "INVALID_WEBHOOK"
+	// ErrorCodeWebhookNotFound means no webhook with the given ID exists
+	ErrorCodeWebhookNotFound ErrorCode = "WEBHOOK_NOT_FOUND"
+	// ErrorCodeArithmeticOverflow means the calculation does not fit into 64-bit integers
+	ErrorCodeArithmeticOverflow ErrorCode = "ARITHMETIC_OVERFLOW"
+	// ErrorCodeCalculationTimeout means the calculation did not finish within its time budget
+	ErrorCodeCalculationTimeout ErrorCode = "CALCULATION_TIMEOUT"
+	// ErrorCodeCalculationCancelled means the calculation was cancelled by the caller
+	ErrorCodeCalculationCancelled ErrorCode = "CALCULATION_CANCELLED"
+	// ErrorCodeCalculationBudgetExceeded means the calculation exceeded its work budget
+	ErrorCodeCalculationBudgetExceeded ErrorCode = "CALCULATION_BUDGET_EXCEEDED"
+)
+
+// APIError is an error response of the API
+type APIError struct {
+	// StatusCode is the HTTP status code of the response
+	StatusCode int
+	// Code identifies the kind of the error, empty if the response has none
+	Code ErrorCode
+	// Message is the human-readable description of the error
+	Message string
+}
+
+// Error returns the message of the API with its error code
+func (e *APIError) Error() string {
+	if e.Code == "" {
+		return fmt.Sprintf("%s (HTTP %d)", e.Message, e.StatusCode)
+	}
+
+	return fmt.Sprintf("%s (%s)", e.Message, e.Code)
+}
+
+// Is reports whether target is an APIError with the same code,
+// e.g. errors.Is(err, &client.APIError{Code: client.ErrorCodePackExists})
+func (e *APIError) Is(target error) bool {
+	t, ok := target.(*APIError)
+
+	return ok && t.Code != "" && t.Code == e.Code
+}
+
+// ErrorCodeOf returns the code of the first APIError in err's chain, or an empty code if there is none
+func ErrorCodeOf(err error) ErrorCode {
+	var e *APIError
+	if errors.As(err, &e) {
+		return e.Code
+	}
+
+	return ""
+}
+
+// newAPIError creates the error of a response with its status code and body
+func newAPIError(statusCode int, body []byte) *APIError {
+	apiErr := &APIError{StatusCode: statusCode}
+
+	var resp errorResponse
+	if err := json.Unmarshal(body, &resp); err != nil || resp.Error == "" {
+		apiErr.Message = http.StatusText(statusCode)
+
+		return apiErr
+	}
+	apiErr.Code, apiErr.Message = resp.Code, resp.Error
+
+	return apiErr
+}
+
+// errorResponse represents an error returned by the API
+type errorResponse struct {
+	// Error is a human-readable description of the error
+	Error string `json:"error"`
+	// Code is a machine-readable identifier of the error
+	Code ErrorCode `json:"code,omitempty"`
+}
@@ -0,0 +1,80 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy decides how often and after which wait a failed request is sent again.
+// A request is retried when it cannot reach the server or the server responds with 429, 502, 503 or 504.
+// Adding a pack is the only request that is not idempotent, it is retried only on 429 and 503,
+// which the server or its proxy respond with before handling a request.
+type RetryPolicy struct {
+	// MaxAttempts is the largest number of attempts of a request, 1 or less disables retries
+	MaxAttempts int
+	// InitialBackoff is the wait before the first retry, doubled before each next retry
+	InitialBackoff time.Duration
+	// MaxBackoff caps the wait before a retry, including the wait asked for by a Retry-After header
+	MaxBackoff time.Duration
+}
+
+// DefaultRetryPolicy returns the retry policy of a client created without WithRetryPolicy
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: 100 * time.Millisecond,
+		MaxBackoff:     2 * time.Second,
+	}
+}
+
+// backoff returns the wait before the retry following the attempt, counted from 1,
+// or the wait asked for by the Retry-After header of the response, both capped by MaxBackoff
+func (p RetryPolicy) backoff(attempt int, resp *http.Response) time.Duration {
+	wait := p.InitialBackoff
+	for i := 1; i < attempt && wait < p.MaxBackoff; i++ {
+		wait *= 2
+	}
+
+	if resp != nil {
+		if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds >= 0 {
+			wait = time.Duration(seconds) * time.Second
+		}
+	}
+
+	if p.MaxBackoff > 0 {
+		wait = min(wait, p.MaxBackoff)
+	}
+
+	return wait
+}
+
+// retryable reports whether a request is sent again after its response, nil if the server could not be reached
+func retryable(resp *http.Response, idempotent bool) bool {
+	if resp == nil {
+		return idempotent
+	}
+
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusServiceUnavailable:
+		return true
+	case http.StatusBadGateway, http.StatusGatewayTimeout:
+		return idempotent
+	default:
+		return false
+	}
+}
+
+// sleep waits for the duration or until ctx is done
+func sleep(ctx context.Context, wait time.Duration) error {
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
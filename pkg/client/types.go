@@ -0,0 +1,547 @@
+package client
+
+import "time"
+
+// The requests and the responses of the API as they are encoded in JSON. They are defined by the client
+// rather than shared with the server, so that they only change with the API itself, see types_test.go.
+
+// PackSize represents the size of a pack
+type PackSize int64
+
+// Pack represents a pack entity with its properties
+type Pack struct {
+	Size PackSize `json:"size"`
+	// Cost is the cost of a single pack in minor currency units, e.g. cents
+	Cost int64 `json:"cost,omitempty"`
+	// MinCount is the number of packs of the size every combination must hold
+	MinCount int64 `json:"minCount,omitempty"`
+	// MaxCount is the largest number of packs of the size a combination can hold, zero means no limit
+	MaxCount int64 `json:"maxCount,omitempty"`
+	// Weight is the weight of a single pack, e.g. in grams
+	Weight int64 `json:"weight,omitempty"`
+	// Volume is the volume of a single pack, e.g. in cubic centimetres
+	Volume int64 `json:"volume,omitempty"`
+	// Content is the amount of a product measured in kilograms or litres in a single pack, e.g. 0.5,
+	// the size of the pack is the content in thousandths of the unit
+	Content Quantity `json:"content,omitempty"`
+}
+
+// Packs represents a collection of Pack entities
+type Packs []Pack
+
+// PacksEventType represents the kind of a change of the available packs
+type PacksEventType string
+
+const (
+	// PacksEventAdded means a pack was added
+	PacksEventAdded PacksEventType = "added"
+	// PacksEventRemoved means a pack was removed
+	PacksEventRemoved PacksEventType = "removed"
+	// PacksEventReplaced means all packs were replaced
+	PacksEventReplaced PacksEventType = "replaced"
+)
+
+// PackChange represents a change of the available packs in their event log
+type PackChange struct {
+	// Version is the version of the packs after the change, the position of the change in the log counted from 1
+	Version int64 `json:"version"`
+	// Type is the kind of the change
+	Type PacksEventType `json:"type"`
+	// Pack is the added pack
+	Pack *Pack `json:"pack,omitempty"`
+	// Size is the size of the removed pack
+	Size PackSize `json:"size,omitempty"`
+	// Packs are the packs that replaced all packs
+	Packs Packs `json:"packs,omitempty"`
+	// ChangedAt is the time of the change
+	ChangedAt time.Time `json:"changedAt"`
+}
+
+// Catalog represents a product with its own pack sizes
+type Catalog struct {
+	// ID identifies the catalog in order lines
+	ID string `json:"id"`
+	// Name is a human-readable name of the product
+	Name string `json:"name"`
+	// Unit is the unit of measure of the packs and the order lines of the product, UnitItem if it is empty
+	Unit Unit `json:"unit,omitempty"`
+	// Packs are the packs the product is shipped in
+	Packs Packs `json:"packs"`
+	// Tolerance bounds the overshipment of the product, the global tolerance is used if it is not set
+	Tolerance *OvershipmentTolerance `json:"tolerance,omitempty"`
+}
+
+// Warehouse represents a site that fulfils orders from its own stock of packs
+type Warehouse struct {
+	// ID identifies the warehouse in allocations
+	ID string `json:"id"`
+	// Name is a human-readable name of the warehouse
+	Name string `json:"name"`
+	// Stock lists the packs the warehouse has in stock
+	Stock []StockItem `json:"stock"`
+}
+
+// StockItem represents the packs of a size in stock at a warehouse
+type StockItem struct {
+	// Size is the size of the packs
+	Size PackSize `json:"size"`
+	// Quantity is the number of packs in stock
+	Quantity int64 `json:"quantity"`
+}
+
+// OvershipmentTolerance represents how many items above the order size can be shipped
+type OvershipmentTolerance struct {
+	// MaxItems is the largest number of items shipped above the order size, zero means no limit.
+	// The items of a product measured in kilograms or litres are thousandths of the unit
+	MaxItems int64 `json:"maxItems,omitempty"`
+	// MaxPercent is the largest overshipment in percent of the order size, zero means no limit
+	MaxPercent float64 `json:"maxPercent,omitempty"`
+	// Reject rejects a calculation that exceeds the tolerance instead of flagging it
+	Reject bool `json:"reject,omitempty"`
+}
+
+// CalculationRequest represents a request to calculate packs
+type CalculationRequest struct {
+	// OrderSize is the size of a single-line order of the available packs, used if there are no lines
+	OrderSize int64 `json:"orderSize"`
+	// Lines are the lines of a multi-line order
+	Lines []OrderLine `json:"lines,omitempty"`
+	// Packaging requests a plan of the outer containers the packs are shipped in
+	Packaging bool `json:"packaging,omitempty"`
+	// Mode chooses whether an order may be overshipped or undershipped, ShipmentModeOvership if it is empty
+	Mode ShipmentMode `json:"mode,omitempty"`
+	// Split requests to split a single-line order into shipments within the caps
+	Split *ShipmentSplit `json:"split,omitempty"`
+	// Warehouses requests to fulfil a single-line order from the pack stock of the warehouses
+	Warehouses bool `json:"warehouses,omitempty"`
+	// ShippingCost requests the shipping cost of a single-line order with the cheapest carrier,
+	// choosing the combination of packs with the lowest landed cost
+	ShippingCost bool `json:"shippingCost,omitempty"`
+}
+
+// ShipmentSplit represents the caps of each of the shipments an order is split into
+type ShipmentSplit struct {
+	// MaxItems is the largest number of items in a shipment, zero means no limit
+	MaxItems int64 `json:"maxItems,omitempty"`
+	// MaxWeight is the largest total weight of the packs in a shipment, zero means no limit
+	MaxWeight int64 `json:"maxWeight,omitempty"`
+}
+
+// ShipmentMode represents how the shipped items may differ from the order size
+type ShipmentMode string
+
+const (
+	// ShipmentModeOvership ships at least the order size, with the least items above it and then the fewest packs
+	ShipmentModeOvership ShipmentMode = "overship"
+	// ShipmentModeUndership ships at most the order size, with the least items below it and then the fewest packs,
+	// and backorders the rest
+	ShipmentModeUndership ShipmentMode = "undership"
+)
+
+// OrderLine represents a line of an order
+type OrderLine struct {
+	// Catalog is the ID of the catalog of the ordered product, the available packs are used if it is empty
+	Catalog string `json:"catalog"`
+	// Quantity is the number of ordered items of a product counted in items
+	Quantity int64 `json:"quantity"`
+	// Amount is the ordered amount of a product measured in kilograms or litres, e.g. 12.5
+	Amount Quantity `json:"amount,omitempty"`
+}
+
+// CalculationResponse represents the result of a pack calculation
+type CalculationResponse struct {
+	// OrderSize is the original size of the order, the total quantity of a multi-line order
+	OrderSize int64 `json:"orderSize"`
+	// Packs represents the calculated packs needed for the order
+	Packs map[PackSize]int64 `json:"packs"` // map of pack size to count
+	// Backorder is the number of items of a single-line order that are not shipped in the undership mode
+	Backorder int64 `json:"backorder,omitempty"`
+	// ToleranceExceeded reports whether the overshipment of the order or of any of its lines exceeds the tolerance
+	ToleranceExceeded bool `json:"toleranceExceeded,omitempty"`
+	// Lines are the packing plans of the lines of a multi-line order
+	Lines []LineResult `json:"lines,omitempty"`
+	// Totals sums up the packing plans of the lines of a multi-line order
+	Totals *ShipmentTotals `json:"totals,omitempty"`
+	// Packaging is the plan of the outer containers the packs are shipped in, if requested
+	Packaging *PackagingPlan `json:"packaging,omitempty"`
+	// Split is the plan of the shipments the order is split into, if requested
+	Split *SplitPlan `json:"split,omitempty"`
+	// Allocations are the packs each warehouse ships, if requested
+	Allocations []WarehouseAllocation `json:"allocations,omitempty"`
+	// Shipping is the shipping cost and the landed cost of the order, if requested
+	Shipping *ShippingQuote `json:"shipping,omitempty"`
+}
+
+// ShippingQuote represents the cost of shipping the packs of an order with a carrier
+type ShippingQuote struct {
+	// Carrier is the carrier with the cheapest rate for the packs
+	Carrier string `json:"carrier"`
+	// Weight is the total weight of the packs
+	Weight int64 `json:"weight"`
+	// Volume is the total volume of the packs
+	Volume int64 `json:"volume"`
+	// PackCost is the total cost of the packs in minor currency units
+	PackCost int64 `json:"packCost"`
+	// ShippingCost is the price of the carrier for shipping the packs in minor currency units
+	ShippingCost int64 `json:"shippingCost"`
+	// LandedCost is the sum of the pack cost and the shipping cost
+	LandedCost int64 `json:"landedCost"`
+}
+
+// WarehouseAllocation represents the packs a warehouse ships for an order
+type WarehouseAllocation struct {
+	// Warehouse is the ID of the warehouse
+	Warehouse string `json:"warehouse"`
+	// Packs maps a pack size to the count of packs the warehouse ships
+	Packs map[PackSize]int64 `json:"packs"`
+}
+
+// SplitPlan represents the shipments an order is split into
+type SplitPlan struct {
+	// Count is the number of shipments
+	Count int64 `json:"count"`
+	// Groups are the groups of identical shipments
+	Groups []ShipmentGroup `json:"groups"`
+}
+
+// ShipmentGroup represents identical shipments of a split order
+type ShipmentGroup struct {
+	// Count is the number of shipments in the group
+	Count int64 `json:"count"`
+	// Packs maps a pack size to the count of packs in each shipment
+	Packs map[PackSize]int64 `json:"packs"`
+	// Items is the number of items in each shipment
+	Items int64 `json:"items"`
+	// Weight is the total weight of the packs in each shipment
+	Weight int64 `json:"weight"`
+}
+
+// LineResult represents the packing plan of an order line
+type LineResult struct {
+	// Catalog is the ID of the catalog of the ordered product
+	Catalog string `json:"catalog"`
+	// Quantity is the number of ordered items
+	Quantity int64 `json:"quantity"`
+	// Unit is the unit of measure of the catalog, UnitItem if it is empty
+	Unit Unit `json:"unit,omitempty"`
+	// Amount is the ordered amount of a product measured in kilograms or litres
+	Amount Quantity `json:"amount,omitempty"`
+	// ShippedAmount is the total amount in the packs of a product measured in kilograms or litres
+	ShippedAmount Quantity `json:"shippedAmount,omitempty"`
+	// Contents maps the content of a pack of a product measured in kilograms or litres to the count of packs shipped
+	Contents map[Quantity]int64 `json:"contents,omitempty"`
+	// ToleranceExceeded reports whether the overshipment of the line exceeds the tolerance of its catalog
+	ToleranceExceeded bool `json:"toleranceExceeded,omitempty"`
+	// Shipment is the shipment of the line, in thousandths of the unit for a product measured in kilograms or litres
+	Shipment
+}
+
+// CalculationRecord represents a calculation made by the service
+type CalculationRecord struct {
+	CalculationResponse
+	// CalculatedAt is the time the calculation was made
+	CalculatedAt time.Time `json:"calculatedAt"`
+}
+
+// Limits represents the bounds a calculation request must stay within
+type Limits struct {
+	// MaxOrderSize is the largest accepted order size, zero means no limit
+	MaxOrderSize int64 `json:"maxOrderSize"`
+	// MaxPacks is the largest number of pack sizes that can be configured, zero means no limit
+	MaxPacks int `json:"maxPacks"`
+	// MaxOrderToPackRatio is the largest accepted ratio of the order size to the smallest pack size, zero means no limit
+	MaxOrderToPackRatio int64 `json:"maxOrderToPackRatio"`
+	// MaxAnalysisRange is the largest number of order sizes analyzed at once, zero means no limit
+	MaxAnalysisRange int64 `json:"maxAnalysisRange"`
+}
+
+// AnalysisRequest represents a request to analyze how well a pack set covers a range of order sizes
+type AnalysisRequest struct {
+	// Packs is the pack set to analyze, the available packs are analyzed if it is empty
+	Packs Packs `json:"packs"`
+	// MinOrderSize is the smallest order size of the range
+	MinOrderSize int64 `json:"minOrderSize"`
+	// MaxOrderSize is the largest order size of the range
+	MaxOrderSize int64 `json:"maxOrderSize"`
+	// WorstOrdersLimit is the number of orders with the worst overshipment to report, 10 if not set
+	WorstOrdersLimit int `json:"worstOrdersLimit"`
+}
+
+// AnalysisResponse represents how well a pack set covers a range of order sizes
+type AnalysisResponse struct {
+	// Packs is the analyzed pack set
+	Packs Packs `json:"packs"`
+	// MinOrderSize is the smallest order size of the range
+	MinOrderSize int64 `json:"minOrderSize"`
+	// MaxOrderSize is the largest order size of the range
+	MaxOrderSize int64 `json:"maxOrderSize"`
+	// AverageOvershipment is the average number of items shipped above the order size
+	AverageOvershipment float64 `json:"averageOvershipment"`
+	// AverageOvershipmentPercent is the average overshipment in percent of the order size
+	AverageOvershipmentPercent float64 `json:"averageOvershipmentPercent"`
+	// MaxOvershipment is the largest number of items shipped above the order size
+	MaxOvershipment int64 `json:"maxOvershipment"`
+	// MaxOvershipmentPercent is the largest overshipment in percent of the order size
+	MaxOvershipmentPercent float64 `json:"maxOvershipmentPercent"`
+	// ExactOrders is the number of orders of the range that are matched exactly
+	ExactOrders int64 `json:"exactOrders"`
+	// GreatestCommonDivisor is the greatest common divisor of the pack sizes
+	GreatestCommonDivisor int64 `json:"greatestCommonDivisor"`
+	// LargestUnmatchableOrder is the largest order size that cannot be matched exactly (the Frobenius number),
+	// zero if every order size can be matched and null if infinitely many cannot as the pack sizes share a divisor
+	// or if it is not known as the counts of packs are limited
+	LargestUnmatchableOrder *int64 `json:"largestUnmatchableOrder"`
+	// PackCountDistribution maps a number of packs to the number of orders of the range shipped in that many packs
+	PackCountDistribution map[int64]int64 `json:"packCountDistribution"`
+	// WorstOrders are the orders of the range with the largest overshipment
+	WorstOrders []OrderOvershipment `json:"worstOrders"`
+	// InfeasibleOrders is the number of orders of the range that are larger than the packs can hold within
+	// their maximum counts, they are left out of the other figures
+	InfeasibleOrders int64 `json:"infeasibleOrders,omitempty"`
+	// SmallestInfeasibleOrder is the smallest order size of the range the packs cannot hold, null if there is none
+	SmallestInfeasibleOrder *int64 `json:"smallestInfeasibleOrder,omitempty"`
+}
+
+// OrderOvershipment represents the packs shipped for an order and the items shipped above its size
+type OrderOvershipment struct {
+	// OrderSize is the size of the order
+	OrderSize int64 `json:"orderSize"`
+	// ShippedItems is the total number of items in the packs
+	ShippedItems int64 `json:"shippedItems"`
+	// Overshipment is the number of items shipped above the order size
+	Overshipment int64 `json:"overshipment"`
+	// Packs maps a pack size to the count of packs shipped
+	Packs map[PackSize]int64 `json:"packs"`
+}
+
+// RecommendationRequest represents a request to recommend pack sizes for a distribution of orders
+type RecommendationRequest struct {
+	// OrderSizes are the historical order sizes, the calculation history is used if it is empty
+	OrderSizes []int64 `json:"orderSizes"`
+	// PackCount is the number of pack sizes to recommend
+	PackCount int `json:"packCount"`
+}
+
+// RecommendationResponse represents recommended pack sizes and their expected improvement
+type RecommendationResponse struct {
+	// Packs are the recommended packs
+	Packs Packs `json:"packs"`
+	// OrderCount is the number of orders the recommendation is based on
+	OrderCount int64 `json:"orderCount"`
+	// Current is the score of the available packs for the orders
+	Current PackSetScore `json:"current"`
+	// Recommended is the score of the recommended packs for the orders
+	Recommended PackSetScore `json:"recommended"`
+	// OvershipmentReduction is the number of items the recommended packs ship less than the available packs
+	OvershipmentReduction int64 `json:"overshipmentReduction"`
+	// PackCountReduction is the number of packs the recommended packs ship less than the available packs
+	PackCountReduction int64 `json:"packCountReduction"`
+}
+
+// PackSetScore represents how well a pack set ships a list of orders
+type PackSetScore struct {
+	// Packs is the scored pack set
+	Packs Packs `json:"packs"`
+	// TotalOvershipment is the number of items shipped above the order sizes
+	TotalOvershipment int64 `json:"totalOvershipment"`
+	// TotalPacks is the number of packs shipped
+	TotalPacks int64 `json:"totalPacks"`
+	// AverageOvershipment is the average number of items shipped above an order size
+	AverageOvershipment float64 `json:"averageOvershipment"`
+	// AveragePacks is the average number of packs shipped for an order
+	AveragePacks float64 `json:"averagePacks"`
+}
+
+// ComparisonRequest represents a request to compare a candidate pack set with the available packs
+type ComparisonRequest struct {
+	// Packs is the candidate pack set
+	Packs Packs `json:"packs"`
+	// OrderSizes are the order sizes to compare the pack sets for
+	OrderSizes []int64 `json:"orderSizes"`
+}
+
+// ComparisonResponse represents how a candidate pack set ships orders compared with the available packs
+type ComparisonResponse struct {
+	// CurrentPacks are the available packs
+	CurrentPacks Packs `json:"currentPacks"`
+	// CandidatePacks is the candidate pack set
+	CandidatePacks Packs `json:"candidatePacks"`
+	// Orders compares the shipments of each order
+	Orders []OrderComparison `json:"orders"`
+	// Current sums up the shipments with the available packs
+	Current ShipmentTotals `json:"current"`
+	// Candidate sums up the shipments with the candidate pack set
+	Candidate ShipmentTotals `json:"candidate"`
+	// ShippedItemsDelta is the change of the shipped items with the candidate pack set
+	ShippedItemsDelta int64 `json:"shippedItemsDelta"`
+	// PackCountDelta is the change of the shipped packs with the candidate pack set
+	PackCountDelta int64 `json:"packCountDelta"`
+	// CostDelta is the change of the cost of the shipped packs with the candidate pack set
+	CostDelta int64 `json:"costDelta"`
+}
+
+// OrderComparison represents the shipments of an order with the available and the candidate packs
+type OrderComparison struct {
+	// OrderSize is the size of the order
+	OrderSize int64 `json:"orderSize"`
+	// Current is the shipment with the available packs
+	Current Shipment `json:"current"`
+	// Candidate is the shipment with the candidate pack set
+	Candidate Shipment `json:"candidate"`
+	// Changed reports whether the shipments differ
+	Changed bool `json:"changed"`
+}
+
+// Shipment represents the packs shipped for an order
+type Shipment struct {
+	// Packs maps a pack size to the count of packs shipped
+	Packs map[PackSize]int64 `json:"packs"`
+	// ShippedItems is the total number of items in the packs
+	ShippedItems int64 `json:"shippedItems"`
+	// Overshipment is the number of items shipped above the order size
+	Overshipment int64 `json:"overshipment"`
+	// Backorder is the number of items shipped below the order size
+	Backorder int64 `json:"backorder,omitempty"`
+	// PackCount is the total number of packs
+	PackCount int64 `json:"packCount"`
+	// Cost is the total cost of the packs in minor currency units
+	Cost int64 `json:"cost"`
+}
+
+// ShipmentTotals represents the sums of a list of shipments.
+// The items of the order lines of products measured in kilograms or litres are left out, their packs are counted.
+type ShipmentTotals struct {
+	// ShippedItems is the total number of items shipped
+	ShippedItems int64 `json:"shippedItems"`
+	// Overshipment is the total number of items shipped above the order sizes
+	Overshipment int64 `json:"overshipment"`
+	// Backorder is the total number of items shipped below the order sizes
+	Backorder int64 `json:"backorder,omitempty"`
+	// PackCount is the total number of packs shipped
+	PackCount int64 `json:"packCount"`
+	// Cost is the total cost of the packs shipped in minor currency units
+	Cost int64 `json:"cost"`
+}
+
+// ContainerLevel represents a level of outer containers, e.g. cartons or pallets
+type ContainerLevel struct {
+	// Name is the name of the containers of the level
+	Name string `json:"name"`
+	// Capacity is the number of items a container of the first level holds,
+	// or the number of inner containers a container of an outer level holds
+	Capacity int64 `json:"capacity"`
+}
+
+// Packaging represents the hierarchy of outer containers packs are shipped in
+type Packaging struct {
+	// Levels are the levels of outer containers, innermost first
+	Levels []ContainerLevel `json:"levels"`
+}
+
+// PackagingPlan represents how the packs of a shipment are nested in outer containers
+type PackagingPlan struct {
+	// Levels are the containers of each level, innermost first
+	Levels []ContainerPlan `json:"levels"`
+}
+
+// ContainerPlan represents the containers of a level of a packaging plan
+type ContainerPlan struct {
+	// Name is the name of the containers of the level
+	Name string `json:"name"`
+	// Count is the number of containers of the level
+	Count int64 `json:"count"`
+	// Groups are the groups of identically filled containers of the level
+	Groups []ContainerGroup `json:"groups"`
+}
+
+// ContainerGroup represents identically filled containers
+type ContainerGroup struct {
+	// Count is the number of containers in the group
+	Count int64 `json:"count"`
+	// Packs maps a pack size to the count of packs in each container of the first level
+	Packs map[PackSize]int64 `json:"packs,omitempty"`
+	// Contents is the number of inner containers in each container of an outer level
+	Contents int64 `json:"contents,omitempty"`
+}
+
+// RateTable represents the shipping prices of a carrier by weight and volume band
+type RateTable struct {
+	// Carrier is the name of the carrier
+	Carrier string `json:"carrier"`
+	// Bands are the bands of the table, the cheapest band a shipment fits into prices it
+	Bands []RateBand `json:"bands"`
+}
+
+// RateBand represents the price of shipping packs up to a total weight and volume
+type RateBand struct {
+	// MaxWeight is the largest total weight of the packs in the band, zero means no limit
+	MaxWeight int64 `json:"maxWeight,omitempty"`
+	// MaxVolume is the largest total volume of the packs in the band, zero means no limit
+	MaxVolume int64 `json:"maxVolume,omitempty"`
+	// Price is the price of shipping in minor currency units
+	Price int64 `json:"price"`
+}
+
+// WebhookEventType represents the kind of an event a webhook is notified of
+type WebhookEventType string
+
+const (
+	// WebhookEventPacksAdded means a pack was added
+	WebhookEventPacksAdded WebhookEventType = "packs.added"
+	// WebhookEventPacksRemoved means a pack was removed
+	WebhookEventPacksRemoved WebhookEventType = "packs.removed"
+	// WebhookEventPacksReplaced means all packs were replaced
+	WebhookEventPacksReplaced WebhookEventType = "packs.replaced"
+	// WebhookEventCalculationCompleted means a calculation was made and recorded in the history
+	WebhookEventCalculationCompleted WebhookEventType = "calculation.completed"
+)
+
+// Webhook represents a subscription of a downstream system to the events of the service
+type Webhook struct {
+	// ID identifies the webhook
+	ID string `json:"id"`
+	// URL is the absolute http or https URL the events are posted to
+	URL string `json:"url"`
+	// Events are the kinds of events the webhook is notified of
+	Events []WebhookEventType `json:"events"`
+	// Secret signs the payloads posted to the webhook, it is never returned by the API
+	Secret string `json:"secret,omitempty"`
+}
+
+// WebhookEvent represents an event posted to the webhooks subscribed to its kind
+type WebhookEvent struct {
+	// ID identifies the event, it is the same in every delivery and every retry of the event
+	ID string `json:"id"`
+	// Type is the kind of the event
+	Type WebhookEventType `json:"type"`
+	// CreatedAt is the time the event occurred
+	CreatedAt time.Time `json:"createdAt"`
+	// Data is a PacksEvent for the changes of the packs and a CalculationRecord for the calculations
+	Data any `json:"data"`
+}
+
+// DeadLetter represents an event that could not be delivered to a webhook within its attempts
+type DeadLetter struct {
+	// Webhook is the ID of the webhook
+	Webhook string `json:"webhook"`
+	// URL is the URL the event was posted to
+	URL string `json:"url"`
+	// Event is the undelivered event
+	Event WebhookEvent `json:"event"`
+	// Attempts is the number of deliveries attempted
+	Attempts int `json:"attempts"`
+	// Error describes why the last attempt failed
+	Error string `json:"error"`
+	// FailedAt is the time the last attempt failed
+	FailedAt time.Time `json:"failedAt"`
+}
+
+// addPackRequest represents a request to add a new pack
+type addPackRequest struct {
+	Pack Pack `json:"pack"`
+}
+
+// replacePacksRequest represents a request to replace all packs
+type replacePacksRequest struct {
+	Packs Packs `json:"packs"`
+}
@@ -0,0 +1,343 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/alishercodecrafter/orderpackscalculator/internal/model"
+	"github.com/stretchr/testify/require"
+)
+
+// requireRoundTrip checks that a value of the server decodes into the type of the client without unknown fields
+// and that both encode to the same JSON
+func requireRoundTrip[T any](t *testing.T, server any) {
+	t.Helper()
+
+	data, err := json.Marshal(server)
+	require.NoError(t, err)
+
+	var value T
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	require.NoError(t, decoder.Decode(&value))
+
+	encoded, err := json.Marshal(value)
+	require.NoError(t, err)
+	require.JSONEq(t, string(data), string(encoded))
+}
+
+func TestTypes_RoundTrip(t *testing.T) {
+	changedAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	largestUnmatchableOrder := int64(249)
+	smallestInfeasibleOrder := int64(991)
+	pack := model.Pack{Size: 500, Cost: 12, MinCount: 1, MaxCount: 4, Weight: 300, Volume: 20, Content: 500}
+	packs := model.Packs{pack, {Size: 250}}
+	shipment := model.Shipment{
+		Packs:        map[model.PackSize]int64{500: 1, 250: 1},
+		ShippedItems: 750,
+		Overshipment: 249,
+		Backorder:    1,
+		PackCount:    2,
+		Cost:         12,
+	}
+	totals := model.ShipmentTotals{ShippedItems: 750, Overshipment: 249, Backorder: 1, PackCount: 2, Cost: 12}
+	calculation := model.CalculationResponse{
+		OrderSize:         501,
+		Packs:             map[model.PackSize]int64{500: 1, 250: 1},
+		Backorder:         1,
+		ToleranceExceeded: true,
+		Lines: []model.LineResult{{
+			Catalog:           "flour",
+			Quantity:          12500,
+			Unit:              model.UnitKilogram,
+			Amount:            12500,
+			ShippedAmount:     13000,
+			Contents:          map[model.Quantity]int64{500: 26},
+			ToleranceExceeded: true,
+			Shipment:          shipment,
+		}},
+		Totals: &totals,
+		Packaging: &model.PackagingPlan{Levels: []model.ContainerPlan{
+			{Name: "carton", Count: 1, Groups: []model.ContainerGroup{{Count: 1, Packs: map[model.PackSize]int64{500: 1}}}},
+			{Name: "pallet", Count: 1, Groups: []model.ContainerGroup{{Count: 1, Contents: 1}}},
+		}},
+		Split: &model.SplitPlan{Count: 2, Groups: []model.ShipmentGroup{
+			{Count: 2, Packs: map[model.PackSize]int64{250: 1}, Items: 250, Weight: 300},
+		}},
+		Allocations: []model.WarehouseAllocation{{Warehouse: "north", Packs: map[model.PackSize]int64{500: 1}}},
+		Shipping: &model.ShippingQuote{
+			Carrier:      "fast",
+			Weight:       300,
+			Volume:       20,
+			PackCost:     12,
+			ShippingCost: 499,
+			LandedCost:   511,
+		},
+	}
+	event := model.WebhookEvent{
+		ID:        "9f1c",
+		Type:      model.WebhookEventPacksAdded,
+		CreatedAt: changedAt,
+		Data:      model.PacksEvent{Type: model.PacksEventAdded, Packs: packs},
+	}
+
+	testCases := []struct {
+		name   string
+		server any
+		check  func(t *testing.T, server any)
+	}{
+		{name: "Packs", server: packs, check: requireRoundTrip[Packs]},
+		{
+			name: "PackChange",
+			server: []model.PackChange{
+				{Version: 1, Type: model.PacksEventAdded, Pack: &pack, ChangedAt: changedAt},
+				{Version: 2, Type: model.PacksEventRemoved, Size: 250, ChangedAt: changedAt},
+				{Version: 3, Type: model.PacksEventReplaced, Packs: packs, ChangedAt: changedAt},
+			},
+			check: requireRoundTrip[[]PackChange],
+		},
+		{
+			name: "Catalog",
+			server: model.Catalog{
+				ID:        "flour",
+				Name:      "Flour",
+				Unit:      model.UnitKilogram,
+				Packs:     packs,
+				Tolerance: &model.OvershipmentTolerance{MaxItems: 100, MaxPercent: 2.5, Reject: true},
+			},
+			check: requireRoundTrip[Catalog],
+		},
+		{
+			name:   "Warehouse",
+			server: model.Warehouse{ID: "north", Name: "North", Stock: []model.StockItem{{Size: 500, Quantity: 10}}},
+			check:  requireRoundTrip[Warehouse],
+		},
+		{
+			name: "CalculationRequest",
+			server: model.CalculationRequest{
+				OrderSize:    501,
+				Lines:        []model.OrderLine{{Catalog: "flour", Quantity: 2, Amount: 12500}},
+				Packaging:    true,
+				Mode:         model.ShipmentModeUndership,
+				Split:        &model.ShipmentSplit{MaxItems: 250, MaxWeight: 1000},
+				Warehouses:   true,
+				ShippingCost: true,
+			},
+			check: requireRoundTrip[CalculationRequest],
+		},
+		{name: "CalculationResponse", server: calculation, check: requireRoundTrip[CalculationResponse]},
+		{
+			name:   "CalculationRecord",
+			server: []model.CalculationRecord{{CalculationResponse: calculation, CalculatedAt: changedAt}},
+			check:  requireRoundTrip[[]CalculationRecord],
+		},
+		{
+			name: "Packaging",
+			server: model.Packaging{Levels: []model.ContainerLevel{
+				{Name: "carton", Capacity: 1000},
+				{Name: "pallet", Capacity: 40},
+			}},
+			check: requireRoundTrip[Packaging],
+		},
+		{
+			name:   "AnalysisRequest",
+			server: model.AnalysisRequest{Packs: packs, MinOrderSize: 1, MaxOrderSize: 1000, WorstOrdersLimit: 5},
+			check:  requireRoundTrip[AnalysisRequest],
+		},
+		{
+			name: "AnalysisResponse",
+			server: model.AnalysisResponse{
+				Packs:                      packs,
+				MinOrderSize:               1,
+				MaxOrderSize:               1000,
+				AverageOvershipment:        124.5,
+				AverageOvershipmentPercent: 12.5,
+				MaxOvershipment:            249,
+				MaxOvershipmentPercent:     99.6,
+				ExactOrders:                4,
+				GreatestCommonDivisor:      250,
+				LargestUnmatchableOrder:    &largestUnmatchableOrder,
+				PackCountDistribution:      map[int64]int64{1: 500, 2: 500},
+				WorstOrders: []model.OrderOvershipment{
+					{OrderSize: 1, ShippedItems: 250, Overshipment: 249, Packs: map[model.PackSize]int64{250: 1}},
+				},
+				InfeasibleOrders:        10,
+				SmallestInfeasibleOrder: &smallestInfeasibleOrder,
+			},
+			check: requireRoundTrip[AnalysisResponse],
+		},
+		{
+			name:   "RecommendationRequest",
+			server: model.RecommendationRequest{OrderSizes: []int64{501, 12001}, PackCount: 3},
+			check:  requireRoundTrip[RecommendationRequest],
+		},
+		{
+			name: "RecommendationResponse",
+			server: model.RecommendationResponse{
+				Packs:      packs,
+				OrderCount: 2,
+				Current: model.PackSetScore{
+					Packs:               packs,
+					TotalOvershipment:   498,
+					TotalPacks:          4,
+					AverageOvershipment: 249,
+					AveragePacks:        2,
+				},
+				Recommended:           model.PackSetScore{Packs: packs, TotalPacks: 2, AveragePacks: 1},
+				OvershipmentReduction: 498,
+				PackCountReduction:    2,
+			},
+			check: requireRoundTrip[RecommendationResponse],
+		},
+		{
+			name:   "ComparisonRequest",
+			server: model.ComparisonRequest{Packs: packs, OrderSizes: []int64{501}},
+			check:  requireRoundTrip[ComparisonRequest],
+		},
+		{
+			name: "ComparisonResponse",
+			server: model.ComparisonResponse{
+				CurrentPacks:      packs,
+				CandidatePacks:    packs,
+				Orders:            []model.OrderComparison{{OrderSize: 501, Current: shipment, Candidate: shipment, Changed: true}},
+				Current:           totals,
+				Candidate:         totals,
+				ShippedItemsDelta: -249,
+				PackCountDelta:    -1,
+				CostDelta:         -12,
+			},
+			check: requireRoundTrip[ComparisonResponse],
+		},
+		{
+			name:   "Limits",
+			server: model.Limits{MaxOrderSize: 1000, MaxPacks: 10, MaxOrderToPackRatio: 100, MaxAnalysisRange: 500},
+			check:  requireRoundTrip[Limits],
+		},
+		{
+			name: "RateTable",
+			server: []model.RateTable{{Carrier: "fast", Bands: []model.RateBand{
+				{MaxWeight: 1000, MaxVolume: 50, Price: 499},
+				{Price: 999},
+			}}},
+			check: requireRoundTrip[[]RateTable],
+		},
+		{
+			name: "Webhook",
+			server: model.Webhook{
+				ID:     "erp",
+				URL:    "https://erp.example.com/hooks",
+				Events: []model.WebhookEventType{model.WebhookEventPacksReplaced, model.WebhookEventCalculationCompleted},
+				Secret: "s3cret",
+			},
+			check: requireRoundTrip[Webhook],
+		},
+		{
+			name: "DeadLetter",
+			server: []model.DeadLetter{{
+				Webhook:  "erp",
+				URL:      "https://erp.example.com/hooks",
+				Event:    event,
+				Attempts: 5,
+				Error:    "unexpected status 500",
+				FailedAt: changedAt,
+			}},
+			check: requireRoundTrip[[]DeadLetter],
+		},
+		{
+			name:   "AddPackRequest",
+			server: model.AddPackRequest{Pack: pack},
+			check:  requireRoundTrip[addPackRequest],
+		},
+		{
+			name:   "ReplacePacksRequest",
+			server: model.ReplacePacksRequest{Packs: packs},
+			check:  requireRoundTrip[replacePacksRequest],
+		},
+		{
+			name:   "ErrorResponse",
+			server: model.ErrorResponse{Error: "pack size 500 already exists", Code: model.ErrorCodePackExists},
+			check:  requireRoundTrip[errorResponse],
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.check(t, tc.server)
+		})
+	}
+}
+
+func TestTypes_Constants(t *testing.T) {
+	require.Equal(t, string(model.ShipmentModeOvership), string(ShipmentModeOvership))
+	require.Equal(t, string(model.ShipmentModeUndership), string(ShipmentModeUndership))
+
+	require.Equal(t, string(model.PacksEventAdded), string(PacksEventAdded))
+	require.Equal(t, string(model.PacksEventRemoved), string(PacksEventRemoved))
+	require.Equal(t, string(model.PacksEventReplaced), string(PacksEventReplaced))
+
+	require.Equal(t, string(model.WebhookEventPacksAdded), string(WebhookEventPacksAdded))
+	require.Equal(t, string(model.WebhookEventPacksRemoved), string(WebhookEventPacksRemoved))
+	require.Equal(t, string(model.WebhookEventPacksReplaced), string(WebhookEventPacksReplaced))
+	require.Equal(t, string(model.WebhookEventCalculationCompleted), string(WebhookEventCalculationCompleted))
+
+	require.Equal(t, string(model.UnitItem), string(UnitItem))
+	require.Equal(t, string(model.UnitKilogram), string(UnitKilogram))
+	require.Equal(t, string(model.UnitLitre), string(UnitLitre))
+
+	codes := map[ErrorCode]model.ErrorCode{
+		ErrorCodeInvalidRequest:            model.ErrorCodeInvalidRequest,
+		ErrorCodeInvalidPackSize:           model.ErrorCodeInvalidPackSize,
+		ErrorCodeInvalidPackCountLimits:    model.ErrorCodeInvalidPackCountLimits,
+		ErrorCodeInvalidPackWeight:         model.ErrorCodeInvalidPackWeight,
+		ErrorCodePackExists:                model.ErrorCodePackExists,
+		ErrorCodePackNotFound:              model.ErrorCodePackNotFound,
+		ErrorCodePacksVersionNotFound:      model.ErrorCodePacksVersionNotFound,
+		ErrorCodeInvalidCatalog:            model.ErrorCodeInvalidCatalog,
+		ErrorCodeCatalogNotFound:           model.ErrorCodeCatalogNotFound,
+		ErrorCodeInvalidUnit:               model.ErrorCodeInvalidUnit,
+		ErrorCodeNoPacks:                   model.ErrorCodeNoPacks,
+		ErrorCodeTooManyPacks:              model.ErrorCodeTooManyPacks,
+		ErrorCodeInvalidOrderSize:          model.ErrorCodeInvalidOrderSize,
+		ErrorCodeOrderSizeTooLarge:         model.ErrorCodeOrderSizeTooLarge,
+		ErrorCodeOrderToPackRatioTooLarge:  model.ErrorCodeOrderToPackRatioTooLarge,
+		ErrorCodeInvalidOrderRange:         model.ErrorCodeInvalidOrderRange,
+		ErrorCodeOrderRangeTooLarge:        model.ErrorCodeOrderRangeTooLarge,
+		ErrorCodeNoOrders:                  model.ErrorCodeNoOrders,
+		ErrorCodeInvalidPackCount:          model.ErrorCodeInvalidPackCount,
+		ErrorCodeNoFeasiblePacks:           model.ErrorCodeNoFeasiblePacks,
+		ErrorCodeInvalidShipmentMode:       model.ErrorCodeInvalidShipmentMode,
+		ErrorCodeInvalidTolerance:          model.ErrorCodeInvalidTolerance,
+		ErrorCodeToleranceExceeded:         model.ErrorCodeToleranceExceeded,
+		ErrorCodeInvalidPackaging:          model.ErrorCodeInvalidPackaging,
+		ErrorCodePackDoesNotFitContainer:   model.ErrorCodePackDoesNotFitContainer,
+		ErrorCodeInvalidSplit:              model.ErrorCodeInvalidSplit,
+		ErrorCodePackDoesNotFitShipment:    model.ErrorCodePackDoesNotFitShipment,
+		ErrorCodeInvalidWarehouse:          model.ErrorCodeInvalidWarehouse,
+		ErrorCodeWarehouseNotFound:         model.ErrorCodeWarehouseNotFound,
+		ErrorCodeInsufficientStock:         model.ErrorCodeInsufficientStock,
+		ErrorCodeInvalidRateTable:          model.ErrorCodeInvalidRateTable,
+		ErrorCodeNoRateTables:              model.ErrorCodeNoRateTables,
+		ErrorCodeNoRateBand:                model.ErrorCodeNoRateBand,
+		ErrorCodeInvalidWebhook:            model.ErrorCodeInvalidWebhook,
+		ErrorCodeWebhookNotFound:           model.ErrorCodeWebhookNotFound,
+		ErrorCodeArithmeticOverflow:        model.ErrorCodeArithmeticOverflow,
+		ErrorCodeCalculationTimeout:        model.ErrorCodeCalculationTimeout,
+		ErrorCodeCalculationCancelled:      model.ErrorCodeCalculationCancelled,
+		ErrorCodeCalculationBudgetExceeded: model.ErrorCodeCalculationBudgetExceeded,
+	}
+	for code, serverCode := range codes {
+		require.Equal(t, string(serverCode), string(code))
+	}
+}
+
+func TestParseQuantity(t *testing.T) {
+	quantity, err := ParseQuantity("12.5")
+	require.NoError(t, err)
+	require.Equal(t, Quantity(12500), quantity)
+	require.Equal(t, "12.5", quantity.String())
+
+	_, err = ParseQuantity("1.2345")
+	require.Error(t, err)
+}
@@ -0,0 +1,36 @@
+package packing
+
+import "math"
+
+// CheckedAdd returns a + b and reports whether the sum fits into int64
+func CheckedAdd(a, b int64) (int64, bool) {
+	return addInt64(a, b)
+}
+
+// CheckedMul returns a * b and reports whether the product fits into int64
+func CheckedMul(a, b int64) (int64, bool) {
+	return mulInt64(a, b)
+}
+
+// addInt64 returns a + b and reports whether the sum fits into int64
+func addInt64(a, b int64) (int64, bool) {
+	if (b > 0 && a > math.MaxInt64-b) || (b < 0 && a < math.MinInt64-b) {
+		return 0, false
+	}
+
+	return a + b, true
+}
+
+// mulInt64 returns a * b and reports whether the product fits into int64
+func mulInt64(a, b int64) (int64, bool) {
+	if a == 0 || b == 0 {
+		return 0, true
+	}
+
+	product := a * b
+	if product/b != a || (a == -1 && b == math.MinInt64) || (b == -1 && a == math.MinInt64) {
+		return 0, false
+	}
+
+	return product, true
+}
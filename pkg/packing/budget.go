@@ -0,0 +1,41 @@
+package packing
+
+import "context"
+
+// Budget bounds the work of the solvers that share it, e.g. all the solvers of a single request.
+// It stops them once its context is done or once they have made more search steps than its maximum.
+// A Budget is not safe for concurrent use.
+type Budget struct {
+	// ctx is checked on every step to stop the search once it is done
+	ctx context.Context
+	// maxSteps is the largest number of search steps, zero means no limit
+	maxSteps int64
+	// steps is the number of search steps made so far
+	steps int64
+}
+
+// NewBudget creates a budget bound by ctx and by maxSteps search steps, zero means no limit of steps
+func NewBudget(ctx context.Context, maxSteps int64) *Budget {
+	return &Budget{ctx: ctx, maxSteps: maxSteps}
+}
+
+// Step accounts for n search steps. It returns ErrBudgetExceeded once more steps than the maximum have been made
+// and the error of the context once it is done.
+func (b *Budget) Step(n int64) error {
+	b.steps += n
+	if b.maxSteps > 0 && b.steps > b.maxSteps {
+		return ErrBudgetExceeded
+	}
+
+	return b.ctx.Err()
+}
+
+// Allows reports whether n more search steps fit into the budget
+func (b *Budget) Allows(n int64) bool {
+	return b.maxSteps == 0 || n <= b.maxSteps-b.steps
+}
+
+// Steps returns the number of search steps made so far
+func (b *Budget) Steps() int64 {
+	return b.steps
+}
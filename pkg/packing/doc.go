@@ -0,0 +1,23 @@
+// Package packing finds the combinations of whole packs that ship an order, without a server or a repository.
+//
+// A combination follows the rules of the calculator:
+//
+//  1. Only whole packs can be sent, packs cannot be broken open.
+//  2. Within the constraints of Rule #1, send out the least amount of items to fulfil the order.
+//  3. Within the constraints of Rules #1 and #2, send out as few packs as possible to fulfil the order.
+//
+// In the Overship mode a combination ships at least the order size, in the Undership mode at most the order size
+// and the rest is backordered. Every combination also holds at least the minimum count and at most the maximum
+// count of packs of each size. Compare ranks any two combinations of an order by the same rules.
+//
+// Solve finds the best combination of a single order. A Solver prepares the packs once for all orders up to
+// a maximum size, so that many orders are solved quickly. A Budget bounds the work of one or more solvers
+// by a context and a number of search steps. CheckedAdd and CheckedMul are the int64 arithmetic of the package
+// that reports overflows instead of wrapping around.
+//
+// The API of the package follows semantic versioning, see Version: incompatible changes to the exported
+// identifiers or to the combinations returned for the same packs only come with a new major version.
+package packing
+
+// Version is the semantic version of the API of the package
+const Version = "1.1.0"
@@ -0,0 +1,23 @@
+package packing
+
+import "errors"
+
+var (
+	// ErrNoPacks is returned when there are no packs to solve with
+	ErrNoPacks = errors.New("no packs")
+	// ErrInvalidPack is returned when a pack size is not positive or listed twice, or its count limits are negative
+	// or the maximum is below the minimum
+	ErrInvalidPack = errors.New("invalid pack")
+	// ErrInvalidMode is returned when the shipment mode is not known
+	ErrInvalidMode = errors.New("invalid shipment mode")
+	// ErrInvalidOrderSize is returned when an order size is not positive or larger than the maximum order size
+	// of the solver
+	ErrInvalidOrderSize = errors.New("invalid order size")
+	// ErrInfeasible is returned when the orders are larger than the packs can hold within their maximum counts,
+	// or smaller than the minimum counts of the packs in the undership mode
+	ErrInfeasible = errors.New("no feasible packs")
+	// ErrBudgetExceeded is returned when a solver makes more search steps than its budget allows
+	ErrBudgetExceeded = errors.New("work budget exceeded")
+	// ErrOverflow is returned when a calculation does not fit into 64-bit integers
+	ErrOverflow = errors.New("arithmetic overflow")
+)
@@ -0,0 +1,82 @@
+package packing_test
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/alishercodecrafter/orderpackscalculator/pkg/packing"
+)
+
+func ExampleSolve() {
+	packs := []packing.Pack{{Size: 250}, {Size: 500}, {Size: 1000}, {Size: 2000}, {Size: 5000}}
+
+	combination, err := packing.Solve(packs, 12001)
+	if err != nil {
+		fmt.Println(err)
+
+		return
+	}
+
+	items, count, _ := combination.Totals()
+	fmt.Println(combination, items, count)
+	// Output: map[250:1 2000:1 5000:2] 12250 4
+}
+
+func ExampleSolve_undership() {
+	packs := []packing.Pack{{Size: 23}, {Size: 31}, {Size: 53}}
+
+	combination, err := packing.Solve(packs, 100, packing.WithMode(packing.Undership))
+	if err != nil {
+		fmt.Println(err)
+
+		return
+	}
+
+	fmt.Println(combination)
+	// Output: map[23:3 31:1]
+}
+
+func ExampleSolver() {
+	packs := []packing.Pack{{Size: 250}, {Size: 500, MaxCount: 1}, {Size: 1000, MinCount: 1}}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	solver, err := packing.NewSolver(packs, 3000, packing.WithBudget(packing.NewBudget(ctx, 1_000_000)))
+	if err != nil {
+		fmt.Println(err)
+
+		return
+	}
+
+	for _, orderSize := range []int64{1, 1251, 2600} {
+		combination, err := solver.Solve(orderSize)
+		if err != nil {
+			fmt.Println(err)
+
+			return
+		}
+
+		fmt.Println(orderSize, combination)
+	}
+	// Output:
+	// 1 map[1000:1]
+	// 1251 map[500:1 1000:1]
+	// 2600 map[250:1 500:1 1000:2]
+}
+
+func ExampleCompare() {
+	a := packing.Combination{500: 1, 250: 1}
+	b := packing.Combination{250: 3}
+
+	result, err := packing.Compare(501, packing.Overship, a, b)
+	if err != nil {
+		fmt.Println(err)
+
+		return
+	}
+
+	fmt.Println(result)
+	// Output: -1
+}
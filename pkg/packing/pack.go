@@ -0,0 +1,88 @@
+package packing
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+)
+
+// Size is the number of items in a pack
+type Size int64
+
+// Pack is a pack size with the limits of its count in a combination
+type Pack struct {
+	// Size is the number of items in a pack, greater than zero
+	Size Size `json:"size"`
+	// MinCount is the number of packs of the size every combination must hold
+	MinCount int64 `json:"minCount,omitempty"`
+	// MaxCount is the largest number of packs of the size a combination can hold, zero means no limit
+	MaxCount int64 `json:"maxCount,omitempty"`
+}
+
+// Mode represents how the shipped items may differ from the order size
+type Mode string
+
+const (
+	// Overship ships at least the order size, with the least items above it and then the fewest packs
+	Overship Mode = "overship"
+	// Undership ships at most the order size, with the least items below it and then the fewest packs,
+	// and backorders the rest
+	Undership Mode = "undership"
+)
+
+// Combination maps a pack size to the number of packs of the size
+type Combination map[Size]int64
+
+// Totals returns the number of items and the number of packs in the combination,
+// ErrOverflow if either does not fit into int64
+func (c Combination) Totals() (int64, int64, error) {
+	var items, count int64
+	for size, packCount := range c {
+		packItems, ok := mulInt64(int64(size), packCount)
+		if !ok {
+			return 0, 0, ErrOverflow
+		}
+
+		if items, ok = addInt64(items, packItems); !ok {
+			return 0, 0, ErrOverflow
+		}
+
+		if count, ok = addInt64(count, packCount); !ok {
+			return 0, 0, ErrOverflow
+		}
+	}
+
+	return items, count, nil
+}
+
+// sortPacks validates packs and returns their copy sorted in descending order by size
+func sortPacks(packs []Pack) ([]Pack, error) {
+	if len(packs) == 0 {
+		return nil, ErrNoPacks
+	}
+
+	result := slices.Clone(packs)
+	slices.SortFunc(result, func(a, b Pack) int {
+		return cmp.Compare(b.Size, a.Size)
+	})
+
+	for i, pack := range result {
+		if pack.Size <= 0 {
+			return nil, fmt.Errorf("%w: pack size %d is not greater than zero", ErrInvalidPack, pack.Size)
+		}
+
+		if pack.MinCount < 0 || pack.MaxCount < 0 || (pack.MaxCount > 0 && pack.MaxCount < pack.MinCount) {
+			return nil, fmt.Errorf(
+				"%w: count limits of pack size %d are negative or the maximum is below the minimum",
+				ErrInvalidPack,
+				pack.Size,
+			)
+		}
+
+		if i > 0 && result[i-1].Size == pack.Size {
+			return nil, fmt.Errorf("%w: pack size %d is listed twice", ErrInvalidPack, pack.Size)
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,59 @@
+package packing
+
+import "cmp"
+
+// Compare ranks two combinations of packs for an order by the rules the solvers follow. A combination that
+// ships at least the order in the overship mode, or at most the order in the undership mode, ranks first,
+// then the one whose items are closer to the order and then the one with fewer packs.
+// It returns a negative number when a ranks before b, a positive number when b ranks before a
+// and zero when they are equally good. The count limits of the packs are not checked.
+func Compare(orderSize int64, mode Mode, a, b Combination) (int, error) {
+	if mode != Overship && mode != Undership {
+		return 0, ErrInvalidMode
+	}
+
+	aItems, aCount, err := a.Totals()
+	if err != nil {
+		return 0, err
+	}
+
+	bItems, bCount, err := b.Totals()
+	if err != nil {
+		return 0, err
+	}
+
+	aFits, bFits := fitsOrder(orderSize, mode, aItems), fitsOrder(orderSize, mode, bItems)
+	if aFits != bFits {
+		if aFits {
+			return -1, nil
+		}
+
+		return 1, nil
+	}
+
+	// the items and the order size are not negative, so their distance does not overflow
+	if c := cmp.Compare(distance(orderSize, aItems), distance(orderSize, bItems)); c != 0 {
+		return c, nil
+	}
+
+	return cmp.Compare(aCount, bCount), nil
+}
+
+// fitsOrder reports whether the items ship at least the order in the overship mode or at most the order
+// in the undership mode
+func fitsOrder(orderSize int64, mode Mode, items int64) bool {
+	if mode == Undership {
+		return items <= orderSize
+	}
+
+	return items >= orderSize
+}
+
+// distance returns the number of items above or below the order
+func distance(orderSize, items int64) int64 {
+	if items > orderSize {
+		return items - orderSize
+	}
+
+	return orderSize - items
+}
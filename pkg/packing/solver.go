@@ -0,0 +1,571 @@
+package packing
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"math"
+	"slices"
+)
+
+// stepCheckInterval is the number of table cells filled between two checks of the budget
+const stepCheckInterval = 4096
+
+// unreachable marks an amount that cannot be composed of whole packs
+const unreachable = math.MaxInt32
+
+// noCountLimit is the largest count of packs of a size with no maximum count
+const noCountLimit = math.MaxInt64
+
+// Option configures a Solver
+type Option func(*options)
+
+// options holds the settings of a Solver
+type options struct {
+	// mode is the shipment mode of the solver
+	mode Mode
+	// budget bounds the work of the solver, nil means no limit
+	budget *Budget
+}
+
+// WithMode sets the shipment mode of a solver, Overship by default
+func WithMode(mode Mode) Option {
+	return func(o *options) {
+		o.mode = mode
+	}
+}
+
+// WithBudget bounds the work of a solver by a budget it may share with other solvers, no limit by default
+func WithBudget(budget *Budget) Option {
+	return func(o *options) {
+		o.budget = budget
+	}
+}
+
+// Solve returns the best combination of packs for an order, see Solver
+func Solve(packs []Pack, orderSize int64, opts ...Option) (Combination, error) {
+	solver, err := NewSolver(packs, orderSize, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return solver.Solve(orderSize)
+}
+
+// Solver finds the combinations of packs that follow Rules #2 and #3 for orders up to a maximum size.
+//
+// All pack sizes are divided by their greatest common divisor first, as only multiples of it can be shipped.
+// Large orders are then reduced by whole largest packs: a combination with the fewest packs never holds
+// largest-1 or more smaller packs, because among them there is always a subset whose size is a multiple of
+// the largest pack and which can be swapped for fewer largest packs. So the smaller packs of the best
+// combination never add up to more than bound = (largest-1) * secondLargest items, and any order above
+// the bound contains (order-bound)/largest largest packs. The remainder is solved exactly by dynamic programming.
+//
+// The minimum counts of the packs are taken out of every order up front, so that only the packs above the minimums
+// are searched for. Orders are not reduced when the count of the largest packs is limited, as there may not be
+// enough of them, and the dynamic programming honours the maximum counts of the other packs.
+//
+// In the undership mode the solver ships the most items up to the order instead, and then as few packs as possible.
+// The same reduction holds: the best amount is above order-largest, so adding another largest pack would
+// exceed the order, and its combination with the fewest packs holds at least (order-bound)/largest largest packs.
+//
+// The memory of a solver grows with the reduced maximum order size times the number of pack sizes,
+// a Budget with a maximum number of steps bounds it.
+type Solver struct {
+	// budget is the budget the solver accounts its steps to
+	budget *Budget
+	// packsList is the list of available packs sorted in descending order by pack size
+	packsList []Pack
+	// undership reports whether the solver ships at most the order instead of at least the order
+	undership bool
+	// maxOrderSize is the largest order the solver solves
+	maxOrderSize int64
+	// divisor is the greatest common divisor of the pack sizes
+	divisor int64
+	// sizes are the pack sizes divided by the divisor
+	sizes []int64
+	// maxCounts are the largest counts of packs of each size on top of their minimum counts, noCountLimit if unlimited
+	maxCounts []int64
+	// mandatory is the amount of items in the minimum counts of packs in units of the divisor
+	mandatory int64
+	// bound is the largest order that is not reduced by the largest packs, -1 if orders are never reduced
+	bound int64
+	// stages[i][amount] is the fewest packs of sizes[:i] that make up the amount
+	stages [][]int32
+}
+
+// NewSolver prepares a solver for orders up to maxOrderSize with the packs in any order.
+// It returns ErrInfeasible when maxOrderSize is larger than the packs can hold within their maximum counts or,
+// in the undership mode, smaller than the minimum counts of the packs.
+func NewSolver(packs []Pack, maxOrderSize int64, opts ...Option) (*Solver, error) {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	o.mode = cmp.Or(o.mode, Overship)
+	if o.mode != Overship && o.mode != Undership {
+		return nil, fmt.Errorf("%w %q", ErrInvalidMode, o.mode)
+	}
+
+	if o.budget == nil {
+		o.budget = NewBudget(context.Background(), 0)
+	}
+
+	if maxOrderSize <= 0 {
+		return nil, fmt.Errorf("%w: maximum order size %d is not greater than zero", ErrInvalidOrderSize, maxOrderSize)
+	}
+
+	packsList, err := sortPacks(packs)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Solver{
+		budget:       o.budget,
+		packsList:    packsList,
+		undership:    o.mode == Undership,
+		maxOrderSize: maxOrderSize,
+		divisor:      int64(packsList[0].Size),
+		sizes:        make([]int64, len(packsList)),
+		maxCounts:    make([]int64, len(packsList)),
+		bound:        -1,
+	}
+
+	for _, pack := range packsList[1:] {
+		s.divisor = gcd(s.divisor, int64(pack.Size))
+	}
+
+	// capacity is the largest amount of items above the minimum counts, -1 if it is unlimited
+	capacity := int64(0)
+	// extraSize is the size of the pack the best combination never overshoots the order by
+	extraSize := int64(0)
+	for i, pack := range packsList {
+		s.sizes[i] = int64(pack.Size) / s.divisor
+
+		items, ok := mulInt64(s.sizes[i], pack.MinCount)
+		if !ok {
+			return nil, ErrOverflow
+		}
+
+		if s.mandatory, ok = addInt64(s.mandatory, items); !ok {
+			return nil, ErrOverflow
+		}
+
+		s.maxCounts[i] = noCountLimit
+		if pack.MaxCount > 0 {
+			s.maxCounts[i] = pack.MaxCount - pack.MinCount
+		}
+
+		if s.maxCounts[i] == noCountLimit {
+			capacity = -1
+			extraSize = s.sizes[i]
+		} else if capacity >= 0 {
+			if items, ok = mulInt64(s.sizes[i], s.maxCounts[i]); !ok {
+				return nil, ErrOverflow
+			}
+
+			if capacity, ok = addInt64(capacity, items); !ok {
+				return nil, ErrOverflow
+			}
+		}
+	}
+
+	if extraSize == 0 {
+		// no pack is unlimited: the best combination would hold no pack it could leave out, so it overshoots
+		// the order by less than the largest pack
+		extraSize = s.sizes[0]
+	}
+
+	if s.maxCounts[0] == noCountLimit {
+		if len(s.sizes) == 1 {
+			s.bound = 0
+		} else if bound, ok := mulInt64(s.sizes[0]-1, s.sizes[1]); ok {
+			s.bound = bound
+		}
+	}
+
+	if s.undership && maxOrderSize/s.divisor < s.mandatory {
+		return nil, fmt.Errorf(
+			"%w: order size %d is smaller than the minimum counts of the packs hold",
+			ErrInfeasible,
+			maxOrderSize,
+		)
+	}
+
+	bulkCount, maxOrder := s.reduce(maxOrderSize)
+	if bulkCount > 0 {
+		// a smaller order above the bound is reduced to less than the bound plus the largest pack,
+		// possibly more than the maximum order is reduced to
+		maxOrder = s.bound + s.sizes[0] - 1
+	}
+
+	if !s.undership && capacity >= 0 && maxOrder > capacity {
+		return nil, fmt.Errorf(
+			"%w: order size %d is larger than the packs can hold within their maximum counts",
+			ErrInfeasible,
+			maxOrderSize,
+		)
+	}
+
+	tableSize := maxOrder + extraSize
+	if s.undership {
+		tableSize = maxOrder + 1
+	}
+	if capacity >= 0 {
+		tableSize = min(tableSize, capacity+1)
+	}
+
+	if tableSize > unreachable {
+		return nil, ErrBudgetExceeded
+	}
+
+	stages, err := s.fillStages(tableSize)
+	if err != nil {
+		return nil, err
+	}
+	s.stages = stages
+
+	return s, nil
+}
+
+// Divisor returns the greatest common divisor of the pack sizes, only its multiples can be shipped
+func (s *Solver) Divisor() int64 {
+	return s.divisor
+}
+
+// CountLimited reports whether any pack has a minimum or a maximum count
+func (s *Solver) CountLimited() bool {
+	if s.mandatory > 0 {
+		return true
+	}
+
+	for _, maxCount := range s.maxCounts {
+		if maxCount != noCountLimit {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Solve returns the best combination of packs for an order of at most the maximum size of the solver.
+// It takes time linear in the number of pack sizes and does not account to the budget.
+// In the undership mode it returns ErrInfeasible for an order smaller than the minimum counts of the packs hold.
+func (s *Solver) Solve(orderSize int64) (Combination, error) {
+	if err := s.checkOrderSize(orderSize); err != nil {
+		return nil, err
+	}
+
+	bulkCount, order := s.reduce(orderSize)
+
+	return s.combination(s.counts(order), bulkCount), nil
+}
+
+// Alternatives returns up to limit, at least one, combinations of packs that follow Rules #2 and #3 equally well
+// for an order of at most the maximum size of the solver, starting with the combination returned by Solve.
+// Every best combination of a reduced order holds the same largest packs it was reduced by,
+// so it is enough to look for the combinations of the remaining order.
+func (s *Solver) Alternatives(orderSize int64, limit int) ([]Combination, error) {
+	if err := s.checkOrderSize(orderSize); err != nil {
+		return nil, err
+	}
+
+	limit = max(limit, 1)
+	bulkCount, order := s.reduce(orderSize)
+	if order == 0 {
+		return []Combination{s.combination(make([]int64, len(s.sizes)), bulkCount)}, nil
+	}
+
+	amount := s.bestAmount(order)
+
+	var result []Combination
+	counts := make([]int64, len(s.sizes))
+
+	// walk takes every count of packs of sizes[i] that still leaves the fewest packs of the smaller sizes
+	var walk func(i int, amount int64, target int32) error
+	walk = func(i int, amount int64, target int32) error {
+		if i < 0 {
+			result = append(result, s.combination(counts, bulkCount))
+
+			return nil
+		}
+
+		for j := int64(0); j*s.sizes[i] <= amount && j <= int64(target) && j <= s.maxCounts[i] && len(result) < limit; j++ {
+			if err := s.budget.Step(1); err != nil {
+				return err
+			}
+
+			if s.stages[i][amount-j*s.sizes[i]] == target-int32(j) {
+				counts[i] = j
+				if err := walk(i-1, amount-j*s.sizes[i], target-int32(j)); err != nil {
+					return err
+				}
+			}
+		}
+		counts[i] = 0
+
+		return nil
+	}
+
+	if err := walk(len(s.sizes)-1, amount, s.stages[len(s.sizes)][amount]); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// LargestUnmatchableOrder returns the largest order no combination matches exactly (the Frobenius number)
+// using the round-robin algorithm of Böcker and Lipták. It reports false when there is no such largest order,
+// because the pack sizes have a common divisor or the packs have count limits. For each remainder modulo
+// the smallest size the algorithm finds the smallest amount that can be made up, every larger amount with
+// the same remainder adds whole smallest packs to it.
+func (s *Solver) LargestUnmatchableOrder() (int64, bool, error) {
+	if s.divisor != 1 || s.CountLimited() {
+		return 0, false, nil
+	}
+
+	smallest := s.sizes[len(s.sizes)-1]
+	if !s.budget.Allows(smallest * int64(len(s.sizes))) {
+		return 0, false, ErrBudgetExceeded
+	}
+
+	// smallestAmounts[r] is the smallest amount with the remainder r that can be made up, -1 if none is known yet
+	smallestAmounts := make([]int64, smallest)
+	for i := range smallestAmounts {
+		smallestAmounts[i] = -1
+	}
+	smallestAmounts[0] = 0
+
+	for _, size := range s.sizes[:len(s.sizes)-1] {
+		cycles := gcd(smallest, size)
+		for cycle := int64(0); cycle < cycles; cycle++ {
+			if err := s.budget.Step(smallest / cycles); err != nil {
+				return 0, false, err
+			}
+
+			// start the cycle of remainders from its smallest known amount
+			remainder := int64(-1)
+			for r := cycle; r < smallest; r += cycles {
+				if smallestAmounts[r] >= 0 && (remainder < 0 || smallestAmounts[r] < smallestAmounts[remainder]) {
+					remainder = r
+				}
+			}
+			if remainder < 0 {
+				continue
+			}
+
+			for i := int64(0); i < smallest/cycles; i++ {
+				next := (remainder + size) % smallest
+				amount, ok := addInt64(smallestAmounts[remainder], size)
+				if !ok {
+					return 0, false, ErrOverflow
+				}
+
+				if smallestAmounts[next] < 0 || amount < smallestAmounts[next] {
+					smallestAmounts[next] = amount
+				}
+				remainder = next
+			}
+		}
+	}
+
+	return max(slices.Max(smallestAmounts)-smallest, 0), true, nil
+}
+
+// checkOrderSize checks that an order size is positive and at most the maximum size of the solver
+// and, in the undership mode, that it holds the minimum counts of the packs
+func (s *Solver) checkOrderSize(orderSize int64) error {
+	if orderSize <= 0 || orderSize > s.maxOrderSize {
+		return fmt.Errorf(
+			"%w: order size %d is not between 1 and the maximum order size %d of the solver",
+			ErrInvalidOrderSize,
+			orderSize,
+			s.maxOrderSize,
+		)
+	}
+
+	if s.undership && orderSize/s.divisor < s.mandatory {
+		return fmt.Errorf(
+			"%w: order size %d is smaller than the minimum counts of the packs hold",
+			ErrInfeasible,
+			orderSize,
+		)
+	}
+
+	return nil
+}
+
+// reduce returns the number of largest packs that are part of the best combination for the order
+// and the remaining order, both in units of the divisor
+func (s *Solver) reduce(orderSize int64) (int64, int64) {
+	// round the order up, or down in the undership mode, to the closest amount that can be shipped
+	order := orderSize / s.divisor
+	if orderSize%s.divisor != 0 && !s.undership {
+		order++
+	}
+
+	order = max(order-s.mandatory, 0)
+
+	if s.bound < 0 || order <= s.bound {
+		return 0, order
+	}
+
+	bulkCount := (order - s.bound) / s.sizes[0]
+
+	return bulkCount, order - bulkCount*s.sizes[0]
+}
+
+// bestAmount returns the amount closest to an order in units of the divisor that can be made up of packs,
+// at least the order or, in the undership mode, at most the order
+func (s *Solver) bestAmount(order int64) int64 {
+	last := s.stages[len(s.sizes)]
+	if s.undership {
+		amount := min(order, int64(len(last))-1)
+		for last[amount] == unreachable {
+			amount--
+		}
+
+		return amount
+	}
+
+	amount := order
+	for last[amount] == unreachable {
+		amount++
+	}
+
+	return amount
+}
+
+// combination maps the counts of packs of each size, their minimum counts and the largest packs the order
+// was reduced by to pack sizes
+func (s *Solver) combination(counts []int64, bulkCount int64) Combination {
+	result := make(Combination)
+	for i, count := range counts {
+		count += s.packsList[i].MinCount
+		if i == 0 {
+			count += bulkCount
+		}
+
+		if count > 0 {
+			result[s.packsList[i].Size] = count
+		}
+	}
+
+	return result
+}
+
+// counts returns the count of packs of each size that follow Rules #2 and #3 for an order in units of the divisor
+func (s *Solver) counts(order int64) []int64 {
+	counts := make([]int64, len(s.sizes))
+	if order == 0 {
+		return counts
+	}
+
+	amount := s.bestAmount(order)
+
+	// walk the stages back to find how many packs of each size make up the amount
+	for i := len(s.sizes) - 1; i >= 0; i-- {
+		target := s.stages[i+1][amount]
+		for s.stages[i][amount] != target {
+			amount -= s.sizes[i]
+			target--
+			counts[i]++
+		}
+	}
+
+	return counts
+}
+
+// fillStages fills the table of the fewest packs of the sizes of the solver, at most maxCounts of each,
+// that make up each amount below tableSize
+func (s *Solver) fillStages(tableSize int64) ([][]int32, error) {
+	if !s.budget.Allows(tableSize * int64(len(s.sizes)+1)) {
+		return nil, ErrBudgetExceeded
+	}
+
+	stages := make([][]int32, len(s.sizes)+1)
+	stages[0] = make([]int32, tableSize)
+	for amount := int64(1); amount < tableSize; amount++ {
+		stages[0][amount] = unreachable
+	}
+
+	for i, size := range s.sizes {
+		prev := stages[i]
+		cur := make([]int32, tableSize)
+		if s.maxCounts[i] != noCountLimit {
+			if err := s.fillBoundedStage(prev, cur, size, s.maxCounts[i]); err != nil {
+				return nil, err
+			}
+			stages[i+1] = cur
+
+			continue
+		}
+
+		for amount := int64(0); amount < tableSize; amount++ {
+			if amount%stepCheckInterval == 0 {
+				if err := s.budget.Step(min(stepCheckInterval, tableSize-amount)); err != nil {
+					return nil, err
+				}
+			}
+
+			cur[amount] = prev[amount]
+			if amount >= size && cur[amount-size] != unreachable && cur[amount-size]+1 < cur[amount] {
+				cur[amount] = cur[amount-size] + 1
+			}
+		}
+		stages[i+1] = cur
+	}
+
+	return stages, nil
+}
+
+// fillBoundedStage fills cur with the fewest packs that make up each amount with the packs of prev
+// and at most maxCount more packs of the size. Amounts k*size+r take j packs of the size on top of prev[(k-j)*size+r],
+// so for each remainder r it keeps the smallest prev[k'*size+r]-k' of the last maxCount+1 values of k' in a queue.
+func (s *Solver) fillBoundedStage(prev, cur []int32, size, maxCount int64) error {
+	tableSize := int64(len(cur))
+	// queue holds the values of k' in increasing order of both k' and prev[k'*size+r]-k'
+	queue := make([]int64, 0, min(maxCount+1, tableSize/size+1))
+	filled := int64(0)
+	for r := int64(0); r < min(size, tableSize); r++ {
+		queue = queue[:0]
+		for k := int64(0); k*size+r < tableSize; k++ {
+			if filled%stepCheckInterval == 0 {
+				if err := s.budget.Step(min(stepCheckInterval, tableSize-filled)); err != nil {
+					return err
+				}
+			}
+			filled++
+
+			if len(queue) > 0 && queue[0] < k-maxCount {
+				queue = queue[1:]
+			}
+
+			amount := k*size + r
+			if prev[amount] != unreachable {
+				value := int64(prev[amount]) - k
+				for len(queue) > 0 && int64(prev[queue[len(queue)-1]*size+r])-queue[len(queue)-1] >= value {
+					queue = queue[:len(queue)-1]
+				}
+				queue = append(queue, k)
+			}
+
+			cur[amount] = unreachable
+			if len(queue) > 0 {
+				cur[amount] = prev[queue[0]*size+r] + int32(k-queue[0])
+			}
+		}
+	}
+
+	return nil
+}
+
+// gcd returns the greatest common divisor of two positive numbers
+func gcd(a, b int64) int64 {
+	for b != 0 {
+		a, b = b, a%b
+	}
+
+	return a
+}
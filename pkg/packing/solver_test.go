@@ -0,0 +1,480 @@
+package packing
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"math"
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// testAlternatives is the largest number of alternatives the tests look for
+const testAlternatives = 1000
+
+func TestSolve(t *testing.T) {
+	packs := []Pack{{Size: 250}, {Size: 500}, {Size: 1000}, {Size: 2000}, {Size: 5000}}
+
+	testCases := []struct {
+		orderSize int64
+		mode      Mode
+		expected  Combination
+	}{
+		{orderSize: 1, expected: Combination{250: 1}},
+		{orderSize: 250, expected: Combination{250: 1}},
+		{orderSize: 251, expected: Combination{500: 1}},
+		{orderSize: 501, expected: Combination{500: 1, 250: 1}},
+		{orderSize: 12001, expected: Combination{5000: 2, 2000: 1, 250: 1}},
+		{orderSize: 12001, mode: Undership, expected: Combination{5000: 2, 2000: 1}},
+		{orderSize: 1_000_000_000_000_001, expected: Combination{5000: 200_000_000_000, 250: 1}},
+	}
+
+	for _, tc := range testCases {
+		result, err := Solve(packs, tc.orderSize, WithMode(tc.mode))
+		require.NoError(t, err)
+		require.Equal(t, tc.expected, result, "order size %d, mode %s", tc.orderSize, tc.mode)
+	}
+}
+
+func TestNewSolverErrors(t *testing.T) {
+	_, err := NewSolver(nil, 10)
+	require.ErrorIs(t, err, ErrNoPacks)
+
+	_, err = NewSolver([]Pack{{Size: 0}}, 10)
+	require.ErrorIs(t, err, ErrInvalidPack)
+
+	_, err = NewSolver([]Pack{{Size: 5}, {Size: 5}}, 10)
+	require.ErrorIs(t, err, ErrInvalidPack)
+
+	_, err = NewSolver([]Pack{{Size: 5, MinCount: 2, MaxCount: 1}}, 10)
+	require.ErrorIs(t, err, ErrInvalidPack)
+
+	_, err = NewSolver([]Pack{{Size: 5}}, 0)
+	require.ErrorIs(t, err, ErrInvalidOrderSize)
+
+	_, err = NewSolver([]Pack{{Size: 5}}, 10, WithMode("exact"))
+	require.ErrorIs(t, err, ErrInvalidMode)
+
+	_, err = NewSolver([]Pack{{Size: 5, MaxCount: 1}}, 10)
+	require.ErrorIs(t, err, ErrInfeasible)
+
+	_, err = NewSolver([]Pack{{Size: 5, MinCount: 3}}, 10, WithMode(Undership))
+	require.ErrorIs(t, err, ErrInfeasible)
+
+	solver, err := NewSolver([]Pack{{Size: 5}}, 10)
+	require.NoError(t, err)
+
+	_, err = solver.Solve(11)
+	require.ErrorIs(t, err, ErrInvalidOrderSize)
+
+	_, err = solver.Alternatives(0, 1)
+	require.ErrorIs(t, err, ErrInvalidOrderSize)
+
+	// A solver shared by many orders never ships more than an order below the minimum counts in the undership mode
+	solver, err = NewSolver([]Pack{{Size: 5, MinCount: 3}, {Size: 2}}, 100, WithMode(Undership))
+	require.NoError(t, err)
+
+	for _, orderSize := range []int64{1, 4, 14} {
+		_, err = solver.Solve(orderSize)
+		require.ErrorIs(t, err, ErrInfeasible, orderSize)
+
+		_, err = solver.Alternatives(orderSize, 3)
+		require.ErrorIs(t, err, ErrInfeasible, orderSize)
+	}
+
+	combination, err := solver.Solve(15)
+	require.NoError(t, err)
+	require.Equal(t, Combination{5: 3}, combination)
+
+	_, _, err = Combination{math.MaxInt64 / 2: 3}.Totals()
+	require.ErrorIs(t, err, ErrOverflow)
+}
+
+func TestSolverBudget(t *testing.T) {
+	packs := []Pack{{Size: 250}, {Size: 500}, {Size: 1000}}
+
+	// Test with a cancelled context
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := NewSolver(packs, 12001, WithBudget(NewBudget(ctx, 0)))
+	require.ErrorIs(t, err, context.Canceled)
+
+	// Test with an exhausted work budget
+	_, err = NewSolver(packs, 12001, WithBudget(NewBudget(context.Background(), 1)))
+	require.ErrorIs(t, err, ErrBudgetExceeded)
+
+	// Test that solvers sharing a budget account to it together
+	budget := NewBudget(context.Background(), 0)
+	_, err = NewSolver(packs, 12001, WithBudget(budget))
+	require.NoError(t, err)
+
+	steps := budget.Steps()
+	require.Positive(t, steps)
+
+	_, err = NewSolver(packs, 12001, WithBudget(budget))
+	require.NoError(t, err)
+	require.Equal(t, 2*steps, budget.Steps())
+	require.True(t, budget.Allows(math.MaxInt64))
+
+	budget = NewBudget(context.Background(), 3*steps/2)
+	_, err = NewSolver(packs, 12001, WithBudget(budget))
+	require.NoError(t, err)
+	require.False(t, budget.Allows(steps))
+
+	_, err = NewSolver(packs, 12001, WithBudget(budget))
+	require.ErrorIs(t, err, ErrBudgetExceeded)
+}
+
+func TestSolverLargestUnmatchableOrder(t *testing.T) {
+	solver, err := NewSolver([]Pack{{Size: 6}, {Size: 9}, {Size: 20}}, 50)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), solver.Divisor())
+
+	largest, ok, err := solver.LargestUnmatchableOrder()
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, int64(43), largest)
+
+	// Test with a common divisor and with count limits
+	solver, err = NewSolver([]Pack{{Size: 250}, {Size: 500}}, 1000)
+	require.NoError(t, err)
+	require.Equal(t, int64(250), solver.Divisor())
+
+	_, ok, err = solver.LargestUnmatchableOrder()
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	solver, err = NewSolver([]Pack{{Size: 6, MaxCount: 10}, {Size: 9}}, 50)
+	require.NoError(t, err)
+	require.True(t, solver.CountLimited())
+
+	_, ok, err = solver.LargestUnmatchableOrder()
+	require.NoError(t, err)
+	require.False(t, ok)
+}
+
+func TestCompare(t *testing.T) {
+	testCases := []struct {
+		name     string
+		mode     Mode
+		a, b     Combination
+		expected int
+	}{
+		{name: "fewer items above the order", mode: Overship, a: Combination{500: 1}, b: Combination{250: 3}, expected: -1},
+		{name: "fewer packs", mode: Overship, a: Combination{500: 1}, b: Combination{250: 2}, expected: -1},
+		{name: "at least the order", mode: Overship, a: Combination{250: 1}, b: Combination{500: 1}, expected: 1},
+		{name: "equally good", mode: Overship, a: Combination{250: 2}, b: Combination{300: 1, 200: 1}, expected: 0},
+		{name: "more items below the order", mode: Undership, a: Combination{250: 1}, b: Combination{250: 1, 100: 1}, expected: 1},
+		{name: "at most the order", mode: Undership, a: Combination{250: 1}, b: Combination{500: 1}, expected: -1},
+		{name: "fewer packs below the order", mode: Undership, a: Combination{250: 1}, b: Combination{125: 2}, expected: -1},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := Compare(400, tc.mode, tc.a, tc.b)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, result)
+
+			result, err = Compare(400, tc.mode, tc.b, tc.a)
+			require.NoError(t, err)
+			require.Equal(t, -tc.expected, result)
+		})
+	}
+
+	_, err := Compare(400, "exact", Combination{}, Combination{})
+	require.ErrorIs(t, err, ErrInvalidMode)
+}
+
+func TestCheckedArithmetic(t *testing.T) {
+	sum, ok := CheckedAdd(math.MaxInt64-1, 1)
+	require.True(t, ok)
+	require.Equal(t, int64(math.MaxInt64), sum)
+
+	_, ok = CheckedAdd(math.MaxInt64, 1)
+	require.False(t, ok)
+
+	_, ok = CheckedAdd(math.MinInt64, -1)
+	require.False(t, ok)
+
+	product, ok := CheckedMul(math.MaxInt64/2, 2)
+	require.True(t, ok)
+	require.Equal(t, int64(math.MaxInt64-1), product)
+
+	_, ok = CheckedMul(math.MaxInt64/2, 3)
+	require.False(t, ok)
+
+	_, ok = CheckedMul(-1, math.MinInt64)
+	require.False(t, ok)
+}
+
+func TestSolveMatchesReference(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 200; i++ {
+		sizes := make(map[Size]bool)
+		for len(sizes) < 1+rnd.Intn(4) {
+			sizes[Size(1+rnd.Intn(40))] = true
+		}
+
+		packList := make([]Pack, 0, len(sizes))
+		for size := range sizes {
+			packList = append(packList, Pack{Size: size})
+		}
+		slices.SortFunc(packList, func(a, b Pack) int {
+			return cmp.Compare(b.Size, a.Size)
+		})
+
+		orderSize := int64(1 + rnd.Intn(3000))
+
+		packs, err := Solve(packList, orderSize)
+		require.NoError(t, err)
+
+		amount, count, err := packs.Totals()
+		require.NoError(t, err)
+
+		expectedAmount, expectedCount := solveByReference(orderSize, packList)
+		require.Equal(t, expectedAmount, amount, "packs %v, order size %d", packList, orderSize)
+		require.Equal(t, expectedCount, count, "packs %v, order size %d", packList, orderSize)
+
+		// Test that every alternative is as good as the best combination, starting with it
+		solver, err := NewSolver(packList, orderSize)
+		require.NoError(t, err)
+
+		alternatives, err := solver.Alternatives(orderSize, testAlternatives)
+		require.NoError(t, err)
+		require.Equal(t, packs, alternatives[0])
+
+		for _, alternative := range alternatives {
+			alternativeAmount, alternativeCount, err := alternative.Totals()
+			require.NoError(t, err)
+			require.Equal(t, expectedAmount, alternativeAmount)
+			require.Equal(t, expectedCount, alternativeCount)
+		}
+	}
+}
+
+func TestSolverMatchesSolveBelowMaximum(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 50; i++ {
+		sizes := make(map[Size]bool)
+		for len(sizes) < 1+rnd.Intn(4) {
+			sizes[Size(1+rnd.Intn(40))] = true
+		}
+
+		packList := make([]Pack, 0, len(sizes))
+		for size := range sizes {
+			packList = append(packList, Pack{Size: size})
+		}
+
+		// Test that a solver of a maximum order size solves every smaller order as if it was the maximum
+		maxOrderSize := int64(1 + rnd.Intn(300))
+		for _, mode := range []Mode{Overship, Undership} {
+			solver, err := NewSolver(packList, maxOrderSize, WithMode(mode))
+			if errors.Is(err, ErrInfeasible) {
+				continue
+			}
+			require.NoError(t, err)
+
+			for orderSize := int64(1); orderSize <= maxOrderSize; orderSize++ {
+				want, wantErr := Solve(packList, orderSize, WithMode(mode))
+				packs, err := solver.Solve(orderSize)
+				if wantErr != nil {
+					require.ErrorIs(t, wantErr, ErrInfeasible)
+					require.ErrorIs(t, err, ErrInfeasible, "packs %v, order size %d", packList, orderSize)
+
+					continue
+				}
+
+				require.NoError(t, err, "packs %v, order size %d", packList, orderSize)
+				require.Equal(t, want, packs, "packs %v, order size %d", packList, orderSize)
+			}
+		}
+	}
+
+	// Test an order that is reduced by fewer largest packs than the maximum order size
+	solver, err := NewSolver([]Pack{{Size: 250}, {Size: 500}}, 550)
+	require.NoError(t, err)
+
+	packs, err := solver.Solve(251)
+	require.NoError(t, err)
+	require.Equal(t, Combination{500: 1}, packs)
+}
+
+// solveByReference returns the least amount of items to ship for an order and the fewest packs for it
+// by trying every amount from the order size up
+func solveByReference(orderSize int64, packList []Pack) (int64, int64) {
+	limit := orderSize + int64(packList[0].Size)
+	fewest := make([]int64, limit+1)
+	for amount := int64(1); amount <= limit; amount++ {
+		fewest[amount] = -1
+		for _, pack := range packList {
+			size := int64(pack.Size)
+			if amount >= size && fewest[amount-size] >= 0 &&
+				(fewest[amount] < 0 || fewest[amount-size]+1 < fewest[amount]) {
+				fewest[amount] = fewest[amount-size] + 1
+			}
+		}
+	}
+
+	for amount := orderSize; ; amount++ {
+		if fewest[amount] >= 0 {
+			return amount, fewest[amount]
+		}
+	}
+}
+
+func TestSolveWithCountLimitsMatchesReference(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 300; i++ {
+		sizes := make(map[Size]bool)
+		for len(sizes) < 1+rnd.Intn(3) {
+			sizes[Size(1+rnd.Intn(30))] = true
+		}
+
+		packList := make([]Pack, 0, len(sizes))
+		for size := range sizes {
+			pack := Pack{Size: size, MinCount: int64(rnd.Intn(2))}
+			if rnd.Intn(2) == 0 {
+				pack.MaxCount = pack.MinCount + int64(rnd.Intn(4))
+			}
+			packList = append(packList, pack)
+		}
+		slices.SortFunc(packList, func(a, b Pack) int {
+			return cmp.Compare(b.Size, a.Size)
+		})
+
+		orderSize := int64(1 + rnd.Intn(200))
+
+		for _, mode := range []Mode{Overship, Undership} {
+			expectedAmount, expectedCount, feasible := solveWithCountLimitsByReference(orderSize, packList, mode)
+
+			solver, err := NewSolver(packList, orderSize, WithMode(mode))
+			if !feasible {
+				require.ErrorIs(t, err, ErrInfeasible,
+					"packs %v, order size %d, mode %s", packList, orderSize, mode)
+
+				continue
+			}
+			require.NoError(t, err)
+
+			alternatives, err := solver.Alternatives(orderSize, testAlternatives)
+			require.NoError(t, err)
+			best, err := solver.Solve(orderSize)
+			require.NoError(t, err)
+			require.Equal(t, best, alternatives[0])
+
+			for _, alternative := range alternatives {
+				amount, count, err := alternative.Totals()
+				require.NoError(t, err)
+				require.Equal(t, expectedAmount, amount, "packs %v, order size %d, mode %s", packList, orderSize, mode)
+				require.Equal(t, expectedCount, count, "packs %v, order size %d, mode %s", packList, orderSize, mode)
+
+				for _, pack := range packList {
+					require.GreaterOrEqual(t, alternative[pack.Size], pack.MinCount)
+					if pack.MaxCount > 0 {
+						require.LessOrEqual(t, alternative[pack.Size], pack.MaxCount)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestSolveUndershipMatchesReference(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 200; i++ {
+		sizes := make(map[Size]bool)
+		for len(sizes) < 1+rnd.Intn(4) {
+			sizes[Size(1+rnd.Intn(40))] = true
+		}
+
+		packList := make([]Pack, 0, len(sizes))
+		for size := range sizes {
+			packList = append(packList, Pack{Size: size})
+		}
+		slices.SortFunc(packList, func(a, b Pack) int {
+			return cmp.Compare(b.Size, a.Size)
+		})
+
+		// orders above the reduction bound of the solver are checked as well
+		orderSize := int64(1 + rnd.Intn(3000))
+
+		solver, err := NewSolver(packList, orderSize, WithMode(Undership))
+		require.NoError(t, err)
+
+		packs, err := solver.Solve(orderSize)
+		require.NoError(t, err)
+
+		amount, count, err := packs.Totals()
+		require.NoError(t, err)
+
+		expectedAmount, expectedCount := solveUndershipByReference(orderSize, packList)
+		require.Equal(t, expectedAmount, amount, "packs %v, order size %d", packList, orderSize)
+		require.Equal(t, expectedCount, count, "packs %v, order size %d", packList, orderSize)
+	}
+}
+
+// solveUndershipByReference returns the most items up to an order that can be shipped and the fewest packs for it
+// by trying every amount from the order size down
+func solveUndershipByReference(orderSize int64, packList []Pack) (int64, int64) {
+	fewest := make([]int64, orderSize+1)
+	for amount := int64(1); amount <= orderSize; amount++ {
+		fewest[amount] = -1
+		for _, pack := range packList {
+			size := int64(pack.Size)
+			if amount >= size && fewest[amount-size] >= 0 &&
+				(fewest[amount] < 0 || fewest[amount-size]+1 < fewest[amount]) {
+				fewest[amount] = fewest[amount-size] + 1
+			}
+		}
+	}
+
+	for amount := orderSize; ; amount-- {
+		if fewest[amount] >= 0 {
+			return amount, fewest[amount]
+		}
+	}
+}
+
+// solveWithCountLimitsByReference returns the amount of items closest to an order in the shipment mode
+// and the fewest packs for it by trying every count of every pack within its limits,
+// and false if no combination fits the order
+func solveWithCountLimitsByReference(orderSize int64, packList []Pack, mode Mode) (int64, int64, bool) {
+	bestAmount, bestCount := int64(-1), int64(-1)
+
+	var try func(i int, amount, count int64)
+	try = func(i int, amount, count int64) {
+		if i == len(packList) {
+			if mode == Undership {
+				if amount <= orderSize && (amount > bestAmount || (amount == bestAmount && count < bestCount)) {
+					bestAmount, bestCount = amount, count
+				}
+			} else if amount >= orderSize &&
+				(bestAmount < 0 || amount < bestAmount || (amount == bestAmount && count < bestCount)) {
+				bestAmount, bestCount = amount, count
+			}
+
+			return
+		}
+
+		pack := packList[i]
+		maxCount := pack.MaxCount
+		if maxCount == 0 {
+			maxCount = max(pack.MinCount, orderSize/int64(pack.Size)+1)
+		}
+
+		for j := pack.MinCount; j <= maxCount; j++ {
+			try(i+1, amount+j*int64(pack.Size), count+j)
+		}
+	}
+	try(0, 0, 0)
+
+	return bestAmount, bestCount, bestAmount >= 0
+}
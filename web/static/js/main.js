@@ -1,26 +1,51 @@
 document.addEventListener('DOMContentLoaded', function() {
     refreshPackSizes();
+    subscribePackSizes();
 });
 
 function refreshPackSizes() {
     fetch('/api/packs')
         .then(response => response.json())
-        .then(packs => {
-            const packSizesList = document.getElementById('packSizesList');
-            packSizesList.innerHTML = '';
-
-            packs.forEach(pack => {
-                const row = document.createElement('tr');
-                row.innerHTML = `
-                    <td>${pack.size}</td>
-                    <td><button class="btn-delete" onclick="removePackSize(${pack.size})">Remove</button></td>
-                `;
-                packSizesList.appendChild(row);
-            });
-        })
+        .then(renderPackSizes)
         .catch(error => console.error('Error fetching pack sizes:', error));
 }
 
+function renderPackSizes(packs) {
+    const packSizesList = document.getElementById('packSizesList');
+    packSizesList.innerHTML = '';
+
+    packs.forEach(pack => {
+        const row = document.createElement('tr');
+        row.innerHTML = `
+            <td>${pack.size}</td>
+            <td><button class="btn-delete" onclick="removePackSize(${pack.size})">Remove</button></td>
+        `;
+        packSizesList.appendChild(row);
+    });
+}
+
+// Keeps the pack sizes up to date with the changes made in other tabs or by other planners
+function subscribePackSizes() {
+    if (!window.EventSource) {
+        return;
+    }
+
+    const events = new EventSource('/api/packs/events');
+    let connected = false;
+
+    events.addEventListener('open', () => {
+        // changes made while the browser was reconnecting are not sent again, so they are fetched
+        if (connected) {
+            refreshPackSizes();
+        }
+        connected = true;
+    });
+
+    events.addEventListener('packs', event => {
+        renderPackSizes(JSON.parse(event.data).packs);
+    });
+}
+
 function addPackSize() {
     const input = document.getElementById('newPackSize');
     const packSize = parseInt(input.value);
@@ -98,8 +123,11 @@ function calculatePacks() {
 function displayResults(result) {
     const resultSection = document.getElementById('resultSection');
     const resultBody = document.getElementById('resultBody');
+    const toleranceWarning = document.getElementById('toleranceWarning');
 
     resultBody.innerHTML = '';
+    toleranceWarning.style.display = result.toleranceExceeded ? 'block' : 'none';
+    resultSection.classList.toggle('tolerance-exceeded', !!result.toleranceExceeded);
 
     // Sort pack sizes in descending order for display
     const packSizes = Object.keys(result.packs).map(Number).sort((a, b) => b - a);
@@ -115,4 +143,104 @@ function displayResults(result) {
     });
 
     resultSection.style.display = 'block';
-}
\ No newline at end of file
+}
+
+function comparePacks() {
+    const packs = document.getElementById('candidatePacks').value
+        .split(',')
+        .map(value => value.trim())
+        .filter(value => value !== '')
+        .map(value => {
+            const [size, cost] = value.split(':').map(Number);
+            return { size, cost: cost || 0 };
+        });
+    const orderSizes = document.getElementById('compareOrderSizes').value
+        .split(',')
+        .map(value => parseInt(value.trim()))
+        .filter(value => !isNaN(value));
+
+    if (packs.length === 0 || packs.some(pack => !pack.size || isNaN(pack.size) || pack.size <= 0)) {
+        alert('Please enter valid candidate pack sizes (greater than zero)');
+        return;
+    }
+
+    if (orderSizes.length === 0 || orderSizes.some(orderSize => orderSize <= 0)) {
+        alert('Please enter valid order sizes (greater than zero)');
+        return;
+    }
+
+    fetch('/api/comparison', {
+        method: 'POST',
+        headers: {
+            'Content-Type': 'application/json'
+        },
+        body: JSON.stringify({ packs, orderSizes })
+    })
+    .then(response => response.json())
+    .then(result => {
+        if (result.error) {
+            alert(result.error);
+            return;
+        }
+
+        displayComparison(result);
+    })
+    .catch(error => console.error('Error comparing packs:', error));
+}
+
+function formatPacks(packs) {
+    return Object.keys(packs)
+        .map(Number)
+        .sort((a, b) => b - a)
+        .map(packSize => `${packs[packSize]} x ${packSize}`)
+        .join(', ');
+}
+
+function formatDelta(current, candidate) {
+    const delta = candidate - current;
+    if (delta === 0) {
+        return `${candidate}`;
+    }
+
+    const className = delta < 0 ? 'delta-better' : 'delta-worse';
+    const sign = delta > 0 ? '+' : '';
+    return `${candidate} <span class="${className}">(${sign}${delta})</span>`;
+}
+
+function displayComparison(result) {
+    const comparisonBody = document.getElementById('comparisonBody');
+    const comparisonTotalsBody = document.getElementById('comparisonTotalsBody');
+
+    comparisonBody.innerHTML = '';
+    comparisonTotalsBody.innerHTML = '';
+
+    result.orders.forEach(order => {
+        const row = document.createElement('tr');
+        if (order.changed) {
+            row.className = 'row-changed';
+        }
+        row.innerHTML = `
+            <td>${order.orderSize}</td>
+            <td>${formatPacks(order.current.packs)}</td>
+            <td>${formatPacks(order.candidate.packs)}</td>
+            <td>${formatDelta(order.current.shippedItems, order.candidate.shippedItems)}</td>
+            <td>${formatDelta(order.current.packCount, order.candidate.packCount)}</td>
+            <td>${formatDelta(order.current.cost, order.candidate.cost)}</td>
+        `;
+        comparisonBody.appendChild(row);
+    });
+
+    [['Current', result.current], ['Candidate', result.candidate]].forEach(([label, totals]) => {
+        const row = document.createElement('tr');
+        row.innerHTML = `
+            <td>${label}</td>
+            <td>${formatDelta(result.current.shippedItems, totals.shippedItems)}</td>
+            <td>${formatDelta(result.current.overshipment, totals.overshipment)}</td>
+            <td>${formatDelta(result.current.packCount, totals.packCount)}</td>
+            <td>${formatDelta(result.current.cost, totals.cost)}</td>
+        `;
+        comparisonTotalsBody.appendChild(row);
+    });
+
+    document.getElementById('comparisonSection').style.display = 'block';
+}